@@ -13,7 +13,7 @@ import (
 )
 
 type createAccountRequest struct {
-	Currency string `json:"currency" binding:"required,oneof=USD EUR CAD RS"`
+	Currency string `json:"currency" binding:"required,currency"`
 }
 
 type getAccountRequest struct {
@@ -25,21 +25,21 @@ type listAccountsRequest struct {
 	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
 }
 
-type updateAccountRequest struct {
-	ID      int64 `json:"id" binding:"required,min=1"`
-	Balance int64 `json:"balance" binding:"required"`
-}
-
-type deleteAccountRequest struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
-}
-
 func (s *server) createAccount(ctx *gin.Context) {
 	var req createAccountRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
+	if !s.knownCurrencies(ctx, req.Currency) {
+		return
+	}
+
+	if !s.currencies.IsEnabled(req.Currency) {
+		err := fmt.Errorf("currency %s is disabled", req.Currency)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
@@ -49,7 +49,7 @@ func (s *server) createAccount(ctx *gin.Context) {
 		Balance:  0,
 	}
 
-	account, err := s.store.CreateAccount(ctx, arg)
+	account, err := s.store.CreateAccountTx(ctx, arg)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
@@ -62,7 +62,7 @@ func (s *server) createAccount(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, account)
+	ctx.JSON(http.StatusCreated, newAccountResponse(account))
 }
 
 func (s *server) getAccount(ctx *gin.Context) {
@@ -72,24 +72,52 @@ func (s *server) getAccount(ctx *gin.Context) {
 		return
 	}
 
+	account, valid := s.ownedAccount(ctx, req.ID)
+	if !valid {
+		return
+	}
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+// accountResponse shows the ledger balance next to what can be spent of it right now.
+type accountResponse struct {
+	db.Account
+	AvailableBalance int64 `json:"availableBalance"`
+}
+
+func newAccountResponse(account db.Account) accountResponse {
+	return accountResponse{Account: account, AvailableBalance: account.AvailableBalance()}
+}
+
+func newAccountsResponse(accounts []db.Account) []accountResponse {
+	rsp := make([]accountResponse, len(accounts))
+	for i, account := range accounts {
+		rsp[i] = newAccountResponse(account)
+	}
+	return rsp
+}
+
+// ownedAccount loads an account and makes sure it belongs to the authenticated user,
+// writing the error response itself when it does not.
+func (s *server) ownedAccount(ctx *gin.Context, accountID int64) (db.Account, bool) {
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
-	account, err := s.store.GetAccount(ctx, req.ID)
+	account, err := s.store.GetAccount(ctx, accountID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
+			return account, false
 		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return account, false
 	}
 
 	if account.Owner != authPayload.Username {
 		err := errors.New("account does not belong to authenticated user")
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
+		return account, false
 	}
-	ctx.JSON(http.StatusOK, account)
+	return account, true
 }
 
 func (s *server) listAccounts(ctx *gin.Context) {
@@ -112,45 +140,52 @@ func (s *server) listAccounts(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	ctx.JSON(http.StatusOK, accounts)
+	ctx.JSON(http.StatusOK, newAccountsResponse(accounts))
 }
 
-func (s *server) updateAccount(ctx *gin.Context) {
-	var req updateAccountRequest
-
-	err := ctx.ShouldBindJSON(&req)
-	if err != nil {
+// closeAccount closes an account of the authenticated user. The account keeps its
+// history but no money can move in or out anymore, so the balance must be zero.
+func (s *server) closeAccount(ctx *gin.Context) {
+	var req getAccountRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	args := db.UpdateAccountParams{
-		ID:      req.ID,
-		Balance: req.Balance,
-	}
-
-	account, err := s.store.UpdateAccount(ctx, args)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	account, valid := s.ownedAccount(ctx, req.ID)
+	if !valid {
 		return
 	}
-	ctx.JSON(http.StatusOK, account)
-}
 
-func (s *server) deleteAccount(ctx *gin.Context) {
-	var req deleteAccountRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	if account.Status == db.AccountStatusFrozen {
+		err := fmt.Errorf("account %d is frozen, only an admin can change it", account.ID)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
 		return
 	}
 
-	if err := s.store.DeleteAccount(ctx, req.ID); err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	s.changeAccountStatus(ctx, account.ID, db.AccountStatusClosed)
+}
+
+// changeAccountStatus moves an account to a new status and writes the response.
+func (s *server) changeAccountStatus(ctx *gin.Context, accountID int64, status db.AccountStatus) {
+	account, err := s.store.ChangeAccountStatusTx(ctx, db.ChangeAccountStatusTxParams{
+		AccountID: accountID,
+		Status:    status,
+	})
+	if err != nil {
+		switch {
+		case err == sql.ErrNoRows:
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrAccountClosed):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+		case errors.Is(err, db.ErrAccountNotEmpty):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
 		return
 	}
-
-	ctx.JSON(http.StatusOK, fmt.Sprintf("Account with id %v deleted", req.ID))
-
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
 
 func errorResponse(err error) gin.H {
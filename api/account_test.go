@@ -116,7 +116,7 @@ func TestCreateAccount(t *testing.T) {
 			expectedStatus: http.StatusCreated,
 			stubs: func() *mocks.Store {
 				mocksStore := new(mocks.Store)
-				mocksStore.On("CreateAccount", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateAccountParams")).Return(*account, nil)
+				mocksStore.On("CreateAccountTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateAccountParams")).Return(*account, nil)
 				return mocksStore
 			},
 			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
@@ -130,7 +130,24 @@ func TestCreateAccount(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			stubs: func() *mocks.Store {
 				mocksStore := new(mocks.Store)
-				mocksStore.On("CreateAccount", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateAccountParams")).Return(*account, nil)
+				mocksStore.On("CreateAccountTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateAccountParams")).Return(*account, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+		},
+		"Disabled currency": {
+			body: gin.H{
+				"currency": util.CAD,
+			},
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("ListCurrencies", mock.Anything).Return([]db.Currency{
+					{Code: util.USD, Exponent: 2, Enabled: true},
+					{Code: util.CAD, Exponent: 2, Enabled: false},
+				}, nil)
 				return mocksStore
 			},
 			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
@@ -145,7 +162,7 @@ func TestCreateAccount(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			stubs: func() *mocks.Store {
 				mocksStore := new(mocks.Store)
-				mocksStore.On("CreateAccount", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateAccountParams")).Return(db.Account{}, errors.New("internal error"))
+				mocksStore.On("CreateAccountTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateAccountParams")).Return(db.Account{}, errors.New("internal error"))
 				return mocksStore
 			},
 			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
@@ -285,84 +302,90 @@ func TestListAccounts(t *testing.T) {
 	}
 }
 
-func TestUpdateAccount(t *testing.T) {
-
+func TestCloseAccount(t *testing.T) {
 	user := randomUser("temp")
 	account := randomAccount(user.Username)
 
-	extra := 100
+	frozen := *account
+	frozen.Status = db.AccountStatusFrozen
+
+	closeParams := db.ChangeAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    db.AccountStatusClosed,
+	}
+
 	testCases := map[string]struct {
-		body           gin.H
+		username       string
 		expectedStatus int
-		stubs          func() *mocks.Store
-		setupAuth      func(t *testing.T, req *http.Request, maker token.Maker)
+		stub           func() *mocks.Store
 	}{
 		"Status OK": {
-			body: gin.H{
-				"id":      account.ID,
-				"balance": account.Balance + int64(extra),
-			},
+			username:       user.Username,
 			expectedStatus: http.StatusOK,
-			stubs: func() *mocks.Store {
-				mocksStore := new(mocks.Store)
-				mocksStore.On("UpdateAccount", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.UpdateAccountParams")).Return(db.Account{ID: account.ID, Currency: account.Currency, Balance: account.Balance + int64(extra), Owner: account.Owner, CreatedAt: account.CreatedAt.Add(time.Second)}, nil)
-				return mocksStore
-			},
-			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
-				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+			stub: func() *mocks.Store {
+				mockStore := new(mocks.Store)
+				closed := *account
+				closed.Status = db.AccountStatusClosed
+				mockStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				mockStore.On("ChangeAccountStatusTx", mock.AnythingOfType("*gin.Context"), closeParams).Return(closed, nil)
+				return mockStore
 			},
 		},
-		"Bad Request": {
-			body: gin.H{
-				"bad": "invalid",
-			},
-			expectedStatus: http.StatusBadRequest,
-			stubs: func() *mocks.Store {
-				mocksStore := new(mocks.Store)
-				mocksStore.On("UpdateAccount", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.UpdateAccountParams")).Return(db.Account{}, nil)
-				return mocksStore
-			},
-			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
-				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+		"Balance not zero": {
+			username:       user.Username,
+			expectedStatus: http.StatusUnprocessableEntity,
+			stub: func() *mocks.Store {
+				mockStore := new(mocks.Store)
+				mockStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				mockStore.On("ChangeAccountStatusTx", mock.AnythingOfType("*gin.Context"), closeParams).Return(db.Account{}, db.ErrAccountNotEmpty)
+				return mockStore
 			},
 		},
-		"Internal server error": {
-			body: gin.H{
-				"id":      account.ID,
-				"balance": account.Balance + int64(extra),
+		"Already closed": {
+			username:       user.Username,
+			expectedStatus: http.StatusConflict,
+			stub: func() *mocks.Store {
+				mockStore := new(mocks.Store)
+				mockStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				mockStore.On("ChangeAccountStatusTx", mock.AnythingOfType("*gin.Context"), closeParams).Return(db.Account{}, db.ErrAccountClosed)
+				return mockStore
 			},
-			expectedStatus: http.StatusInternalServerError,
-			stubs: func() *mocks.Store {
-				mocksStore := new(mocks.Store)
-				mocksStore.On("UpdateAccount", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.UpdateAccountParams")).Return(db.Account{}, errors.New("internal error"))
-				return mocksStore
+		},
+		"Frozen": {
+			username:       user.Username,
+			expectedStatus: http.StatusConflict,
+			stub: func() *mocks.Store {
+				mockStore := new(mocks.Store)
+				mockStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(frozen, nil)
+				return mockStore
 			},
-			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
-				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+		},
+		"Not owner": {
+			username:       "someoneelse",
+			expectedStatus: http.StatusUnauthorized,
+			stub: func() *mocks.Store {
+				mockStore := new(mocks.Store)
+				mockStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				return mockStore
 			},
 		},
 	}
 
 	for name, test := range testCases {
-
 		t.Run(name, func(t *testing.T) {
-
-			mockStore := test.stubs()
-
-			server := newTestServer(t, mockStore)
+			mockStore := test.stub()
 			recorder := httptest.NewRecorder()
 
-			data, err := json.Marshal(test.body)
-			require.NoError(t, err)
-
-			url := "/accounts/"
-			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			url := fmt.Sprintf("/accounts/%d/close", account.ID)
+			req, err := http.NewRequest(http.MethodPost, url, nil)
 			require.NoError(t, err)
 
-			test.setupAuth(t, request, server.tokenMaker)
+			server := newTestServer(t, mockStore)
+			addAuth(t, req, server.tokenMaker, authorizationTypeBearer, test.username, time.Minute)
+			server.router.ServeHTTP(recorder, req)
 
-			server.router.ServeHTTP(recorder, request)
 			require.Equal(t, test.expectedStatus, recorder.Code)
+			mockStore.AssertExpectations(t)
 		})
 	}
 }
@@ -0,0 +1,289 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/token"
+	"github.com/gin-gonic/gin"
+)
+
+type adjustBalanceRequest struct {
+	Amount     int64  `json:"amount" binding:"required"`
+	ReasonCode string `json:"reason_code" binding:"required,oneof=correction goodwill chargeback fee_refund write_off"`
+	Note       string `json:"note" binding:"required,max=500"`
+}
+
+type updateOverdraftLimitRequest struct {
+	OverdraftLimit *int64 `json:"overdraft_limit" binding:"required,min=0"`
+}
+
+func (server *server) reconcile(ctx *gin.Context) {
+	report, err := server.store.Reconcile(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+func (server *server) adjustBalance(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req adjustBalanceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := server.store.GetAccount(ctx, uri.ID); err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.store.AdjustBalanceTx(ctx, db.AdjustBalanceTxParams{
+		AccountID:  uri.ID,
+		Amount:     req.Amount,
+		ReasonCode: db.AdjustmentReason(req.ReasonCode),
+		Note:       req.Note,
+		Actor:      authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrAccountClosed) || errors.Is(err, db.ErrSystemAccount) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusCreated, result)
+}
+
+func (server *server) updateOverdraftLimit(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateOverdraftLimitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.UpdateAccountOverdraftLimit(ctx, db.UpdateAccountOverdraftLimitParams{
+		ID:             uri.ID,
+		OverdraftLimit: *req.OverdraftLimit,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+type revokeUserSessionsResponse struct {
+	Username        string `json:"username"`
+	BlockedSessions int64  `json:"blockedSessions"`
+}
+
+// revokeUserSessions blocks every session of a user and rejects every token issued
+// to them so far, the user has to log in again.
+func (server *server) revokeUserSessions(ctx *gin.Context) {
+	var uri getUserRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := server.store.GetUser(ctx, uri.Username); err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	blocked, err := server.revokeUser(ctx, uri.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, revokeUserSessionsResponse{
+		Username:        uri.Username,
+		BlockedSessions: blocked,
+	})
+}
+
+// revokeUser blocks the sessions of a user and revokes the tokens issued to them so far,
+// it returns how many sessions were blocked.
+func (server *server) revokeUser(ctx *gin.Context, username string) (int64, error) {
+	blocked, err := server.store.BlockUserSessions(ctx, username)
+	if err != nil {
+		return 0, err
+	}
+
+	ttl := server.config.ACCESS_TOKEN_DURATION
+	if server.config.REFRESH_TOKEN_DURATION > ttl {
+		ttl = server.config.REFRESH_TOKEN_DURATION
+	}
+	if err := server.revocations.RevokeUser(ctx, username, ttl); err != nil {
+		return 0, err
+	}
+	return blocked, nil
+}
+
+type updateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=customer support admin"`
+}
+
+// updateUserRole changes the role of a user. Tokens carry the role, so the tokens and
+// sessions of the user are revoked and the new role applies from the next login.
+func (server *server) updateUserRole(ctx *gin.Context) {
+	var uri getUserRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateUserRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if uri.Username == authPayload.Username {
+		err := errors.New("admins can't change their own role")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.UpdateUserRole(ctx, db.UpdateUserRoleParams{
+		Username: uri.Username,
+		Role:     db.UserRole(req.Role),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if _, err := server.revokeUser(ctx, user.Username); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// lookupUser returns any user, unlike the accounts of a user it is open to support staff.
+func (server *server) lookupUser(ctx *gin.Context) {
+	var uri getUserRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, uri.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+type listAllAccountsRequest struct {
+	Owner    string `form:"owner" binding:"omitempty,alphanum"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+// listAllAccounts lists the accounts of every user, or of one user when owner is set.
+func (server *server) listAllAccounts(ctx *gin.Context) {
+	var req listAllAccountsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	accounts, err := server.store.ListAllAccounts(ctx, db.ListAllAccountsParams{
+		Owner:       req.Owner,
+		LimitCount:  req.PageSize,
+		OffsetCount: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, newAccountsResponse(accounts))
+}
+
+func (server *server) freezeAccount(ctx *gin.Context) {
+	server.setAccountStatus(ctx, db.AccountStatusFrozen)
+}
+
+func (server *server) unfreezeAccount(ctx *gin.Context) {
+	server.setAccountStatus(ctx, db.AccountStatusActive)
+}
+
+func (server *server) adminCloseAccount(ctx *gin.Context) {
+	server.setAccountStatus(ctx, db.AccountStatusClosed)
+}
+
+// setAccountStatus moves any customer account to the given status, money doesn't move
+// in or out of a frozen or closed account. The system cash accounts can't be changed.
+func (server *server) setAccountStatus(ctx *gin.Context, status db.AccountStatus) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	server.changeAccountStatus(ctx, uri.ID, status)
+}
+
+type tokenKeysResponse struct {
+	ActiveKeyID string   `json:"activeKeyID"`
+	KeyIDs      []string `json:"keyIDs"`
+}
+
+func (server *server) reloadTokenKeys(ctx *gin.Context) {
+	if err := server.ReloadTokenKeys(); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, tokenKeysResponse{
+		ActiveKeyID: server.tokenKeys.ActiveKeyID(),
+		KeyIDs:      server.tokenKeys.KeyIDs(),
+	})
+}
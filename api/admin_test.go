@@ -0,0 +1,723 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/mocks"
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/RahilRehan/banco/token"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcile(t *testing.T) {
+	testCases := map[string]struct {
+		expectedStatus int
+		stubs          func() *mocks.Store
+		setupAuth      func(t *testing.T, req *http.Request, maker token.Maker)
+	}{
+		"Status OK": {
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("Reconcile", mock.AnythingOfType("*gin.Context")).Return(db.ReconciliationReport{Balanced: true}, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+		},
+		"Not admin": {
+			expectedStatus: http.StatusForbidden,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, "customer", time.Minute)
+			},
+		},
+		"No Auth": {
+			expectedStatus: http.StatusUnauthorized,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {},
+		},
+		"Internal server error": {
+			expectedStatus: http.StatusInternalServerError,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("Reconcile", mock.AnythingOfType("*gin.Context")).Return(db.ReconciliationReport{}, errors.New("internal error"))
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/reconciliation", nil)
+			require.NoError(t, err)
+
+			test.setupAuth(t, request, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestAdjustBalance(t *testing.T) {
+	account := randomAccount(util.RandomOwner())
+
+	body := gin.H{
+		"amount":      -50,
+		"reason_code": "chargeback",
+		"note":        "disputed card payment",
+	}
+
+	testCases := map[string]struct {
+		body           gin.H
+		expectedStatus int
+		stubs          func() *mocks.Store
+		setupAuth      func(t *testing.T, req *http.Request, maker token.Maker)
+	}{
+		"Status Created": {
+			body:           body,
+			expectedStatus: http.StatusCreated,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				mocksStore.On("AdjustBalanceTx", mock.AnythingOfType("*gin.Context"), db.AdjustBalanceTxParams{
+					AccountID:  account.ID,
+					Amount:     -50,
+					ReasonCode: db.AdjustmentReasonChargeback,
+					Note:       "disputed card payment",
+					Actor:      testAdminUsername,
+				}).Return(db.AdjustBalanceTxResult{}, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+		},
+		"Not admin": {
+			body:           body,
+			expectedStatus: http.StatusForbidden,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, account.Owner, time.Minute)
+			},
+		},
+		"Missing reason code": {
+			body: gin.H{
+				"amount": 50,
+				"note":   "no reason",
+			},
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+		},
+		"Zero amount": {
+			body: gin.H{
+				"amount":      0,
+				"reason_code": "correction",
+				"note":        "nothing",
+			},
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+		},
+		"System account": {
+			body:           body,
+			expectedStatus: http.StatusConflict,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				mocksStore.On("AdjustBalanceTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.AdjustBalanceTxParams")).Return(db.AdjustBalanceTxResult{}, fmt.Errorf("%w: %d", db.ErrSystemAccount, account.ID))
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+		},
+		"Insufficient funds": {
+			body:           body,
+			expectedStatus: http.StatusUnprocessableEntity,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				mocksStore.On("AdjustBalanceTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.AdjustBalanceTxParams")).Return(db.AdjustBalanceTxResult{}, db.ErrInsufficientFunds)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+		},
+		"Account not found": {
+			body:           body,
+			expectedStatus: http.StatusNotFound,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(db.Account{}, sql.ErrNoRows)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(test.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/accounts/%d/adjustments", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			test.setupAuth(t, request, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestUpdateOverdraftLimit(t *testing.T) {
+	account := randomAccount(util.RandomOwner())
+
+	testCases := map[string]struct {
+		body           gin.H
+		expectedStatus int
+		stubs          func() *mocks.Store
+	}{
+		"Status OK": {
+			body:           gin.H{"overdraft_limit": 100},
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("UpdateAccountOverdraftLimit", mock.AnythingOfType("*gin.Context"), db.UpdateAccountOverdraftLimitParams{
+					ID:             account.ID,
+					OverdraftLimit: 100,
+				}).Return(*account, nil)
+				return mocksStore
+			},
+		},
+		"Zero limit": {
+			body:           gin.H{"overdraft_limit": 0},
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("UpdateAccountOverdraftLimit", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.UpdateAccountOverdraftLimitParams")).Return(*account, nil)
+				return mocksStore
+			},
+		},
+		"Negative limit": {
+			body:           gin.H{"overdraft_limit": -1},
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+		},
+		"Not found": {
+			body:           gin.H{"overdraft_limit": 100},
+			expectedStatus: http.StatusNotFound,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("UpdateAccountOverdraftLimit", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.UpdateAccountOverdraftLimitParams")).Return(db.Account{}, sql.ErrNoRows)
+				return mocksStore
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(test.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/accounts/%d/overdraft-limit", account.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthWithRole(t, request, server.tokenMaker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestRevokeUserSessions(t *testing.T) {
+	user := randomUser("temp")
+
+	testCases := map[string]struct {
+		username       string
+		expectedStatus int
+		stubs          func() *mocks.Store
+	}{
+		"Status OK": {
+			username:       user.Username,
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetUser", mock.AnythingOfType("*gin.Context"), user.Username).Return(db.User{Username: user.Username}, nil)
+				mocksStore.On("BlockUserSessions", mock.AnythingOfType("*gin.Context"), user.Username).Return(int64(2), nil)
+				return mocksStore
+			},
+		},
+		"User not found": {
+			username:       user.Username,
+			expectedStatus: http.StatusNotFound,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetUser", mock.AnythingOfType("*gin.Context"), user.Username).Return(db.User{}, sql.ErrNoRows)
+				return mocksStore
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			_, userPayload, err := server.tokenMaker.CreateToken(test.username, util.CustomerRole, token.AccessToken, time.Minute)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/users/%s/revoke-sessions", test.username)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthWithRole(t, request, server.tokenMaker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+
+			revoked, err := server.revocations.IsRevoked(context.Background(), userPayload)
+			require.NoError(t, err)
+			require.Equal(t, test.expectedStatus == http.StatusOK, revoked)
+		})
+	}
+}
+
+func TestReloadTokenKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeKey := func(id string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, id+".key"), []byte(util.RandomString(32)), 0600))
+	}
+	writeKey("k1")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "active"), []byte("k1"), 0600))
+
+	server := newTestServerWithConfig(t, new(mocks.Store), func(config *util.Config) {
+		config.TOKEN_KEYS_DIR = dir
+	})
+
+	reload := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/admin/token-keys/reload", nil)
+		require.NoError(t, err)
+		addAuthWithRole(t, request, server.tokenMaker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	oldToken, _, err := server.tokenMaker.CreateToken(testAdminUsername, util.AdminRole, token.AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	writeKey("k2")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "active"), []byte("k2"), 0600))
+
+	recorder := reload()
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp tokenKeysResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, tokenKeysResponse{ActiveKeyID: "k2", KeyIDs: []string{"k1", "k2"}}, rsp)
+
+	_, err = server.tokenMaker.VerifyToken(oldToken)
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "k1.key")))
+	require.Equal(t, http.StatusOK, reload().Code)
+
+	_, err = server.tokenMaker.VerifyToken(oldToken)
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "active"), []byte("missing"), 0600))
+	require.Equal(t, http.StatusInternalServerError, reload().Code)
+}
+
+func TestListAllAccounts(t *testing.T) {
+	owner := util.RandomOwner()
+	accounts := []db.Account{*randomAccount(owner), *randomAccount(owner)}
+
+	testCases := map[string]struct {
+		query          string
+		role           string
+		expectedStatus int
+		stubs          func() *mocks.Store
+	}{
+		"Status OK": {
+			query:          "page_id=1&page_size=5",
+			role:           util.AdminRole,
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("ListAllAccounts", mock.AnythingOfType("*gin.Context"), db.ListAllAccountsParams{
+					LimitCount:  5,
+					OffsetCount: 0,
+				}).Return(accounts, nil)
+				return mocksStore
+			},
+		},
+		"Support filters by owner": {
+			query:          "owner=" + owner + "&page_id=2&page_size=5",
+			role:           util.SupportRole,
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("ListAllAccounts", mock.AnythingOfType("*gin.Context"), db.ListAllAccountsParams{
+					Owner:       owner,
+					LimitCount:  5,
+					OffsetCount: 5,
+				}).Return(accounts, nil)
+				return mocksStore
+			},
+		},
+		"Customer": {
+			query:          "page_id=1&page_size=5",
+			role:           util.CustomerRole,
+			expectedStatus: http.StatusForbidden,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+		},
+		"Invalid page size": {
+			query:          "page_id=1&page_size=1000",
+			role:           util.AdminRole,
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+		},
+		"Internal server error": {
+			query:          "page_id=1&page_size=5",
+			role:           util.AdminRole,
+			expectedStatus: http.StatusInternalServerError,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("ListAllAccounts", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.ListAllAccountsParams")).Return([]db.Account{}, sql.ErrConnDone)
+				return mocksStore
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/accounts?"+test.query, nil)
+			require.NoError(t, err)
+
+			addAuthWithRole(t, request, server.tokenMaker, authorizationTypeBearer, util.RandomOwner(), test.role, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var rsp []db.Account
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp, len(accounts))
+			}
+		})
+	}
+}
+
+func TestLookupUser(t *testing.T) {
+	user := db.User{Username: util.RandomOwner(), Email: util.RandomEmail(), Role: db.UserRoleSupport}
+
+	testCases := map[string]struct {
+		role           string
+		expectedStatus int
+		stubs          func() *mocks.Store
+	}{
+		"Status OK": {
+			role:           util.SupportRole,
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetUser", mock.AnythingOfType("*gin.Context"), user.Username).Return(user, nil)
+				return mocksStore
+			},
+		},
+		"Not found": {
+			role:           util.AdminRole,
+			expectedStatus: http.StatusNotFound,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetUser", mock.AnythingOfType("*gin.Context"), user.Username).Return(db.User{}, sql.ErrNoRows)
+				return mocksStore
+			},
+		},
+		"Customer": {
+			role:           util.CustomerRole,
+			expectedStatus: http.StatusForbidden,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/users/"+user.Username, nil)
+			require.NoError(t, err)
+
+			addAuthWithRole(t, request, server.tokenMaker, authorizationTypeBearer, util.RandomOwner(), test.role, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var rsp userResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, user.Username, rsp.Username)
+				require.Equal(t, util.SupportRole, rsp.Role)
+			}
+		})
+	}
+}
+
+func TestSetAccountStatus(t *testing.T) {
+	account := randomAccount(util.RandomOwner())
+
+	testCases := map[string]struct {
+		action         string
+		role           string
+		expectedStatus int
+		stubs          func() *mocks.Store
+	}{
+		"Freeze": {
+			action:         "freeze",
+			role:           util.AdminRole,
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				frozen := *account
+				frozen.Status = db.AccountStatusFrozen
+				mocksStore.On("ChangeAccountStatusTx", mock.AnythingOfType("*gin.Context"), db.ChangeAccountStatusTxParams{
+					AccountID: account.ID,
+					Status:    db.AccountStatusFrozen,
+				}).Return(frozen, nil)
+				return mocksStore
+			},
+		},
+		"Unfreeze": {
+			action:         "unfreeze",
+			role:           util.AdminRole,
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("ChangeAccountStatusTx", mock.AnythingOfType("*gin.Context"), db.ChangeAccountStatusTxParams{
+					AccountID: account.ID,
+					Status:    db.AccountStatusActive,
+				}).Return(*account, nil)
+				return mocksStore
+			},
+		},
+		"Not found": {
+			action:         "freeze",
+			role:           util.AdminRole,
+			expectedStatus: http.StatusNotFound,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("ChangeAccountStatusTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.ChangeAccountStatusTxParams")).Return(db.Account{}, sql.ErrNoRows)
+				return mocksStore
+			},
+		},
+		"Close": {
+			action:         "close",
+			role:           util.AdminRole,
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				closed := *account
+				closed.Status = db.AccountStatusClosed
+				mocksStore.On("ChangeAccountStatusTx", mock.AnythingOfType("*gin.Context"), db.ChangeAccountStatusTxParams{
+					AccountID: account.ID,
+					Status:    db.AccountStatusClosed,
+				}).Return(closed, nil)
+				return mocksStore
+			},
+		},
+		"Already closed": {
+			action:         "unfreeze",
+			role:           util.AdminRole,
+			expectedStatus: http.StatusConflict,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("ChangeAccountStatusTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.ChangeAccountStatusTxParams")).Return(db.Account{}, fmt.Errorf("%w: %d", db.ErrAccountClosed, account.ID))
+				return mocksStore
+			},
+		},
+		"Support": {
+			action:         "freeze",
+			role:           util.SupportRole,
+			expectedStatus: http.StatusForbidden,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/admin/accounts/%d/%s", account.ID, test.action)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthWithRole(t, request, server.tokenMaker, authorizationTypeBearer, testAdminUsername, test.role, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	user := db.User{Username: util.RandomOwner(), Role: db.UserRoleSupport}
+
+	testCases := map[string]struct {
+		username       string
+		body           gin.H
+		expectedStatus int
+		stubs          func() *mocks.Store
+	}{
+		"Status OK": {
+			username:       user.Username,
+			body:           gin.H{"role": util.SupportRole},
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("UpdateUserRole", mock.AnythingOfType("*gin.Context"), db.UpdateUserRoleParams{
+					Username: user.Username,
+					Role:     db.UserRoleSupport,
+				}).Return(user, nil)
+				mocksStore.On("BlockUserSessions", mock.AnythingOfType("*gin.Context"), user.Username).Return(int64(1), nil)
+				return mocksStore
+			},
+		},
+		"Invalid role": {
+			username:       user.Username,
+			body:           gin.H{"role": "root"},
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+		},
+		"Own role": {
+			username:       testAdminUsername,
+			body:           gin.H{"role": util.CustomerRole},
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+		},
+		"User not found": {
+			username:       user.Username,
+			body:           gin.H{"role": util.AdminRole},
+			expectedStatus: http.StatusNotFound,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("UpdateUserRole", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.UpdateUserRoleParams")).Return(db.User{}, sql.ErrNoRows)
+				return mocksStore
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			_, userPayload, err := server.tokenMaker.CreateToken(test.username, util.CustomerRole, token.AccessToken, time.Minute)
+			require.NoError(t, err)
+
+			data, err := json.Marshal(test.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/users/%s/role", test.username)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthWithRole(t, request, server.tokenMaker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+
+			// the old tokens carry the old role, they have to go
+			revoked, err := server.revocations.IsRevoked(context.Background(), userPayload)
+			require.NoError(t, err)
+			require.Equal(t, test.expectedStatus == http.StatusOK, revoked)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	requestIDHeaderKey = "X-Request-ID"
+	maxRequestIDLength = 64
+)
+
+// auditMiddleware makes sure every state-changing request leaves an audit event. It shares
+// the request metadata with the store through the context, so the events the store writes
+// carry its request id; authMiddleware fills in the actor. When the store recorded the
+// change with its snapshots the request needs nothing more, otherwise (a refused request,
+// or a change the store doesn't audit) a request level event without snapshots is written.
+func auditMiddleware(store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeaderKey)
+		if len(requestID) == 0 || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+		ctx.Header(requestIDHeaderKey, requestID)
+
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			ctx.Next()
+			return
+		}
+
+		audit := &db.AuditContext{
+			RequestID: requestID,
+			ClientIP:  ctx.ClientIP(),
+		}
+		ctx.Set(db.AuditContextKey, audit)
+
+		ctx.Next()
+
+		// unknown routes change nothing
+		if len(ctx.FullPath()) == 0 || audit.Recorded {
+			return
+		}
+
+		resourceType, resourceID := auditResource(ctx)
+		_, err := store.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+			Actor:        audit.Actor,
+			Action:       ctx.Request.Method + " " + ctx.FullPath(),
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+			RequestID:    audit.RequestID,
+			ClientIp:     audit.ClientIP,
+			Before:       json.RawMessage("null"),
+			After:        json.RawMessage("null"),
+		})
+		if err != nil {
+			// the response is already written, the failure can only be logged
+			log.Printf("cannot record audit event of request %s: %v", requestID, err)
+		}
+	}
+}
+
+// auditResource names what a request acts on from its route, e.g. accounts and 42
+// for /admin/accounts/42/freeze.
+func auditResource(ctx *gin.Context) (string, string) {
+	segments := strings.Split(strings.Trim(ctx.FullPath(), "/"), "/")
+	if len(segments) > 1 && segments[0] == "admin" {
+		segments = segments[1:]
+	}
+	for _, param := range []string{"id", "username", "code"} {
+		if value := ctx.Param(param); len(value) > 0 {
+			return segments[0], value
+		}
+	}
+	return segments[0], ""
+}
+
+type listAuditEventsRequest struct {
+	Actor        string    `form:"actor"`
+	Action       string    `form:"action"`
+	ResourceType string    `form:"resource_type"`
+	ResourceID   string    `form:"resource_id"`
+	RequestID    string    `form:"request_id"`
+	StartTime    time.Time `form:"start_time"`
+	EndTime      time.Time `form:"end_time"`
+	Cursor       int64     `form:"cursor" binding:"omitempty,min=1"`
+	PageSize     int32     `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+type listAuditEventsResponse struct {
+	Events     []db.AuditEvent `json:"events"`
+	NextCursor int64           `json:"nextCursor,omitempty"`
+}
+
+// listAuditEvents returns the newest audit events first, every filter is optional.
+func (server *server) listAuditEvents(ctx *gin.Context) {
+	var req listAuditEventsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.ListAuditEventsParams{
+		Actor:        req.Actor,
+		Action:       req.Action,
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceID,
+		RequestID:    req.RequestID,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		Cursor:       req.Cursor,
+		PageSize:     req.PageSize,
+	}
+	if arg.EndTime.IsZero() {
+		arg.EndTime = time.Now()
+	}
+	if arg.Cursor == 0 {
+		arg.Cursor = math.MaxInt64
+	}
+
+	if !arg.StartTime.Before(arg.EndTime) {
+		err := errors.New("invalid range: start must be before end")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	events, err := server.store.ListAuditEvents(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := listAuditEventsResponse{Events: events}
+	if len(events) == int(req.PageSize) {
+		rsp.NextCursor = events[len(events)-1].ID
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/mocks"
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditMiddleware(t *testing.T) {
+	account := randomAccount(util.RandomOwner())
+	requestID := "req-" + util.RandomString(8)
+
+	var event db.CreateAuditEventParams
+	var storeAudit *db.AuditContext
+	mockStore := new(mocks.Store)
+	mockStore.On("ChangeAccountStatusTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.ChangeAccountStatusTxParams")).
+		Run(func(args mock.Arguments) {
+			// the store writes its own event and marks the context recorded
+			storeAudit = args.Get(0).(*gin.Context).Value(db.AuditContextKey).(*db.AuditContext)
+			storeAudit.Recorded = true
+		}).
+		Return(*account, nil)
+	mockStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+	mockStore.On("CreateAuditEvent", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateAuditEventParams")).
+		Run(func(args mock.Arguments) {
+			event = args.Get(1).(db.CreateAuditEventParams)
+		}).
+		Return(db.AuditEvent{}, nil)
+
+	server := newTestServer(t, mockStore)
+
+	// reads are not audited
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d", account.ID), nil)
+	require.NoError(t, err)
+	addAuth(t, request, server.tokenMaker, authorizationTypeBearer, account.Owner, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotEmpty(t, recorder.Header().Get(requestIDHeaderKey))
+	mockStore.AssertNotCalled(t, "CreateAuditEvent", mock.Anything, mock.Anything)
+
+	// a change the store recorded gets no second event
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodPost, fmt.Sprintf("/admin/accounts/%d/freeze", account.ID), nil)
+	require.NoError(t, err)
+	request.Header.Set(requestIDHeaderKey, requestID)
+	request.RemoteAddr = "10.0.0.1:41234"
+	addAuthWithRole(t, request, server.tokenMaker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, requestID, recorder.Header().Get(requestIDHeaderKey))
+	mockStore.AssertNotCalled(t, "CreateAuditEvent", mock.Anything, mock.Anything)
+
+	// the store sees the request metadata for its own events
+	require.Equal(t, &db.AuditContext{Actor: testAdminUsername, RequestID: requestID, ClientIP: "10.0.0.1", Recorded: true}, storeAudit)
+
+	// a refused request leaves a request level event without snapshots
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodPost, "/admin/accounts/0/freeze", nil)
+	require.NoError(t, err)
+	request.Header.Set(requestIDHeaderKey, requestID)
+	request.RemoteAddr = "10.0.0.1:41234"
+	addAuthWithRole(t, request, server.tokenMaker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	require.Equal(t, testAdminUsername, event.Actor)
+	require.Equal(t, "POST /admin/accounts/:id/freeze", event.Action)
+	require.Equal(t, "accounts", event.ResourceType)
+	require.Equal(t, "0", event.ResourceID)
+	require.Equal(t, requestID, event.RequestID)
+	require.Equal(t, "10.0.0.1", event.ClientIp)
+	require.JSONEq(t, "null", string(event.Before))
+	require.JSONEq(t, "null", string(event.After))
+}
+
+func TestListAuditEvents(t *testing.T) {
+	events := []db.AuditEvent{
+		{ID: 12, Actor: testAdminUsername, Action: db.AuditActionAccountStatus, ResourceType: "accounts", ResourceID: "7"},
+		{ID: 11, Actor: testAdminUsername, Action: "POST /admin/accounts/:id/freeze", ResourceType: "accounts", ResourceID: "7"},
+	}
+
+	testCases := map[string]struct {
+		query          string
+		role           string
+		expectedStatus int
+		stubs          func() *mocks.Store
+	}{
+		"Status OK": {
+			query:          "actor=admin&resource_type=accounts&resource_id=7&page_size=5",
+			role:           util.AdminRole,
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("ListAuditEvents", mock.AnythingOfType("*gin.Context"), mock.MatchedBy(func(arg db.ListAuditEventsParams) bool {
+					return arg.Actor == testAdminUsername &&
+						arg.ResourceType == "accounts" &&
+						arg.ResourceID == "7" &&
+						arg.Action == "" &&
+						arg.PageSize == 5 &&
+						!arg.EndTime.IsZero()
+				})).Return(events, nil)
+				return mocksStore
+			},
+		},
+		"Invalid range": {
+			query:          "start_time=2030-01-02T00:00:00Z&end_time=2030-01-01T00:00:00Z&page_size=5",
+			role:           util.AdminRole,
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+		},
+		"Missing page size": {
+			query:          "actor=admin",
+			role:           util.AdminRole,
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+		},
+		"Support": {
+			query:          "page_size=5",
+			role:           util.SupportRole,
+			expectedStatus: http.StatusForbidden,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+		},
+		"Internal server error": {
+			query:          "page_size=5",
+			role:           util.AdminRole,
+			expectedStatus: http.StatusInternalServerError,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("ListAuditEvents", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.ListAuditEventsParams")).Return([]db.AuditEvent{}, fmt.Errorf("internal error"))
+				return mocksStore
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/audit-events?"+test.query, nil)
+			require.NoError(t, err)
+
+			addAuthWithRole(t, request, server.tokenMaker, authorizationTypeBearer, testAdminUsername, test.role, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var rsp listAuditEventsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Events, len(events))
+				require.Zero(t, rsp.NextCursor)
+			}
+		})
+	}
+}
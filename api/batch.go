@@ -0,0 +1,121 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/token"
+	"github.com/gin-gonic/gin"
+)
+
+const batchModePerItem = "per_item"
+
+type batchTransferLeg struct {
+	ToAccountID int64 `json:"to_account_id" binding:"required,min=1"`
+	Amount      int64 `json:"amount" binding:"required,gt=0"`
+}
+
+// batchTransferRequest sends from one account to many of its currency. An atomic batch,
+// the default, moves no money unless every leg goes through, a per_item one reports
+// the outcome of each leg.
+type batchTransferRequest struct {
+	FromAccountID int64              `json:"from_account_id" binding:"required,min=1"`
+	Currency      string             `json:"currency" binding:"required,currency"`
+	Mode          string             `json:"mode" binding:"omitempty,oneof=atomic per_item"`
+	Legs          []batchTransferLeg `json:"legs" binding:"required,min=1,max=500,dive"`
+}
+
+func (server *server) createBatchTransfer(ctx *gin.Context) {
+	var req batchTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !server.knownCurrencies(ctx, req.Currency) {
+		return
+	}
+
+	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromAccount.Owner != authPayload.Username {
+		err := errors.New("from account doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if !server.validBatchLegs(ctx, fromAccount, req.Legs) {
+		return
+	}
+
+	arg := db.BatchTransferTxParams{
+		FromAccountID: fromAccount.ID,
+		Legs:          make([]db.BatchTransferLeg, len(req.Legs)),
+		Atomic:        req.Mode != batchModePerItem,
+	}
+	for i, leg := range req.Legs {
+		arg.Legs[i] = db.BatchTransferLeg{ToAccountID: leg.ToAccountID, Amount: leg.Amount}
+	}
+
+	result, err := server.store.BatchTransferTx(ctx, arg)
+	if err != nil {
+		var legErr *db.BatchLegError
+		if errors.As(err, &legErr) && isUnprocessable(legErr.Err) {
+			rsp := gin.H{"error": err.Error(), "leg": legErr.Index}
+			var limitErr *db.TransferLimitError
+			if errors.As(err, &limitErr) {
+				rsp["limit"] = limitErr
+			}
+			ctx.JSON(http.StatusUnprocessableEntity, rsp)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// validBatchLegs loads every destination of a batch with one query and checks it exists,
+// isn't the source and has its currency, before any money moves.
+func (server *server) validBatchLegs(ctx *gin.Context, fromAccount db.Account, legs []batchTransferLeg) bool {
+	ids := make([]int64, len(legs))
+	for i, leg := range legs {
+		ids[i] = leg.ToAccountID
+	}
+
+	accounts, err := server.store.ListAccountsByIDs(ctx, ids)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+	found := make(map[int64]db.Account, len(accounts))
+	for _, account := range accounts {
+		found[account.ID] = account
+	}
+
+	for i, leg := range legs {
+		account, ok := found[leg.ToAccountID]
+		if !ok {
+			err := fmt.Errorf("leg %d: account [%d] not found", i, leg.ToAccountID)
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "leg": i})
+			return false
+		}
+		if account.ID == fromAccount.ID {
+			err := fmt.Errorf("leg %d: can't transfer to the source account", i)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "leg": i})
+			return false
+		}
+		if account.Currency != fromAccount.Currency {
+			err := fmt.Errorf("leg %d: account [%d] currency mismatch: %s vs %s", i, account.ID, account.Currency, fromAccount.Currency)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "leg": i})
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/mocks"
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBatchTransfer(t *testing.T) {
+	user := randomUser("temp")
+	from := randomAccount(user.Username)
+	from.Currency = util.USD
+	to1 := randomAccount("employee1")
+	to1.ID = from.ID + 1
+	to1.Currency = util.USD
+	to2 := randomAccount("employee2")
+	to2.ID = from.ID + 2
+	to2.Currency = util.USD
+	eur := randomAccount("employee3")
+	eur.ID = from.ID + 3
+	eur.Currency = util.EUR
+
+	legs := []gin.H{{"to_account_id": to1.ID, "amount": 10}, {"to_account_id": to2.ID, "amount": 20}}
+	ids := []int64{to1.ID, to2.ID}
+
+	testCases := map[string]struct {
+		body           gin.H
+		username       string
+		expectedStatus int
+		stubs          func(store *mocks.Store)
+	}{
+		"Atomic": {
+			body:           gin.H{"from_account_id": from.ID, "currency": util.USD, "legs": legs},
+			username:       user.Username,
+			expectedStatus: http.StatusOK,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+				store.On("ListAccountsByIDs", mock.AnythingOfType("*gin.Context"), ids).Return([]db.Account{*to1, *to2}, nil)
+				store.On("BatchTransferTx", mock.AnythingOfType("*gin.Context"), db.BatchTransferTxParams{
+					FromAccountID: from.ID,
+					Legs:          []db.BatchTransferLeg{{ToAccountID: to1.ID, Amount: 10}, {ToAccountID: to2.ID, Amount: 20}},
+					Atomic:        true,
+				}).Return(db.BatchTransferTxResult{Succeeded: 2}, nil)
+			},
+		},
+		"Per item": {
+			body:           gin.H{"from_account_id": from.ID, "currency": util.USD, "mode": "per_item", "legs": legs},
+			username:       user.Username,
+			expectedStatus: http.StatusOK,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+				store.On("ListAccountsByIDs", mock.AnythingOfType("*gin.Context"), ids).Return([]db.Account{*to1, *to2}, nil)
+				store.On("BatchTransferTx", mock.AnythingOfType("*gin.Context"), mock.MatchedBy(func(arg db.BatchTransferTxParams) bool {
+					return !arg.Atomic && len(arg.Legs) == 2
+				})).Return(db.BatchTransferTxResult{
+					Items:     []db.BatchTransferItem{{Transfer: &db.TransferTxResult{}}, {Error: db.ErrInsufficientFunds.Error()}},
+					Succeeded: 1,
+					Failed:    1,
+				}, nil)
+			},
+		},
+		"Atomic leg fails": {
+			body:           gin.H{"from_account_id": from.ID, "currency": util.USD, "legs": legs},
+			username:       user.Username,
+			expectedStatus: http.StatusUnprocessableEntity,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+				store.On("ListAccountsByIDs", mock.AnythingOfType("*gin.Context"), ids).Return([]db.Account{*to1, *to2}, nil)
+				store.On("BatchTransferTx", mock.AnythingOfType("*gin.Context"), mock.Anything).
+					Return(db.BatchTransferTxResult{}, &db.BatchLegError{Index: 1, Err: db.ErrInsufficientFunds})
+			},
+		},
+		"Leg account not found": {
+			body:           gin.H{"from_account_id": from.ID, "currency": util.USD, "legs": legs},
+			username:       user.Username,
+			expectedStatus: http.StatusNotFound,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+				store.On("ListAccountsByIDs", mock.AnythingOfType("*gin.Context"), ids).Return([]db.Account{*to1}, nil)
+			},
+		},
+		"Leg currency mismatch": {
+			body: gin.H{"from_account_id": from.ID, "currency": util.USD, "legs": []gin.H{
+				{"to_account_id": to1.ID, "amount": 10},
+				{"to_account_id": eur.ID, "amount": 20},
+			}},
+			username:       user.Username,
+			expectedStatus: http.StatusBadRequest,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+				store.On("ListAccountsByIDs", mock.AnythingOfType("*gin.Context"), []int64{to1.ID, eur.ID}).Return([]db.Account{*to1, *eur}, nil)
+			},
+		},
+		"Leg to the source": {
+			body: gin.H{"from_account_id": from.ID, "currency": util.USD, "legs": []gin.H{
+				{"to_account_id": from.ID, "amount": 10},
+			}},
+			username:       user.Username,
+			expectedStatus: http.StatusBadRequest,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+				store.On("ListAccountsByIDs", mock.AnythingOfType("*gin.Context"), []int64{from.ID}).Return([]db.Account{*from}, nil)
+			},
+		},
+		"Invalid leg": {
+			body: gin.H{"from_account_id": from.ID, "currency": util.USD, "legs": []gin.H{
+				{"to_account_id": to1.ID, "amount": 0},
+			}},
+			username:       user.Username,
+			expectedStatus: http.StatusBadRequest,
+			stubs:          func(store *mocks.Store) {},
+		},
+		"No legs": {
+			body:           gin.H{"from_account_id": from.ID, "currency": util.USD, "legs": []gin.H{}},
+			username:       user.Username,
+			expectedStatus: http.StatusBadRequest,
+			stubs:          func(store *mocks.Store) {},
+		},
+		"Invalid mode": {
+			body:           gin.H{"from_account_id": from.ID, "currency": util.USD, "mode": "best_effort", "legs": legs},
+			username:       user.Username,
+			expectedStatus: http.StatusBadRequest,
+			stubs:          func(store *mocks.Store) {},
+		},
+		"Unauthorized user": {
+			body:           gin.H{"from_account_id": from.ID, "currency": util.USD, "legs": legs},
+			username:       "unauthorized",
+			expectedStatus: http.StatusUnauthorized,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := new(mocks.Store)
+			test.stubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(test.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers/batch", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuth(t, request, server.tokenMaker, authorizationTypeBearer, test.username, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			store.AssertExpectations(t)
+		})
+	}
+}
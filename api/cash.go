@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type cashRequest struct {
+	Amount   int64  `json:"amount" binding:"required,gt=0"`
+	Currency string `json:"currency" binding:"required,currency"`
+}
+
+// createDeposit credits cash to any account. It is served to admins only, a
+// customer crediting their own account would be minting money.
+func (server *server) createDeposit(ctx *gin.Context) {
+	server.cashOperation(ctx, server.existingAccount, server.store.DepositTx)
+}
+
+func (server *server) createWithdrawal(ctx *gin.Context) {
+	server.cashOperation(ctx, server.ownedAccount, server.store.WithdrawTx)
+}
+
+func (server *server) cashOperation(
+	ctx *gin.Context,
+	load func(ctx *gin.Context, accountID int64) (db.Account, bool),
+	operation func(ctx context.Context, args db.CashTxParams) (db.CashTxResult, error),
+) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req cashRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !server.knownCurrencies(ctx, req.Currency) {
+		return
+	}
+
+	account, valid := load(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	if account.Currency != req.Currency {
+		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, req.Currency)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := operation(ctx, db.CashTxParams{
+		AccountID: account.ID,
+		Amount:    req.Amount,
+	})
+	if err != nil {
+		if isUnprocessable(err) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+func (server *server) existingAccount(ctx *gin.Context, accountID int64) (db.Account, bool) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return account, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, false
+	}
+	return account, true
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/mocks"
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/RahilRehan/banco/token"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCashOperations(t *testing.T) {
+	user := randomUser("temp")
+	account := randomAccount(user.Username)
+	account.Currency = util.USD
+
+	testCases := map[string]struct {
+		path           string
+		body           gin.H
+		expectedStatus int
+		stubs          func() *mocks.Store
+		setupAuth      func(t *testing.T, req *http.Request, maker token.Maker)
+	}{
+		"Deposit": {
+			path:           "/admin/accounts/%d/deposits",
+			body:           gin.H{"amount": 100, "currency": util.USD},
+			expectedStatus: http.StatusCreated,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				mocksStore.On("DepositTx", mock.AnythingOfType("*gin.Context"), db.CashTxParams{AccountID: account.ID, Amount: 100}).Return(db.CashTxResult{}, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+		},
+		"Withdrawal": {
+			path:           "/accounts/%d/withdrawals",
+			body:           gin.H{"amount": 100, "currency": util.USD},
+			expectedStatus: http.StatusCreated,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				mocksStore.On("WithdrawTx", mock.AnythingOfType("*gin.Context"), db.CashTxParams{AccountID: account.ID, Amount: 100}).Return(db.CashTxResult{}, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+		},
+		"Insufficient funds": {
+			path:           "/accounts/%d/withdrawals",
+			body:           gin.H{"amount": 100, "currency": util.USD},
+			expectedStatus: http.StatusUnprocessableEntity,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				mocksStore.On("WithdrawTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CashTxParams")).Return(db.CashTxResult{}, db.ErrInsufficientFunds)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+		},
+		"Currency mismatch": {
+			path:           "/admin/accounts/%d/deposits",
+			body:           gin.H{"amount": 100, "currency": util.EUR},
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+		},
+		"Negative amount": {
+			path:           "/admin/accounts/%d/deposits",
+			body:           gin.H{"amount": -100, "currency": util.USD},
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+		},
+		"Customer deposit": {
+			path:           "/admin/accounts/%d/deposits",
+			body:           gin.H{"amount": 100, "currency": util.USD},
+			expectedStatus: http.StatusForbidden,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+		},
+		"Customer deposit on the old route": {
+			path:           "/accounts/%d/deposits",
+			body:           gin.H{"amount": 100, "currency": util.USD},
+			expectedStatus: http.StatusNotFound,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+		},
+		"Unauthorized user": {
+			path:           "/accounts/%d/withdrawals",
+			body:           gin.H{"amount": 100, "currency": util.USD},
+			expectedStatus: http.StatusUnauthorized,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, "unauthorized", time.Minute)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(test.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf(test.path, account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			test.setupAuth(t, request, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
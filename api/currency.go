@@ -0,0 +1,99 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type createCurrencyRequest struct {
+	Code     string `json:"code" binding:"required,len=3,alpha,uppercase"`
+	Exponent *int32 `json:"exponent" binding:"required,min=0,max=4"`
+}
+
+type currencyCodeRequest struct {
+	Code string `uri:"code" binding:"required,len=3"`
+}
+
+func (server *server) listCurrencies(ctx *gin.Context) {
+	currencies, err := server.store.ListCurrencies(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, currencies)
+}
+
+func (server *server) createCurrency(ctx *gin.Context) {
+	var req createCurrencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	currency, err := server.store.CreateCurrencyTx(ctx, db.CreateCurrencyParams{
+		Code:     req.Code,
+		Exponent: *req.Exponent,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !server.reloadCurrencies(ctx) {
+		return
+	}
+	ctx.JSON(http.StatusCreated, currency)
+}
+
+func (server *server) disableCurrency(ctx *gin.Context) {
+	server.setCurrencyEnabled(ctx, false)
+}
+
+func (server *server) enableCurrency(ctx *gin.Context) {
+	server.setCurrencyEnabled(ctx, true)
+}
+
+func (server *server) setCurrencyEnabled(ctx *gin.Context, enabled bool) {
+	var uri currencyCodeRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	currency, err := server.store.UpdateCurrencyEnabled(ctx, db.UpdateCurrencyEnabledParams{
+		Code:    uri.Code,
+		Enabled: enabled,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !server.reloadCurrencies(ctx) {
+		return
+	}
+	ctx.JSON(http.StatusOK, currency)
+}
+
+// reloadCurrencies refreshes the in-memory registry after the currencies table changed.
+func (server *server) reloadCurrencies(ctx *gin.Context) bool {
+	if err := server.currencies.Reload(ctx); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/mocks"
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/RahilRehan/banco/token"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCurrency(t *testing.T) {
+	jpy := db.Currency{Code: "JPY", Exponent: 0, Enabled: true}
+
+	testCases := map[string]struct {
+		body           gin.H
+		expectedStatus int
+		stubs          func() *mocks.Store
+		setupAuth      func(t *testing.T, req *http.Request, maker token.Maker)
+		checkResponse  func(t *testing.T, server *server)
+	}{
+		"Status OK": {
+			body:           gin.H{"code": jpy.Code, "exponent": jpy.Exponent},
+			expectedStatus: http.StatusCreated,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("ListCurrencies", mock.Anything).Return(testCurrencies, nil).Once()
+				mocksStore.On("CreateCurrencyTx", mock.AnythingOfType("*gin.Context"), db.CreateCurrencyParams{Code: jpy.Code, Exponent: jpy.Exponent}).Return(jpy, nil)
+				mocksStore.On("ListCurrencies", mock.Anything).Return(append(testCurrencies, jpy), nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, server *server) {
+				require.True(t, server.currencies.IsEnabled(jpy.Code))
+			},
+		},
+		"Missing exponent": {
+			body:           gin.H{"code": jpy.Code},
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, server *server) {},
+		},
+		"Invalid code": {
+			body:           gin.H{"code": "usd", "exponent": 2},
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, server *server) {},
+		},
+		"Already exists": {
+			body:           gin.H{"code": util.USD, "exponent": 2},
+			expectedStatus: http.StatusConflict,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("CreateCurrencyTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateCurrencyParams")).Return(db.Currency{}, &pq.Error{Code: "23505"})
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthWithRole(t, req, maker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+			},
+			checkResponse: func(t *testing.T, server *server) {},
+		},
+		"Not admin": {
+			body:           gin.H{"code": jpy.Code, "exponent": jpy.Exponent},
+			expectedStatus: http.StatusForbidden,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, "customer", time.Minute)
+			},
+			checkResponse: func(t *testing.T, server *server) {},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(test.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/currencies", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			test.setupAuth(t, request, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			test.checkResponse(t, server)
+		})
+	}
+}
+
+func TestDisableCurrency(t *testing.T) {
+	disabled := []db.Currency{
+		{Code: util.USD, Exponent: 2, Enabled: true},
+		{Code: util.EUR, Exponent: 2, Enabled: true},
+		{Code: util.CAD, Exponent: 2, Enabled: false},
+	}
+
+	testCases := map[string]struct {
+		code           string
+		expectedStatus int
+		stubs          func() *mocks.Store
+		checkResponse  func(t *testing.T, server *server)
+	}{
+		"Status OK": {
+			code:           util.CAD,
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("ListCurrencies", mock.Anything).Return(testCurrencies, nil).Once()
+				mocksStore.On("UpdateCurrencyEnabled", mock.AnythingOfType("*gin.Context"), db.UpdateCurrencyEnabledParams{Code: util.CAD, Enabled: false}).Return(disabled[2], nil)
+				mocksStore.On("ListCurrencies", mock.Anything).Return(disabled, nil)
+				return mocksStore
+			},
+			checkResponse: func(t *testing.T, server *server) {
+				require.True(t, server.currencies.IsRegistered(util.CAD))
+				require.False(t, server.currencies.IsEnabled(util.CAD))
+			},
+		},
+		"Not found": {
+			code:           "XXX",
+			expectedStatus: http.StatusNotFound,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("UpdateCurrencyEnabled", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.UpdateCurrencyEnabledParams")).Return(db.Currency{}, sql.ErrNoRows)
+				return mocksStore
+			},
+			checkResponse: func(t *testing.T, server *server) {},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/currencies/"+test.code+"/disable", nil)
+			require.NoError(t, err)
+
+			addAuthWithRole(t, request, server.tokenMaker, authorizationTypeBearer, testAdminUsername, util.AdminRole, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			test.checkResponse(t, server)
+		})
+	}
+}
+
+func TestCurrencyValidationPerServer(t *testing.T) {
+	jpy := db.Currency{Code: "JPY", Exponent: 0, Enabled: true}
+	user := randomUser("temp")
+
+	jpyStore := new(mocks.Store)
+	jpyStore.On("ListCurrencies", mock.Anything).Return(append(testCurrencies, jpy), nil)
+	jpyStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), int64(1)).Return(db.Account{}, sql.ErrNoRows)
+	jpyServer := newTestServer(t, jpyStore)
+
+	// a server made later must not change what the first one accepts
+	otherStore := new(mocks.Store)
+	otherServer := newTestServer(t, otherStore)
+
+	testCases := map[string]struct {
+		server         *server
+		expectedStatus int
+	}{
+		"Registered on the server": {
+			server:         jpyServer,
+			expectedStatus: http.StatusNotFound,
+		},
+		"Unknown to the server": {
+			server:         otherServer,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			data, err := json.Marshal(gin.H{"from_account_id": 1, "to_account_id": 2, "amount": 10, "currency": jpy.Code})
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/transfers/", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuth(t, request, test.server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+
+			test.server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+	jpyStore.AssertExpectations(t)
+}
@@ -0,0 +1,234 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultHoldDuration = 7 * 24 * time.Hour
+	maxHoldDuration     = 30 * 24 * time.Hour
+)
+
+// createHoldRequest.Amount is in the account currency, the hold can only be captured
+// into an account of the same currency. Without ExpiresAt the hold lasts a week.
+type createHoldRequest struct {
+	ToAccountID int64     `json:"to_account_id" binding:"required,min=1"`
+	Amount      int64     `json:"amount" binding:"required,gt=0"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type holdURI struct {
+	ID     int64 `uri:"id" binding:"required,min=1"`
+	HoldID int64 `uri:"hold_id" binding:"required,min=1"`
+}
+
+type listHoldsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=active captured released expired"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// captureHoldRequest.Amount leaves out to capture the whole hold.
+type captureHoldRequest struct {
+	Amount int64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+type holdResponse struct {
+	Hold    db.Hold         `json:"hold"`
+	Account accountResponse `json:"account"`
+}
+
+func (server *server) createHold(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req createHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	now := time.Now()
+	if req.ExpiresAt.IsZero() {
+		req.ExpiresAt = now.Add(defaultHoldDuration)
+	}
+	if !req.ExpiresAt.After(now) || req.ExpiresAt.After(now.Add(maxHoldDuration)) {
+		err := fmt.Errorf("expires_at must be in the future and at most %s away", maxHoldDuration)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.ownedAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+	if req.ToAccountID == account.ID {
+		err := errors.New("a hold can't be placed for the account itself")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if _, valid := server.validAccount(ctx, req.ToAccountID, account.Currency); !valid {
+		return
+	}
+
+	result, err := server.store.PlaceHold(ctx, db.PlaceHoldParams{
+		AccountID:   account.ID,
+		ToAccountID: req.ToAccountID,
+		Amount:      req.Amount,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if err != nil {
+		server.holdError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, holdResponse{Hold: result.Hold, Account: newAccountResponse(result.Account)})
+}
+
+func (server *server) listHolds(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listHoldsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.ownedAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	holds, err := server.store.ListHolds(ctx, db.ListHoldsParams{
+		AccountID:   account.ID,
+		Status:      req.Status,
+		LimitCount:  req.PageSize,
+		OffsetCount: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, holds)
+}
+
+func (server *server) getHold(ctx *gin.Context) {
+	var uri holdURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hold, valid := server.ownedHold(ctx, uri)
+	if !valid {
+		return
+	}
+	ctx.JSON(http.StatusOK, hold)
+}
+
+func (server *server) captureHold(ctx *gin.Context) {
+	var uri holdURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req captureHoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hold, valid := server.ownedHold(ctx, uri)
+	if !valid {
+		return
+	}
+
+	result, err := server.store.CaptureHold(ctx, db.CaptureHoldParams{
+		HoldID: hold.ID,
+		Amount: req.Amount,
+	})
+	if err != nil {
+		server.holdError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (server *server) releaseHold(ctx *gin.Context) {
+	var uri holdURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hold, valid := server.ownedHold(ctx, uri)
+	if !valid {
+		return
+	}
+
+	result, err := server.store.ReleaseHold(ctx, hold.ID)
+	if err != nil {
+		server.holdError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, holdResponse{Hold: result.Hold, Account: newAccountResponse(result.Account)})
+}
+
+// holdError writes the response for an error of the hold store methods.
+func (server *server) holdError(ctx *gin.Context, err error) {
+	var limitErr *db.TransferLimitError
+	switch {
+	case err == sql.ErrNoRows:
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+	case errors.Is(err, db.ErrHoldNotActive), errors.Is(err, db.ErrHoldExpired):
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+	case errors.Is(err, db.ErrHoldCurrencyMismatch):
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	case errors.As(err, &limitErr):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "limit": limitErr})
+	case isUnprocessable(err):
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+	default:
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+func (server *server) ownedHold(ctx *gin.Context, uri holdURI) (db.Hold, bool) {
+	account, valid := server.ownedAccount(ctx, uri.ID)
+	if !valid {
+		return db.Hold{}, false
+	}
+
+	hold, err := server.store.GetHold(ctx, uri.HoldID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return hold, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return hold, false
+	}
+
+	if hold.AccountID != account.ID {
+		err := errors.New("hold does not belong to the account")
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return hold, false
+	}
+	return hold, true
+}
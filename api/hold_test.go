@@ -0,0 +1,351 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/mocks"
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHold(t *testing.T) {
+	user := randomUser("temp")
+	account := randomAccount(user.Username)
+	account.Currency = util.USD
+	merchant := randomAccount("merchant")
+	merchant.ID = account.ID + 1
+	merchant.Currency = util.USD
+	eur := randomAccount("merchant")
+	eur.ID = account.ID + 2
+	eur.Currency = util.EUR
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	testCases := map[string]struct {
+		body           gin.H
+		username       string
+		expectedStatus int
+		stubs          func(store *mocks.Store)
+	}{
+		"OK": {
+			body:           gin.H{"to_account_id": merchant.ID, "amount": 50, "expires_at": expiresAt},
+			username:       user.Username,
+			expectedStatus: http.StatusCreated,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), merchant.ID).Return(*merchant, nil)
+				store.On("PlaceHold", mock.AnythingOfType("*gin.Context"), db.PlaceHoldParams{
+					AccountID:   account.ID,
+					ToAccountID: merchant.ID,
+					Amount:      50,
+					ExpiresAt:   expiresAt,
+				}).Return(db.HoldResult{}, nil)
+			},
+		},
+		"Default expiry": {
+			body:           gin.H{"to_account_id": merchant.ID, "amount": 50},
+			username:       user.Username,
+			expectedStatus: http.StatusCreated,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), merchant.ID).Return(*merchant, nil)
+				store.On("PlaceHold", mock.AnythingOfType("*gin.Context"), mock.MatchedBy(func(arg db.PlaceHoldParams) bool {
+					return time.Until(arg.ExpiresAt) > defaultHoldDuration-time.Minute
+				})).Return(db.HoldResult{}, nil)
+			},
+		},
+		"Expiry too far": {
+			body:           gin.H{"to_account_id": merchant.ID, "amount": 50, "expires_at": time.Now().Add(maxHoldDuration + time.Hour)},
+			username:       user.Username,
+			expectedStatus: http.StatusBadRequest,
+			stubs:          func(store *mocks.Store) {},
+		},
+		"Other currency": {
+			body:           gin.H{"to_account_id": eur.ID, "amount": 50},
+			username:       user.Username,
+			expectedStatus: http.StatusBadRequest,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), eur.ID).Return(*eur, nil)
+			},
+		},
+		"Insufficient funds": {
+			body:           gin.H{"to_account_id": merchant.ID, "amount": 50},
+			username:       user.Username,
+			expectedStatus: http.StatusUnprocessableEntity,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), merchant.ID).Return(*merchant, nil)
+				store.On("PlaceHold", mock.AnythingOfType("*gin.Context"), mock.Anything).Return(db.HoldResult{}, db.ErrInsufficientFunds)
+			},
+		},
+		"Over the limit": {
+			body:           gin.H{"to_account_id": merchant.ID, "amount": 50},
+			username:       user.Username,
+			expectedStatus: http.StatusUnprocessableEntity,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), merchant.ID).Return(*merchant, nil)
+				store.On("PlaceHold", mock.AnythingOfType("*gin.Context"), mock.Anything).Return(db.HoldResult{}, &db.TransferLimitError{
+					Limit:    db.LimitDaily,
+					Currency: util.USD,
+					Max:      100,
+					Used:     80,
+					Headroom: 20,
+				})
+			},
+		},
+		"Same account": {
+			body:           gin.H{"to_account_id": account.ID, "amount": 50},
+			username:       user.Username,
+			expectedStatus: http.StatusBadRequest,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+			},
+		},
+		"Unauthorized user": {
+			body:           gin.H{"to_account_id": merchant.ID, "amount": 50},
+			username:       "unauthorized",
+			expectedStatus: http.StatusUnauthorized,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := new(mocks.Store)
+			test.stubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(test.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/accounts/%d/holds", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuth(t, request, server.tokenMaker, authorizationTypeBearer, test.username, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCaptureHold(t *testing.T) {
+	user := randomUser("temp")
+	account := randomAccount(user.Username)
+	hold := db.Hold{
+		ID:          util.RandomInt(1, 1000),
+		AccountID:   account.ID,
+		ToAccountID: account.ID + 1,
+		Amount:      80,
+		Status:      db.HoldStatusActive,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	testCases := map[string]struct {
+		body           gin.H
+		holdID         int64
+		expectedStatus int
+		stubs          func(store *mocks.Store)
+	}{
+		"Full capture": {
+			holdID:         hold.ID,
+			expectedStatus: http.StatusOK,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetHold", mock.AnythingOfType("*gin.Context"), hold.ID).Return(hold, nil)
+				store.On("CaptureHold", mock.AnythingOfType("*gin.Context"), db.CaptureHoldParams{HoldID: hold.ID}).
+					Return(db.CaptureHoldResult{}, nil)
+			},
+		},
+		"Partial capture": {
+			body:           gin.H{"amount": 30},
+			holdID:         hold.ID,
+			expectedStatus: http.StatusOK,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetHold", mock.AnythingOfType("*gin.Context"), hold.ID).Return(hold, nil)
+				store.On("CaptureHold", mock.AnythingOfType("*gin.Context"), db.CaptureHoldParams{HoldID: hold.ID, Amount: 30}).
+					Return(db.CaptureHoldResult{}, nil)
+			},
+		},
+		"Exceeds hold": {
+			body:           gin.H{"amount": 90},
+			holdID:         hold.ID,
+			expectedStatus: http.StatusUnprocessableEntity,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetHold", mock.AnythingOfType("*gin.Context"), hold.ID).Return(hold, nil)
+				store.On("CaptureHold", mock.AnythingOfType("*gin.Context"), mock.Anything).
+					Return(db.CaptureHoldResult{}, fmt.Errorf("%w: 80 held", db.ErrCaptureExceedsHold))
+			},
+		},
+		"Not active": {
+			holdID:         hold.ID,
+			expectedStatus: http.StatusConflict,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetHold", mock.AnythingOfType("*gin.Context"), hold.ID).Return(hold, nil)
+				store.On("CaptureHold", mock.AnythingOfType("*gin.Context"), mock.Anything).
+					Return(db.CaptureHoldResult{}, db.ErrHoldNotActive)
+			},
+		},
+		"Expired": {
+			holdID:         hold.ID,
+			expectedStatus: http.StatusConflict,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetHold", mock.AnythingOfType("*gin.Context"), hold.ID).Return(hold, nil)
+				store.On("CaptureHold", mock.AnythingOfType("*gin.Context"), mock.Anything).
+					Return(db.CaptureHoldResult{}, db.ErrHoldExpired)
+			},
+		},
+		"Hold of another account": {
+			holdID:         hold.ID,
+			expectedStatus: http.StatusNotFound,
+			stubs: func(store *mocks.Store) {
+				other := hold
+				other.AccountID = account.ID + 5
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetHold", mock.AnythingOfType("*gin.Context"), hold.ID).Return(other, nil)
+			},
+		},
+		"Not found": {
+			holdID:         hold.ID,
+			expectedStatus: http.StatusNotFound,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetHold", mock.AnythingOfType("*gin.Context"), hold.ID).Return(db.Hold{}, sql.ErrNoRows)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := new(mocks.Store)
+			test.stubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body []byte
+			if test.body != nil {
+				var err error
+				body, err = json.Marshal(test.body)
+				require.NoError(t, err)
+			}
+
+			url := fmt.Sprintf("/accounts/%d/holds/%d/capture", account.ID, test.holdID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+			require.NoError(t, err)
+			addAuth(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReleaseHold(t *testing.T) {
+	user := randomUser("temp")
+	account := randomAccount(user.Username)
+	hold := db.Hold{ID: util.RandomInt(1, 1000), AccountID: account.ID, Amount: 80, Status: db.HoldStatusActive}
+
+	testCases := map[string]struct {
+		expectedStatus int
+		stubs          func(store *mocks.Store)
+	}{
+		"OK": {
+			expectedStatus: http.StatusOK,
+			stubs: func(store *mocks.Store) {
+				released := hold
+				released.Status = db.HoldStatusReleased
+				store.On("ReleaseHold", mock.AnythingOfType("*gin.Context"), hold.ID).
+					Return(db.HoldResult{Hold: released, Account: *account}, nil)
+			},
+		},
+		"Already captured": {
+			expectedStatus: http.StatusConflict,
+			stubs: func(store *mocks.Store) {
+				store.On("ReleaseHold", mock.AnythingOfType("*gin.Context"), hold.ID).
+					Return(db.HoldResult{}, fmt.Errorf("%w: %d is captured", db.ErrHoldNotActive, hold.ID))
+			},
+		},
+		"Internal error": {
+			expectedStatus: http.StatusInternalServerError,
+			stubs: func(store *mocks.Store) {
+				store.On("ReleaseHold", mock.AnythingOfType("*gin.Context"), hold.ID).
+					Return(db.HoldResult{}, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := new(mocks.Store)
+			store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+			store.On("GetHold", mock.AnythingOfType("*gin.Context"), hold.ID).Return(hold, nil)
+			test.stubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/holds/%d/release", account.ID, hold.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+			addAuth(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetAccountBalances(t *testing.T) {
+	user := randomUser("temp")
+	account := randomAccount(user.Username)
+	account.Balance = 100
+	account.OverdraftLimit = 20
+	account.HeldBalance = 70
+
+	store := new(mocks.Store)
+	store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d", account.ID), nil)
+	require.NoError(t, err)
+	addAuth(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp struct {
+		Balance          int64 `json:"balance"`
+		HeldBalance      int64 `json:"heldBalance"`
+		AvailableBalance int64 `json:"availableBalance"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, int64(100), rsp.Balance)
+	require.Equal(t, int64(70), rsp.HeldBalance)
+	require.Equal(t, int64(50), rsp.AvailableBalance)
+}
@@ -5,16 +5,38 @@ import (
 	"testing"
 	"time"
 
+	"github.com/RahilRehan/banco/db/mocks"
 	db "github.com/RahilRehan/banco/db/sqlc"
 	"github.com/RahilRehan/banco/db/util"
 	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
-func newTestServer(t *testing.T, store db.Store) *server {
+const testAdminUsername = "admin"
+
+var testCurrencies = []db.Currency{
+	{Code: util.USD, Exponent: 2, Enabled: true},
+	{Code: util.EUR, Exponent: 2, Enabled: true},
+	{Code: util.CAD, Exponent: 2, Enabled: true},
+}
+
+// newTestServer loads testCurrencies into the registry unless the test stubbed ListCurrencies itself,
+// audit events of state-changing requests are accepted and dropped.
+func newTestServer(t *testing.T, store *mocks.Store) *server {
+	return newTestServerWithConfig(t, store, func(config *util.Config) {})
+}
+
+func newTestServerWithConfig(t *testing.T, store *mocks.Store, configure func(config *util.Config)) *server {
+	store.On("ListCurrencies", mock.Anything).Return(testCurrencies, nil)
+	store.On("CreateAuditEvent", mock.Anything, mock.AnythingOfType("db.CreateAuditEventParams")).Return(db.AuditEvent{}, nil).Maybe()
+
 	config := util.Config{
-		ACCESS_TOKEN_DURATION: time.Minute,
+		ACCESS_TOKEN_DURATION:  time.Minute,
+		REFRESH_TOKEN_DURATION: time.Hour,
+		TOKEN_SYMMETRIC_KEY:    util.RandomString(32),
 	}
+	configure(&config)
 	server, err := NewServer(config, store)
 	require.NoError(t, err)
 	return server
@@ -25,3 +47,37 @@ func TestMain(m *testing.M) {
 
 	os.Exit(m.Run())
 }
+
+func TestNewServerInvalidConfig(t *testing.T) {
+	testCases := map[string]func(config *util.Config){
+		"Unknown token type": func(config *util.Config) {
+			config.TOKEN_TYPE = "macaroon"
+		},
+		"Missing token key": func(config *util.Config) {
+			config.TOKEN_SYMMETRIC_KEY = ""
+		},
+		"No access token duration": func(config *util.Config) {
+			config.ACCESS_TOKEN_DURATION = 0
+		},
+		"Refresh shorter than access": func(config *util.Config) {
+			config.REFRESH_TOKEN_DURATION = time.Second
+		},
+	}
+
+	for name, configure := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := new(mocks.Store)
+			store.On("ListCurrencies", mock.Anything).Return(testCurrencies, nil)
+
+			config := util.Config{
+				ACCESS_TOKEN_DURATION:  time.Minute,
+				REFRESH_TOKEN_DURATION: time.Hour,
+				TOKEN_SYMMETRIC_KEY:    util.RandomString(32),
+			}
+			configure(&config)
+
+			_, err := NewServer(config, store)
+			require.Error(t, err)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	db "github.com/RahilRehan/banco/db/sqlc"
 	"github.com/RahilRehan/banco/token"
 	"github.com/gin-gonic/gin"
 )
@@ -16,8 +17,10 @@ const (
 	authorizationPayloadKey = "authorization_payload"
 )
 
+var errRevokedToken = errors.New("token has been revoked")
+
 // AuthMiddleware creates a gin middleware for authorization
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+func authMiddleware(tokenMaker token.Maker, revocations token.RevocationStore) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 
@@ -47,8 +50,45 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
+		if payload.Type != token.AccessToken {
+			err := fmt.Errorf("%w: an access token is required", token.ErrWrongTokenType)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+
+		revoked, err := revocations.IsRevoked(ctx, payload)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if revoked {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errRevokedToken))
+			return
+		}
 
 		ctx.Set(authorizationPayloadKey, payload)
+		if audit, ok := ctx.Value(db.AuditContextKey).(*db.AuditContext); ok {
+			audit.Actor = payload.Username
+		}
+		ctx.Next()
+	}
+}
+
+// roleMiddleware creates a gin middleware that only lets users with one of the given
+// roles through, it must run after authMiddleware
+func roleMiddleware(roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if !allowed[authPayload.Role] {
+			err := fmt.Errorf("role %q is not allowed, requires one of %s", authPayload.Role, strings.Join(roles, ", "))
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
 		ctx.Next()
 	}
 }
@@ -1,19 +1,26 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/RahilRehan/banco/db/mocks"
+	"github.com/RahilRehan/banco/db/util"
 	"github.com/RahilRehan/banco/token"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 )
 
 func addAuth(t *testing.T, req *http.Request, maker token.Maker, authorizationType string, username string, duration time.Duration) {
-	token, err := maker.CreateToken(username, duration)
+	addAuthWithRole(t, req, maker, authorizationType, username, util.CustomerRole, duration)
+}
+
+func addAuthWithRole(t *testing.T, req *http.Request, maker token.Maker, authorizationType string, username string, role string, duration time.Duration) {
+	token, _, err := maker.CreateToken(username, role, token.AccessToken, duration)
 	require.NoError(t, err)
 
 	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, token)
@@ -56,6 +63,16 @@ func TestMiddleware(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, rec.Code)
 			},
 		},
+		"Refresh token": {
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				refreshToken, _, err := maker.CreateToken("username", util.CustomerRole, token.RefreshToken, time.Hour)
+				require.NoError(t, err)
+				req.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, refreshToken))
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, rec.Code)
+			},
+		},
 		"Expired token": {
 			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
 				addAuth(t, req, maker, authorizationTypeBearer, "username", -time.Minute)
@@ -66,13 +83,105 @@ func TestMiddleware(t *testing.T) {
 		},
 	}
 
+	privateKey, _, err := token.GenerateEd25519Keys()
+	require.NoError(t, err)
+
+	makerConfigs := map[string]func(config *util.Config){
+		"v2.local": func(config *util.Config) {},
+		"v2.public": func(config *util.Config) {
+			config.TOKEN_TYPE = token.TokenTypePasetoPublic
+			config.TOKEN_PRIVATE_KEY = privateKey
+		},
+		"jwt": func(config *util.Config) {
+			config.TOKEN_TYPE = token.TokenTypeJWT
+		},
+	}
+
+	for makerName, configure := range makerConfigs {
+		for name, test := range testCases {
+			t.Run(makerName+"/"+name, func(t *testing.T) {
+				server := newTestServerWithConfig(t, new(mocks.Store), configure)
+				authPath := "/auth"
+				server.router.GET(
+					authPath,
+					authMiddleware(server.tokenMaker, server.revocations),
+					func(c *gin.Context) {
+						c.JSON(http.StatusOK, gin.H{})
+					},
+				)
+
+				rec := httptest.NewRecorder()
+				req, err := http.NewRequest(http.MethodGet, authPath, nil)
+				require.NoError(t, err)
+
+				test.setupAuth(t, req, server.tokenMaker)
+				server.router.ServeHTTP(rec, req)
+				test.checkResponse(t, rec)
+			})
+		}
+	}
+}
+
+func TestMiddlewareRevokedToken(t *testing.T) {
+	server := newTestServer(t, new(mocks.Store))
+	authPath := "/auth"
+	server.router.GET(
+		authPath,
+		authMiddleware(server.tokenMaker, server.revocations),
+		func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{})
+		},
+	)
+
+	accessToken, payload, err := server.tokenMaker.CreateToken("username", util.CustomerRole, token.AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	request := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodGet, authPath, nil)
+		require.NoError(t, err)
+		req.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+		server.router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusOK, request().Code)
+
+	require.NoError(t, server.revocations.RevokeToken(context.Background(), payload))
+	require.Equal(t, http.StatusUnauthorized, request().Code)
+}
+
+func TestRoleMiddleware(t *testing.T) {
+	testCases := map[string]struct {
+		role           string
+		expectedStatus int
+	}{
+		"Support": {
+			role:           util.SupportRole,
+			expectedStatus: http.StatusOK,
+		},
+		"Admin": {
+			role:           util.AdminRole,
+			expectedStatus: http.StatusOK,
+		},
+		"Customer": {
+			role:           util.CustomerRole,
+			expectedStatus: http.StatusForbidden,
+		},
+		"No role": {
+			role:           "",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
 	for name, test := range testCases {
 		t.Run(name, func(t *testing.T) {
-			server := newTestServer(t, nil)
-			authPath := "/auth"
+			server := newTestServer(t, new(mocks.Store))
+			authPath := "/staff"
 			server.router.GET(
 				authPath,
-				authMiddleware(server.tokenMaker),
+				authMiddleware(server.tokenMaker, server.revocations),
+				roleMiddleware(util.SupportRole, util.AdminRole),
 				func(c *gin.Context) {
 					c.JSON(http.StatusOK, gin.H{})
 				},
@@ -82,9 +191,9 @@ func TestMiddleware(t *testing.T) {
 			req, err := http.NewRequest(http.MethodGet, authPath, nil)
 			require.NoError(t, err)
 
-			test.setupAuth(t, req, server.tokenMaker)
+			addAuthWithRole(t, req, server.tokenMaker, authorizationTypeBearer, "username", test.role, time.Minute)
 			server.router.ServeHTTP(rec, req)
-			test.checkResponse(t, rec)
+			require.Equal(t, test.expectedStatus, rec.Code)
 		})
 	}
 }
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/RahilRehan/banco/token"
+	"github.com/gin-gonic/gin"
+)
+
+type reverseTransferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// reverseTransferRequest.Amount is in the currency of the original source account,
+// leaving it out refunds everything that hasn't been refunded yet.
+type reverseTransferRequest struct {
+	Amount int64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// reverseTransfer sends a transfer back, fully or in part. The owner of the account that
+// received the money can refund it, admins can reverse any transfer.
+func (server *server) reverseTransfer(ctx *gin.Context) {
+	var uri reverseTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req reverseTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.Role != util.AdminRole {
+		if _, valid := server.ownedAccount(ctx, transfer.ToAccountID); !valid {
+			return
+		}
+	}
+
+	result, err := server.store.ReverseTransferTx(ctx, db.ReverseTransferTxParams{
+		TransferID: transfer.ID,
+		Amount:     req.Amount,
+	})
+	if err != nil {
+		if isUnprocessable(err) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
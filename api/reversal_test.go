@@ -0,0 +1,165 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/mocks"
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseTransfer(t *testing.T) {
+	user := randomUser("temp")
+	receiver := randomAccount(user.Username)
+	transfer := db.Transfer{
+		ID:            util.RandomInt(1, 1000),
+		FromAccountID: receiver.ID + 1,
+		ToAccountID:   receiver.ID,
+		Amount:        100,
+		ToAmount:      100,
+		ExchangeRate:  "1",
+		Status:        db.TransferStatusCompleted,
+	}
+
+	testCases := map[string]struct {
+		body           gin.H
+		username       string
+		role           string
+		expectedStatus int
+		stubs          func(store *mocks.Store)
+	}{
+		"Full refund": {
+			username:       user.Username,
+			expectedStatus: http.StatusOK,
+			stubs: func(store *mocks.Store) {
+				store.On("GetTransfer", mock.AnythingOfType("*gin.Context"), transfer.ID).Return(transfer, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), receiver.ID).Return(*receiver, nil)
+				store.On("ReverseTransferTx", mock.AnythingOfType("*gin.Context"), db.ReverseTransferTxParams{TransferID: transfer.ID}).
+					Return(db.ReverseTransferTxResult{}, nil)
+			},
+		},
+		"Partial refund": {
+			body:           gin.H{"amount": 40},
+			username:       user.Username,
+			expectedStatus: http.StatusOK,
+			stubs: func(store *mocks.Store) {
+				store.On("GetTransfer", mock.AnythingOfType("*gin.Context"), transfer.ID).Return(transfer, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), receiver.ID).Return(*receiver, nil)
+				store.On("ReverseTransferTx", mock.AnythingOfType("*gin.Context"), db.ReverseTransferTxParams{TransferID: transfer.ID, Amount: 40}).
+					Return(db.ReverseTransferTxResult{}, nil)
+			},
+		},
+		"Admin": {
+			username:       testAdminUsername,
+			role:           util.AdminRole,
+			expectedStatus: http.StatusOK,
+			stubs: func(store *mocks.Store) {
+				store.On("GetTransfer", mock.AnythingOfType("*gin.Context"), transfer.ID).Return(transfer, nil)
+				store.On("ReverseTransferTx", mock.AnythingOfType("*gin.Context"), db.ReverseTransferTxParams{TransferID: transfer.ID}).
+					Return(db.ReverseTransferTxResult{}, nil)
+			},
+		},
+		"Refund exceeds transfer": {
+			body:           gin.H{"amount": 400},
+			username:       user.Username,
+			expectedStatus: http.StatusUnprocessableEntity,
+			stubs: func(store *mocks.Store) {
+				store.On("GetTransfer", mock.AnythingOfType("*gin.Context"), transfer.ID).Return(transfer, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), receiver.ID).Return(*receiver, nil)
+				store.On("ReverseTransferTx", mock.AnythingOfType("*gin.Context"), mock.Anything).
+					Return(db.ReverseTransferTxResult{}, fmt.Errorf("%w: 100 left", db.ErrRefundExceedsTransfer))
+			},
+		},
+		"Already reversed": {
+			username:       user.Username,
+			expectedStatus: http.StatusUnprocessableEntity,
+			stubs: func(store *mocks.Store) {
+				store.On("GetTransfer", mock.AnythingOfType("*gin.Context"), transfer.ID).Return(transfer, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), receiver.ID).Return(*receiver, nil)
+				store.On("ReverseTransferTx", mock.AnythingOfType("*gin.Context"), mock.Anything).
+					Return(db.ReverseTransferTxResult{}, db.ErrTransferNotReversible)
+			},
+		},
+		"Insufficient funds": {
+			username:       user.Username,
+			expectedStatus: http.StatusUnprocessableEntity,
+			stubs: func(store *mocks.Store) {
+				store.On("GetTransfer", mock.AnythingOfType("*gin.Context"), transfer.ID).Return(transfer, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), receiver.ID).Return(*receiver, nil)
+				store.On("ReverseTransferTx", mock.AnythingOfType("*gin.Context"), mock.Anything).
+					Return(db.ReverseTransferTxResult{}, db.ErrInsufficientFunds)
+			},
+		},
+		"Invalid amount": {
+			body:           gin.H{"amount": -5},
+			username:       user.Username,
+			expectedStatus: http.StatusBadRequest,
+			stubs:          func(store *mocks.Store) {},
+		},
+		"Transfer not found": {
+			username:       user.Username,
+			expectedStatus: http.StatusNotFound,
+			stubs: func(store *mocks.Store) {
+				store.On("GetTransfer", mock.AnythingOfType("*gin.Context"), transfer.ID).Return(db.Transfer{}, sql.ErrNoRows)
+			},
+		},
+		"Not the receiver": {
+			username:       "unauthorized",
+			expectedStatus: http.StatusUnauthorized,
+			stubs: func(store *mocks.Store) {
+				store.On("GetTransfer", mock.AnythingOfType("*gin.Context"), transfer.ID).Return(transfer, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), receiver.ID).Return(*receiver, nil)
+			},
+		},
+		"Internal error": {
+			username:       user.Username,
+			expectedStatus: http.StatusInternalServerError,
+			stubs: func(store *mocks.Store) {
+				store.On("GetTransfer", mock.AnythingOfType("*gin.Context"), transfer.ID).Return(transfer, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), receiver.ID).Return(*receiver, nil)
+				store.On("ReverseTransferTx", mock.AnythingOfType("*gin.Context"), mock.Anything).
+					Return(db.ReverseTransferTxResult{}, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := new(mocks.Store)
+			test.stubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body []byte
+			if test.body != nil {
+				var err error
+				body, err = json.Marshal(test.body)
+				require.NoError(t, err)
+			}
+
+			url := fmt.Sprintf("/transfers/%d/reverse", transfer.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+			require.NoError(t, err)
+			role := test.role
+			if role == "" {
+				role = util.CustomerRole
+			}
+			addAuthWithRole(t, request, server.tokenMaker, authorizationTypeBearer, test.username, role, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			store.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/schedule"
+	"github.com/gin-gonic/gin"
+)
+
+const scheduledTransferRunsShown = 20
+
+// createScheduledTransferRequest runs once at StartAt when Schedule is empty, otherwise
+// Schedule is a cron expression or an RRULE starting at StartAt, in UTC. Scheduled
+// transfers stay within one currency.
+type createScheduledTransferRequest struct {
+	ToAccountID int64     `json:"to_account_id" binding:"required,min=1"`
+	Amount      int64     `json:"amount" binding:"required,gt=0"`
+	Currency    string    `json:"currency" binding:"required,currency"`
+	StartAt     time.Time `json:"start_at" binding:"required"`
+	Schedule    string    `json:"schedule" binding:"max=200"`
+}
+
+type updateScheduledTransferRequest struct {
+	Amount   int64     `json:"amount" binding:"required,gt=0"`
+	StartAt  time.Time `json:"start_at" binding:"required"`
+	Schedule string    `json:"schedule" binding:"max=200"`
+}
+
+type scheduledTransferURI struct {
+	ID          int64 `uri:"id" binding:"required,min=1"`
+	ScheduledID int64 `uri:"scheduled_id" binding:"required,min=1"`
+}
+
+type scheduledTransferResponse struct {
+	db.ScheduledTransfer
+	Runs []db.ScheduledTransferRun `json:"runs"`
+}
+
+func (server *server) createScheduledTransfer(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req createScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !server.knownCurrencies(ctx, req.Currency) {
+		return
+	}
+
+	fromAccount, valid := server.ownedAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+	if fromAccount.Status == db.AccountStatusClosed {
+		err := fmt.Errorf("%w: %d", db.ErrAccountClosed, fromAccount.ID)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+	if fromAccount.ID == req.ToAccountID {
+		err := errors.New("a scheduled transfer needs two different accounts")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if fromAccount.Currency != req.Currency {
+		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", fromAccount.ID, fromAccount.Currency, req.Currency)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if _, valid := server.validAccount(ctx, req.ToAccountID, req.Currency); !valid {
+		return
+	}
+
+	nextRunAt, valid := firstRun(ctx, req.Schedule, req.StartAt)
+	if !valid {
+		return
+	}
+
+	scheduled, err := server.store.CreateScheduledTransfer(ctx, db.CreateScheduledTransferParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		Schedule:      req.Schedule,
+		StartAt:       req.StartAt,
+		NextRunAt:     sql.NullTime{Time: nextRunAt, Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusCreated, scheduled)
+}
+
+func (server *server) listScheduledTransfers(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.ownedAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	scheduled, err := server.store.ListScheduledTransfers(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, scheduled)
+}
+
+// getScheduledTransfer returns a scheduled transfer with its latest runs.
+func (server *server) getScheduledTransfer(ctx *gin.Context) {
+	var uri scheduledTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, valid := server.ownedScheduledTransfer(ctx, uri)
+	if !valid {
+		return
+	}
+
+	runs, err := server.store.ListScheduledTransferRuns(ctx, db.ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduled.ID,
+		Limit:               scheduledTransferRunsShown,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, scheduledTransferResponse{ScheduledTransfer: scheduled, Runs: runs})
+}
+
+func (server *server) updateScheduledTransfer(ctx *gin.Context) {
+	var uri scheduledTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req updateScheduledTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, valid := server.ownedScheduledTransfer(ctx, uri)
+	if !valid {
+		return
+	}
+
+	nextRunAt, valid := firstRun(ctx, req.Schedule, req.StartAt)
+	if !valid {
+		return
+	}
+
+	scheduled, err := server.store.UpdateScheduledTransfer(ctx, db.UpdateScheduledTransferParams{
+		ID:        scheduled.ID,
+		Amount:    req.Amount,
+		Schedule:  req.Schedule,
+		StartAt:   req.StartAt,
+		NextRunAt: sql.NullTime{Time: nextRunAt, Valid: true},
+	})
+	if err != nil {
+		scheduledTransferNotActive(ctx, uri.ScheduledID, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, scheduled)
+}
+
+// cancelScheduledTransfer stops a scheduled transfer, its runs are kept.
+func (server *server) cancelScheduledTransfer(ctx *gin.Context) {
+	var uri scheduledTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scheduled, valid := server.ownedScheduledTransfer(ctx, uri)
+	if !valid {
+		return
+	}
+
+	scheduled, err := server.store.CancelScheduledTransfer(ctx, scheduled.ID)
+	if err != nil {
+		scheduledTransferNotActive(ctx, uri.ScheduledID, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, scheduled)
+}
+
+// scheduledTransferNotActive writes the error of an update that only applies to active
+// scheduled transfers.
+func scheduledTransferNotActive(ctx *gin.Context, id int64, err error) {
+	if err == sql.ErrNoRows {
+		err := fmt.Errorf("scheduled transfer %d is no longer active", id)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+}
+
+// ownedScheduledTransfer loads a scheduled transfer of an account of the authenticated
+// user, writing the error response itself when there is none.
+func (server *server) ownedScheduledTransfer(ctx *gin.Context, uri scheduledTransferURI) (db.ScheduledTransfer, bool) {
+	account, valid := server.ownedAccount(ctx, uri.ID)
+	if !valid {
+		return db.ScheduledTransfer{}, false
+	}
+
+	scheduled, err := server.store.GetScheduledTransfer(ctx, uri.ScheduledID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return scheduled, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return scheduled, false
+	}
+
+	if scheduled.FromAccountID != account.ID {
+		err := errors.New("scheduled transfer does not belong to the account")
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return scheduled, false
+	}
+	return scheduled, true
+}
+
+// firstRun checks a schedule and returns its first run from now on, writing the error
+// response itself when the schedule is invalid or never runs.
+func firstRun(ctx *gin.Context, spec string, startAt time.Time) (time.Time, bool) {
+	s, err := schedule.Parse(spec, startAt)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return time.Time{}, false
+	}
+
+	from := startAt
+	if now := time.Now(); now.After(from) {
+		from = now
+	}
+	next := schedule.First(s, from)
+	if next.IsZero() {
+		err := errors.New("the schedule has no run after now")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return time.Time{}, false
+	}
+	return next, true
+}
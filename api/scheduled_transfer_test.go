@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/mocks"
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateScheduledTransfer(t *testing.T) {
+	user := randomUser("temp")
+	from := randomAccount(user.Username)
+	from.Currency = util.USD
+	to := randomAccount("someone")
+	to.ID = from.ID + 1
+	to.Currency = util.USD
+	eur := randomAccount("someone")
+	eur.ID = from.ID + 2
+	eur.Currency = util.EUR
+
+	startAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	testCases := map[string]struct {
+		body           gin.H
+		username       string
+		expectedStatus int
+		stubs          func(store *mocks.Store)
+	}{
+		"One-off": {
+			body:           gin.H{"to_account_id": to.ID, "amount": 100, "currency": util.USD, "start_at": startAt},
+			username:       user.Username,
+			expectedStatus: http.StatusCreated,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), to.ID).Return(*to, nil)
+				store.On("CreateScheduledTransfer", mock.AnythingOfType("*gin.Context"), mock.MatchedBy(func(arg db.CreateScheduledTransferParams) bool {
+					return arg.FromAccountID == from.ID && arg.ToAccountID == to.ID && arg.Amount == 100 &&
+						arg.NextRunAt.Valid && arg.NextRunAt.Time.Equal(startAt)
+				})).Return(db.ScheduledTransfer{ID: 1}, nil)
+			},
+		},
+		"Recurring": {
+			body:           gin.H{"to_account_id": to.ID, "amount": 100, "currency": util.USD, "start_at": startAt, "schedule": "0 9 1 * *"},
+			username:       user.Username,
+			expectedStatus: http.StatusCreated,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), to.ID).Return(*to, nil)
+				store.On("CreateScheduledTransfer", mock.AnythingOfType("*gin.Context"), mock.MatchedBy(func(arg db.CreateScheduledTransferParams) bool {
+					next := arg.NextRunAt.Time
+					return arg.Schedule == "0 9 1 * *" && next.Day() == 1 && next.Hour() == 9 && !next.Before(startAt)
+				})).Return(db.ScheduledTransfer{ID: 1}, nil)
+			},
+		},
+		"One-off in the past": {
+			body:           gin.H{"to_account_id": to.ID, "amount": 100, "currency": util.USD, "start_at": startAt.Add(-2 * time.Hour)},
+			username:       user.Username,
+			expectedStatus: http.StatusBadRequest,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), to.ID).Return(*to, nil)
+			},
+		},
+		"Invalid schedule": {
+			body:           gin.H{"to_account_id": to.ID, "amount": 100, "currency": util.USD, "start_at": startAt, "schedule": "every monday"},
+			username:       user.Username,
+			expectedStatus: http.StatusBadRequest,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), to.ID).Return(*to, nil)
+			},
+		},
+		"Other currency": {
+			body:           gin.H{"to_account_id": eur.ID, "amount": 100, "currency": util.USD, "start_at": startAt},
+			username:       user.Username,
+			expectedStatus: http.StatusBadRequest,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), eur.ID).Return(*eur, nil)
+			},
+		},
+		"Same account": {
+			body:           gin.H{"to_account_id": from.ID, "amount": 100, "currency": util.USD, "start_at": startAt},
+			username:       user.Username,
+			expectedStatus: http.StatusBadRequest,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+			},
+		},
+		"Destination not found": {
+			body:           gin.H{"to_account_id": to.ID, "amount": 100, "currency": util.USD, "start_at": startAt},
+			username:       user.Username,
+			expectedStatus: http.StatusNotFound,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), to.ID).Return(db.Account{}, sql.ErrNoRows)
+			},
+		},
+		"Unauthorized user": {
+			body:           gin.H{"to_account_id": to.ID, "amount": 100, "currency": util.USD, "start_at": startAt},
+			username:       "unauthorized",
+			expectedStatus: http.StatusUnauthorized,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := new(mocks.Store)
+			test.stubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(test.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/accounts/%d/scheduled-transfers", from.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuth(t, request, server.tokenMaker, authorizationTypeBearer, test.username, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCancelScheduledTransfer(t *testing.T) {
+	user := randomUser("temp")
+	account := randomAccount(user.Username)
+	scheduled := db.ScheduledTransfer{ID: 7, FromAccountID: account.ID, Status: db.ScheduledTransferStatusActive}
+
+	testCases := map[string]struct {
+		stubs          func(store *mocks.Store)
+		expectedStatus int
+	}{
+		"OK": {
+			stubs: func(store *mocks.Store) {
+				store.On("GetScheduledTransfer", mock.AnythingOfType("*gin.Context"), scheduled.ID).Return(scheduled, nil)
+				store.On("CancelScheduledTransfer", mock.AnythingOfType("*gin.Context"), scheduled.ID).Return(db.ScheduledTransfer{ID: scheduled.ID, Status: db.ScheduledTransferStatusCancelled}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		"Not active": {
+			stubs: func(store *mocks.Store) {
+				store.On("GetScheduledTransfer", mock.AnythingOfType("*gin.Context"), scheduled.ID).Return(scheduled, nil)
+				store.On("CancelScheduledTransfer", mock.AnythingOfType("*gin.Context"), scheduled.ID).Return(db.ScheduledTransfer{}, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		"Other account": {
+			stubs: func(store *mocks.Store) {
+				other := scheduled
+				other.FromAccountID = account.ID + 1
+				store.On("GetScheduledTransfer", mock.AnythingOfType("*gin.Context"), scheduled.ID).Return(other, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := new(mocks.Store)
+			store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+			test.stubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/scheduled-transfers/%d", account.ID, scheduled.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+			addAuth(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			store.AssertExpectations(t)
+		})
+	}
+}
@@ -1,43 +1,75 @@
 package api
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"net"
 
+	"github.com/RahilRehan/banco/currency"
 	db "github.com/RahilRehan/banco/db/sqlc"
 	"github.com/RahilRehan/banco/db/util"
+	"github.com/RahilRehan/banco/fx"
 	"github.com/RahilRehan/banco/token"
+	"github.com/RahilRehan/banco/webhook"
 	"github.com/gin-gonic/gin"
-	"github.com/gin-gonic/gin/binding"
-	"github.com/go-playground/validator/v10"
 )
 
 type server struct {
-	config     util.Config
-	store      db.Store
-	router     *gin.Engine
-	tokenMaker token.Maker
+	config       util.Config
+	store        db.Store
+	router       *gin.Engine
+	tokenMaker   token.Maker
+	tokenKeys    *token.KeyRing
+	revocations  token.RevocationStore
+	rateProvider fx.RateProvider
+	currencies   *currency.Registry
+	resolver     webhook.Resolver
 }
 
 func (s *server) Start(address string) error {
 	return s.router.Run(address)
 }
 
+// ReloadTokenKeys reads the token signing keys again, so keys can be rotated or
+// retired without a restart.
+func (s *server) ReloadTokenKeys() error {
+	return s.tokenKeys.Reload()
+}
+
 func NewServer(cfg util.Config, store db.Store) (*server, error) {
-	symmetricKey := os.Getenv("TOKEN_SYMMETRIC_KEY")
-	tokenMaker, err := token.NewPasetoMaker(symmetricKey)
+	if cfg.ACCESS_TOKEN_DURATION <= 0 || cfg.REFRESH_TOKEN_DURATION < cfg.ACCESS_TOKEN_DURATION {
+		return nil, fmt.Errorf("invalid token durations: access %s must be positive and refresh %s at least as long", cfg.ACCESS_TOKEN_DURATION, cfg.REFRESH_TOKEN_DURATION)
+	}
+
+	tokenMaker, tokenKeys, err := token.NewMaker(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
-	server := &server{
-		store:      store,
-		tokenMaker: tokenMaker,
-		config:     cfg,
+	revocations, err := token.NewRevocationStore(cfg, store)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create revocation store: %w", err)
 	}
 
-	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		v.RegisterValidation("currency", validateCurrency)
+	rateProvider, err := fx.NewRateProvider(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create rate provider: %w", err)
+	}
+
+	currencies, err := currency.NewRegistry(context.Background(), store)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &server{
+		store:        store,
+		tokenMaker:   tokenMaker,
+		tokenKeys:    tokenKeys,
+		revocations:  revocations,
+		rateProvider: rateProvider,
+		currencies:   currencies,
+		resolver:     net.DefaultResolver,
+		config:       cfg,
 	}
 
 	server.setupRouter()
@@ -47,19 +79,70 @@ func NewServer(cfg util.Config, store db.Store) (*server, error) {
 
 func (server *server) setupRouter() {
 	router := gin.Default()
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+	router.Use(auditMiddleware(server.store))
+
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.revocations))
 
 	authRoutes.POST("/accounts/", server.createAccount)
 	authRoutes.GET("/accounts/:id", server.getAccount)
 	authRoutes.GET("/accounts/", server.listAccounts)
-	authRoutes.PUT("/accounts/", server.updateAccount)
-	authRoutes.DELETE("/accounts/:id", server.deleteAccount)
+	authRoutes.POST("/accounts/:id/close", server.closeAccount)
+	authRoutes.GET("/accounts/:id/transfers", server.listAccountTransfers)
+	authRoutes.GET("/accounts/:id/statement", server.getStatement)
+	authRoutes.POST("/accounts/:id/withdrawals", server.createWithdrawal)
+	authRoutes.POST("/accounts/:id/scheduled-transfers", server.createScheduledTransfer)
+	authRoutes.GET("/accounts/:id/scheduled-transfers", server.listScheduledTransfers)
+	authRoutes.GET("/accounts/:id/scheduled-transfers/:scheduled_id", server.getScheduledTransfer)
+	authRoutes.PUT("/accounts/:id/scheduled-transfers/:scheduled_id", server.updateScheduledTransfer)
+	authRoutes.DELETE("/accounts/:id/scheduled-transfers/:scheduled_id", server.cancelScheduledTransfer)
+	authRoutes.POST("/accounts/:id/holds", server.createHold)
+	authRoutes.GET("/accounts/:id/holds", server.listHolds)
+	authRoutes.GET("/accounts/:id/holds/:hold_id", server.getHold)
+	authRoutes.POST("/accounts/:id/holds/:hold_id/capture", server.captureHold)
+	authRoutes.POST("/accounts/:id/holds/:hold_id/release", server.releaseHold)
+	authRoutes.POST("/accounts/:id/webhooks", server.createWebhook)
+	authRoutes.GET("/accounts/:id/webhooks", server.listWebhooks)
+	authRoutes.DELETE("/accounts/:id/webhooks/:webhook_id", server.deleteWebhook)
+	authRoutes.GET("/accounts/:id/webhooks/:webhook_id/deliveries", server.listWebhookDeliveries)
+	authRoutes.GET("/accounts/:id/webhooks/:webhook_id/deliveries/:delivery_id", server.getWebhookDelivery)
+	authRoutes.POST("/accounts/:id/webhooks/:webhook_id/deliveries/:delivery_id/replay", server.replayWebhookDelivery)
 
 	authRoutes.POST("/transfers/", server.createTransfer)
+	authRoutes.POST("/transfers/batch", server.createBatchTransfer)
+	authRoutes.POST("/transfers/:id/reverse", server.reverseTransfer)
+
+	authRoutes.POST("/users/logout", server.logoutUser)
+
+	supportRoutes := router.Group("/admin").Use(authMiddleware(server.tokenMaker, server.revocations), roleMiddleware(util.SupportRole, util.AdminRole))
+
+	supportRoutes.GET("/accounts", server.listAllAccounts)
+	supportRoutes.GET("/users/:username", server.lookupUser)
+
+	adminRoutes := router.Group("/admin").Use(authMiddleware(server.tokenMaker, server.revocations), roleMiddleware(util.AdminRole))
+
+	adminRoutes.GET("/reconciliation", server.reconcile)
+	adminRoutes.POST("/accounts/:id/adjustments", server.adjustBalance)
+	adminRoutes.POST("/accounts/:id/deposits", server.createDeposit)
+	adminRoutes.PUT("/accounts/:id/overdraft-limit", server.updateOverdraftLimit)
+	adminRoutes.POST("/accounts/:id/freeze", server.freezeAccount)
+	adminRoutes.POST("/accounts/:id/unfreeze", server.unfreezeAccount)
+	adminRoutes.POST("/accounts/:id/close", server.adminCloseAccount)
+	adminRoutes.GET("/currencies", server.listCurrencies)
+	adminRoutes.POST("/currencies", server.createCurrency)
+	adminRoutes.POST("/currencies/:code/disable", server.disableCurrency)
+	adminRoutes.POST("/currencies/:code/enable", server.enableCurrency)
+	adminRoutes.PUT("/users/:username/role", server.updateUserRole)
+	adminRoutes.POST("/users/:username/revoke-sessions", server.revokeUserSessions)
+	adminRoutes.POST("/token-keys/reload", server.reloadTokenKeys)
+	adminRoutes.GET("/audit-events", server.listAuditEvents)
+	adminRoutes.GET("/transfer-limits", server.listTransferLimits)
+	adminRoutes.PUT("/transfer-limits", server.setTransferLimit)
+	adminRoutes.DELETE("/transfer-limits/:id", server.deleteTransferLimit)
 
 	router.POST("/users/", server.createUser)
 	router.GET("/users/:username", server.getUser)
 	router.POST("/users/login", server.loginUser)
+	router.POST("/tokens/renew", server.renewAccessToken)
 
 	server.router = router
 }
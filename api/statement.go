@@ -0,0 +1,103 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type getStatementRequest struct {
+	From time.Time `form:"from"`
+	To   time.Time `form:"to"`
+}
+
+type statementEntry struct {
+	ID         int64        `json:"id"`
+	Kind       db.EntryKind `json:"kind"`
+	Amount     int64        `json:"amount"`
+	Balance    int64        `json:"balance"`
+	TransferID int64        `json:"transferID,omitempty"`
+	CreatedAt  time.Time    `json:"createdAt"`
+}
+
+type statementResponse struct {
+	AccountID      int64            `json:"accountID"`
+	Currency       string           `json:"currency"`
+	From           time.Time        `json:"from"`
+	To             time.Time        `json:"to"`
+	OpeningBalance int64            `json:"openingBalance"`
+	ClosingBalance int64            `json:"closingBalance"`
+	Entries        []statementEntry `json:"entries"`
+}
+
+func (server *server) getStatement(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req getStatementRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+	if !req.From.Before(req.To) {
+		err := errors.New("from must be before to")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.ownedAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	openingBalance, err := server.store.GetAccountBalanceAt(ctx, db.GetAccountBalanceAtParams{
+		AccountID: account.ID,
+		At:        req.From,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	entries, err := server.store.ListStatementEntries(ctx, db.ListStatementEntriesParams{
+		AccountID: account.ID,
+		StartTime: req.From,
+		EndTime:   req.To,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := statementResponse{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		From:           req.From,
+		To:             req.To,
+		OpeningBalance: openingBalance,
+		ClosingBalance: openingBalance,
+		Entries:        make([]statementEntry, len(entries)),
+	}
+	for i, entry := range entries {
+		rsp.ClosingBalance += entry.Amount
+		rsp.Entries[i] = statementEntry{
+			ID:         entry.ID,
+			Kind:       entry.Kind,
+			Amount:     entry.Amount,
+			Balance:    rsp.ClosingBalance,
+			TransferID: entry.TransferID.Int64,
+			CreatedAt:  entry.CreatedAt,
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
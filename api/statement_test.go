@@ -0,0 +1,102 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/mocks"
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/token"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStatement(t *testing.T) {
+	user := randomUser("temp")
+	account := randomAccount(user.Username)
+
+	openingBalance := int64(100)
+	entries := []db.Entry{
+		{ID: 1, AccountID: account.ID, Amount: 50, Kind: db.EntryKindTransfer, TransferID: sql.NullInt64{Int64: 7, Valid: true}},
+		{ID: 2, AccountID: account.ID, Amount: -30, Kind: db.EntryKindTransfer, TransferID: sql.NullInt64{Int64: 8, Valid: true}},
+	}
+
+	testCases := map[string]struct {
+		query          string
+		expectedStatus int
+		stubs          func() *mocks.Store
+		setupAuth      func(t *testing.T, req *http.Request, maker token.Maker)
+		checkResponse  func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"Status OK": {
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				mocksStore.On("GetAccountBalanceAt", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.GetAccountBalanceAtParams")).Return(openingBalance, nil)
+				mocksStore.On("ListStatementEntries", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.ListStatementEntriesParams")).Return(entries, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				var rsp statementResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, openingBalance, rsp.OpeningBalance)
+				require.Equal(t, int64(120), rsp.ClosingBalance)
+				require.Len(t, rsp.Entries, 2)
+				require.Equal(t, int64(150), rsp.Entries[0].Balance)
+				require.Equal(t, int64(120), rsp.Entries[1].Balance)
+				require.Equal(t, int64(8), rsp.Entries[1].TransferID)
+			},
+		},
+		"Invalid range": {
+			query:          "from=2021-02-01T00:00:00Z&to=2021-01-01T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {},
+		},
+		"Unauthorized user": {
+			expectedStatus: http.StatusUnauthorized,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, "unauthorized", time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/statement?%s", account.ID, test.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			test.setupAuth(t, request, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			test.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/mocks"
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/RahilRehan/banco/token"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewAccessToken(t *testing.T) {
+	user := randomUser("temp")
+
+	session := func(refreshToken string, payload *token.Payload) db.Session {
+		return db.Session{
+			ID:           payload.ID,
+			Username:     payload.Username,
+			RefreshToken: refreshToken,
+			ExpiresAt:    payload.ExpiredAt,
+		}
+	}
+
+	testCases := map[string]struct {
+		body           func(refreshToken string) gin.H
+		expectedStatus int
+		buildStubs     func(store *mocks.Store, refreshToken string, payload *token.Payload)
+	}{
+		"Status OK": {
+			expectedStatus: http.StatusOK,
+			buildStubs: func(store *mocks.Store, refreshToken string, payload *token.Payload) {
+				store.On("GetSession", mock.AnythingOfType("*gin.Context"), payload.ID).Return(session(refreshToken, payload), nil)
+			},
+		},
+		"Session not found": {
+			expectedStatus: http.StatusUnauthorized,
+			buildStubs: func(store *mocks.Store, refreshToken string, payload *token.Payload) {
+				store.On("GetSession", mock.AnythingOfType("*gin.Context"), payload.ID).Return(db.Session{}, sql.ErrNoRows)
+			},
+		},
+		"Blocked session": {
+			expectedStatus: http.StatusUnauthorized,
+			buildStubs: func(store *mocks.Store, refreshToken string, payload *token.Payload) {
+				blocked := session(refreshToken, payload)
+				blocked.IsBlocked = true
+				store.On("GetSession", mock.AnythingOfType("*gin.Context"), payload.ID).Return(blocked, nil)
+			},
+		},
+		"Mismatched refresh token": {
+			expectedStatus: http.StatusUnauthorized,
+			buildStubs: func(store *mocks.Store, refreshToken string, payload *token.Payload) {
+				store.On("GetSession", mock.AnythingOfType("*gin.Context"), payload.ID).Return(session("another-token", payload), nil)
+			},
+		},
+		"Expired session": {
+			expectedStatus: http.StatusUnauthorized,
+			buildStubs: func(store *mocks.Store, refreshToken string, payload *token.Payload) {
+				expired := session(refreshToken, payload)
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				store.On("GetSession", mock.AnythingOfType("*gin.Context"), payload.ID).Return(expired, nil)
+			},
+		},
+		"Invalid refresh token": {
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": "invalid"}
+			},
+			expectedStatus: http.StatusUnauthorized,
+			buildStubs:     func(store *mocks.Store, refreshToken string, payload *token.Payload) {},
+		},
+		"Missing refresh token": {
+			body: func(refreshToken string) gin.H {
+				return gin.H{}
+			},
+			expectedStatus: http.StatusBadRequest,
+			buildStubs:     func(store *mocks.Store, refreshToken string, payload *token.Payload) {},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := new(mocks.Store)
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			refreshToken, payload, err := server.tokenMaker.CreateToken(user.Username, util.SupportRole, token.RefreshToken, time.Hour)
+			require.NoError(t, err)
+			test.buildStubs(mockStore, refreshToken, payload)
+
+			body := gin.H{"refresh_token": refreshToken}
+			if test.body != nil {
+				body = test.body(refreshToken)
+			}
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/tokens/renew", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+
+			if test.expectedStatus == http.StatusOK {
+				var rsp renewAccessTokenResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				accessPayload, err := server.tokenMaker.VerifyToken(rsp.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, accessPayload.Username)
+				require.Equal(t, util.SupportRole, accessPayload.Role)
+				require.Equal(t, token.AccessToken, accessPayload.Type)
+			}
+		})
+	}
+}
+
+func TestRenewAccessTokenWithAccessToken(t *testing.T) {
+	user := randomUser("temp")
+	mockStore := new(mocks.Store)
+	server := newTestServer(t, mockStore)
+	recorder := httptest.NewRecorder()
+
+	// an access token must not buy another one, even with a session of the same id
+	accessToken, _, err := server.tokenMaker.CreateToken(user.Username, util.CustomerRole, token.AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(gin.H{"refresh_token": accessToken})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/tokens/renew", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	mockStore.AssertNotCalled(t, "GetSession", mock.Anything, mock.Anything)
+}
@@ -1,21 +1,35 @@
 package api
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"time"
 
 	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/fx"
 	"github.com/RahilRehan/banco/token"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+)
+
+// transferRequest.Amount is in Currency, the source account currency. Transfers into an account
+// of another currency must name it in ToCurrency and are converted with the current exchange rate.
 type transferRequest struct {
 	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
+	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1,nefield=FromAccountID"`
 	Amount        int64  `json:"amount" binding:"required,gt=0"`
 	Currency      string `json:"currency" binding:"required,currency"`
+	ToCurrency    string `json:"to_currency" binding:"omitempty,currency"`
 }
 
 func (server *server) createTransfer(ctx *gin.Context) {
@@ -24,6 +38,16 @@ func (server *server) createTransfer(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
+	if !server.knownCurrencies(ctx, req.Currency, req.ToCurrency) {
+		return
+	}
+
+	idempotencyKey := ctx.GetHeader(idempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		err := fmt.Errorf("%s must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 
 	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
 	if !valid {
@@ -37,19 +61,72 @@ func (server *server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
-	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
+	toCurrency := req.ToCurrency
+	if len(toCurrency) == 0 {
+		toCurrency = req.Currency
+	}
+
+	_, valid = server.validAccount(ctx, req.ToAccountID, toCurrency)
 	if !valid {
 		return
 	}
 
+	rate, err := server.rateProvider.Rate(ctx, req.Currency, toCurrency)
+	if err != nil {
+		if errors.Is(err, fx.ErrRateNotFound) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, errorResponse(err))
+		return
+	}
+
+	fromUnit, _ := server.currencies.Lookup(req.Currency)
+	toUnit, _ := server.currencies.Lookup(toCurrency)
+	toAmount, err := rate.ConvertMinor(req.Amount, fromUnit.Exponent, toUnit.Exponent)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if toAmount <= 0 {
+		err := fmt.Errorf("amount %d %s is too small to convert to %s", req.Amount, req.Currency, toCurrency)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	arg := db.TransferTxParams{
 		FromAccountID: req.FromAccountID,
 		ToAccountID:   req.ToAccountID,
 		Amount:        req.Amount,
+		ToAmount:      toAmount,
+		ExchangeRate:  rate.Value,
 	}
 
-	result, err := server.store.TransferTx(ctx, arg)
+	var result db.TransferTxResult
+	if len(idempotencyKey) == 0 {
+		result, err = server.store.TransferTx(ctx, arg)
+	} else {
+		result, err = server.store.IdempotentTransferTx(ctx, db.IdempotentTransferTxParams{
+			TransferTxParams: arg,
+			Username:         authPayload.Username,
+			Key:              idempotencyKey,
+			RequestHash:      hashRequest(req),
+		})
+	}
 	if err != nil {
+		if errors.Is(err, db.ErrIdempotencyKeyConflict) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		var limitErr *db.TransferLimitError
+		if errors.As(err, &limitErr) {
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "limit": limitErr})
+			return
+		}
+		if isUnprocessable(err) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -57,6 +134,78 @@ func (server *server) createTransfer(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, result)
 }
 
+type listAccountTransfersRequest struct {
+	Direction string    `form:"direction" binding:"omitempty,oneof=in out both"`
+	StartTime time.Time `form:"start_time"`
+	EndTime   time.Time `form:"end_time"`
+	MinAmount int64     `form:"min_amount" binding:"omitempty,min=0"`
+	MaxAmount int64     `form:"max_amount" binding:"omitempty,min=0"`
+	Cursor    int64     `form:"cursor" binding:"omitempty,min=1"`
+	PageSize  int32     `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+type listAccountTransfersResponse struct {
+	Transfers  []db.Transfer `json:"transfers"`
+	NextCursor int64         `json:"nextCursor,omitempty"`
+}
+
+func (server *server) listAccountTransfers(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listAccountTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, valid := server.ownedAccount(ctx, uri.ID); !valid {
+		return
+	}
+
+	arg := db.ListAccountTransfersParams{
+		AccountID: uri.ID,
+		Outgoing:  req.Direction != "in",
+		Incoming:  req.Direction != "out",
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		MinAmount: req.MinAmount,
+		MaxAmount: req.MaxAmount,
+		Cursor:    req.Cursor,
+		PageSize:  req.PageSize,
+	}
+	if arg.EndTime.IsZero() {
+		arg.EndTime = time.Now()
+	}
+	if arg.MaxAmount == 0 {
+		arg.MaxAmount = math.MaxInt64
+	}
+	if arg.Cursor == 0 {
+		arg.Cursor = math.MaxInt64
+	}
+
+	if !arg.StartTime.Before(arg.EndTime) || arg.MinAmount > arg.MaxAmount {
+		err := errors.New("invalid range: start must be before end and min must not exceed max")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	transfers, err := server.store.ListAccountTransfers(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := listAccountTransfersResponse{Transfers: transfers}
+	if len(transfers) == int(req.PageSize) {
+		rsp.NextCursor = transfers[len(transfers)-1].ID
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
 func (server *server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
 	account, err := server.store.GetAccount(ctx, accountID)
 	if err != nil {
@@ -77,3 +226,17 @@ func (server *server) validAccount(ctx *gin.Context, accountID int64, currency s
 
 	return account, true
 }
+
+// isUnprocessable tells whether a store error comes from the state of the accounts
+// involved, the request was fine but the money can't move.
+func isUnprocessable(err error) bool {
+	return db.IsRejected(err)
+}
+
+// hashRequest fingerprints a bound request so that replays of an idempotency key
+// can be told apart from a different request reusing the same key.
+func hashRequest(req interface{}) string {
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type listTransferLimitsRequest struct {
+	Currency string `form:"currency" binding:"omitempty,len=3"`
+}
+
+// setTransferLimitRequest sets the currency default when neither AccountID nor Owner is
+// given. Every limit is required, 0 turns it off.
+type setTransferLimitRequest struct {
+	Currency       string `json:"currency" binding:"required,currency"`
+	AccountID      int64  `json:"account_id" binding:"omitempty,min=1"`
+	Owner          string `json:"owner" binding:"omitempty,alphanum"`
+	PerTransaction *int64 `json:"per_transaction" binding:"required,min=0"`
+	Daily          *int64 `json:"daily" binding:"required,min=0"`
+	Monthly        *int64 `json:"monthly" binding:"required,min=0"`
+}
+
+type transferLimitRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *server) listTransferLimits(ctx *gin.Context) {
+	var req listTransferLimitsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	limits, err := server.store.ListTransferLimits(ctx, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, limits)
+}
+
+func (server *server) setTransferLimit(ctx *gin.Context) {
+	var req setTransferLimitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !server.knownCurrencies(ctx, req.Currency) {
+		return
+	}
+	if req.AccountID != 0 && req.Owner != "" {
+		err := errors.New("a limit is either for an account or for an owner")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if req.AccountID != 0 {
+		account, err := server.store.GetAccount(ctx, req.AccountID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if account.Currency != req.Currency {
+			err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, req.Currency)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	limit, err := server.store.UpsertTransferLimit(ctx, db.UpsertTransferLimitParams{
+		Currency:       req.Currency,
+		AccountID:      sql.NullInt64{Int64: req.AccountID, Valid: req.AccountID != 0},
+		Owner:          sql.NullString{String: req.Owner, Valid: req.Owner != ""},
+		PerTransaction: *req.PerTransaction,
+		Daily:          *req.Daily,
+		Monthly:        *req.Monthly,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "foreign_key_violation" {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, limit)
+}
+
+func (server *server) deleteTransferLimit(ctx *gin.Context) {
+	var uri transferLimitRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	limit, err := server.store.DeleteTransferLimit(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, limit)
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/mocks"
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTransferLimit(t *testing.T) {
+	account := randomAccount("someone")
+	account.Currency = util.USD
+
+	testCases := map[string]struct {
+		body           gin.H
+		role           string
+		expectedStatus int
+		stubs          func(store *mocks.Store)
+	}{
+		"Currency default": {
+			body:           gin.H{"currency": util.USD, "per_transaction": 1000, "daily": 5000, "monthly": 0},
+			role:           util.AdminRole,
+			expectedStatus: http.StatusOK,
+			stubs: func(store *mocks.Store) {
+				store.On("UpsertTransferLimit", mock.AnythingOfType("*gin.Context"), db.UpsertTransferLimitParams{
+					Currency:       util.USD,
+					PerTransaction: 1000,
+					Daily:          5000,
+				}).Return(db.TransferLimit{ID: 1, Currency: util.USD, PerTransaction: 1000, Daily: 5000}, nil)
+			},
+		},
+		"Account": {
+			body:           gin.H{"currency": util.USD, "account_id": account.ID, "per_transaction": 0, "daily": 100, "monthly": 1000},
+			role:           util.AdminRole,
+			expectedStatus: http.StatusOK,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("UpsertTransferLimit", mock.AnythingOfType("*gin.Context"), db.UpsertTransferLimitParams{
+					Currency:  util.USD,
+					AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+					Daily:     100,
+					Monthly:   1000,
+				}).Return(db.TransferLimit{ID: 2}, nil)
+			},
+		},
+		"Account currency mismatch": {
+			body:           gin.H{"currency": util.EUR, "account_id": account.ID, "per_transaction": 0, "daily": 100, "monthly": 1000},
+			role:           util.AdminRole,
+			expectedStatus: http.StatusBadRequest,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+			},
+		},
+		"Account and owner": {
+			body:           gin.H{"currency": util.USD, "account_id": account.ID, "owner": "someone", "per_transaction": 0, "daily": 100, "monthly": 1000},
+			role:           util.AdminRole,
+			expectedStatus: http.StatusBadRequest,
+			stubs:          func(store *mocks.Store) {},
+		},
+		"Missing limit": {
+			body:           gin.H{"currency": util.USD, "owner": "someone", "daily": 100},
+			role:           util.AdminRole,
+			expectedStatus: http.StatusBadRequest,
+			stubs:          func(store *mocks.Store) {},
+		},
+		"Not an admin": {
+			body:           gin.H{"currency": util.USD, "per_transaction": 1000, "daily": 5000, "monthly": 0},
+			role:           util.SupportRole,
+			expectedStatus: http.StatusForbidden,
+			stubs:          func(store *mocks.Store) {},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := new(mocks.Store)
+			test.stubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(test.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, "/admin/transfer-limits", bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuthWithRole(t, request, server.tokenMaker, authorizationTypeBearer, testAdminUsername, test.role, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateTransferLimitExceeded(t *testing.T) {
+	user := randomUser("temp")
+	from := randomAccount(user.Username)
+	from.Currency = util.USD
+	to := randomAccount("someone")
+	to.ID = from.ID + 1
+	to.Currency = util.USD
+
+	store := new(mocks.Store)
+	store.On("GetAccount", mock.AnythingOfType("*gin.Context"), from.ID).Return(*from, nil)
+	store.On("GetAccount", mock.AnythingOfType("*gin.Context"), to.ID).Return(*to, nil)
+	store.On("TransferTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.TransferTxParams")).Return(db.TransferTxResult{}, &db.TransferLimitError{
+		Limit:    db.LimitUserMonthly,
+		Currency: util.USD,
+		Max:      1000,
+		Used:     990,
+		Headroom: 10,
+	})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"from_account_id": from.ID, "to_account_id": to.ID, "amount": 50, "currency": util.USD})
+	require.NoError(t, err)
+	request, err := http.NewRequest(http.MethodPost, "/transfers/", bytes.NewReader(data))
+	require.NoError(t, err)
+	addAuth(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+	var rsp struct {
+		Error string                `json:"error"`
+		Limit db.TransferLimitError `json:"limit"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Contains(t, rsp.Error, "user_monthly")
+	require.Equal(t, db.LimitUserMonthly, rsp.Limit.Limit)
+	require.Equal(t, int64(10), rsp.Limit.Headroom)
+}
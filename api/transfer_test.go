@@ -0,0 +1,392 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/mocks"
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/RahilRehan/banco/fx"
+	"github.com/RahilRehan/banco/token"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransfer(t *testing.T) {
+	user1 := randomUser("temp")
+	user2 := randomUser("temp")
+
+	account1 := randomAccount(user1.Username)
+	account1.Currency = util.USD
+	account2 := randomAccount(user2.Username)
+	account2.ID = account1.ID + 1
+	account2.Currency = util.USD
+	account3 := randomAccount(user2.Username)
+	account3.ID = account1.ID + 2
+	account3.Currency = util.EUR
+	account4 := randomAccount(user2.Username)
+	account4.ID = account1.ID + 3
+	account4.Currency = util.CAD
+
+	amount := int64(10)
+	body := gin.H{
+		"from_account_id": account1.ID,
+		"to_account_id":   account2.ID,
+		"amount":          amount,
+		"currency":        util.USD,
+	}
+
+	stubAccounts := func(mockStore *mocks.Store) {
+		mockStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account1.ID).Return(*account1, nil)
+		mockStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account2.ID).Return(*account2, nil)
+		mockStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account3.ID).Return(*account3, nil)
+		mockStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account4.ID).Return(*account4, nil)
+	}
+
+	testCases := map[string]struct {
+		body           gin.H
+		idempotencyKey string
+		expectedStatus int
+		stubs          func() *mocks.Store
+		setupAuth      func(t *testing.T, req *http.Request, maker token.Maker)
+	}{
+		"Status OK": {
+			body:           body,
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				stubAccounts(mocksStore)
+				mocksStore.On("TransferTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.TransferTxParams")).Return(db.TransferTxResult{}, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+		},
+		"Cross currency": {
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account3.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+				"to_currency":     util.EUR,
+			},
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				stubAccounts(mocksStore)
+				mocksStore.On("TransferTx", mock.AnythingOfType("*gin.Context"), db.TransferTxParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account3.ID,
+					Amount:        amount,
+					ToAmount:      amount / 2,
+					ExchangeRate:  "0.5",
+				}).Return(db.TransferTxResult{}, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+		},
+		"Cross currency without to_currency": {
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account3.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+			},
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				stubAccounts(mocksStore)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+		},
+		"Rate not found": {
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account4.ID,
+				"amount":          amount,
+				"currency":        util.USD,
+				"to_currency":     util.CAD,
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				stubAccounts(mocksStore)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+		},
+		"Idempotency key": {
+			body:           body,
+			idempotencyKey: "key",
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				stubAccounts(mocksStore)
+				mocksStore.On("IdempotentTransferTx", mock.AnythingOfType("*gin.Context"), mock.MatchedBy(func(arg db.IdempotentTransferTxParams) bool {
+					return arg.Key == "key" && arg.Username == user1.Username && arg.RequestHash != ""
+				})).Return(db.TransferTxResult{}, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+		},
+		"Idempotency key conflict": {
+			body:           body,
+			idempotencyKey: "key",
+			expectedStatus: http.StatusConflict,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				stubAccounts(mocksStore)
+				mocksStore.On("IdempotentTransferTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.IdempotentTransferTxParams")).Return(db.TransferTxResult{}, db.ErrIdempotencyKeyConflict)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+		},
+		"Frozen account": {
+			body:           body,
+			expectedStatus: http.StatusUnprocessableEntity,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				stubAccounts(mocksStore)
+				mocksStore.On("TransferTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.TransferTxParams")).Return(db.TransferTxResult{}, fmt.Errorf("%w: %d", db.ErrAccountFrozen, account2.ID))
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+		},
+		"Insufficient funds": {
+			body:           body,
+			expectedStatus: http.StatusUnprocessableEntity,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				stubAccounts(mocksStore)
+				mocksStore.On("TransferTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.TransferTxParams")).Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+		},
+		"Transfer limit": {
+			body:           body,
+			expectedStatus: http.StatusUnprocessableEntity,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				stubAccounts(mocksStore)
+				mocksStore.On("TransferTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.TransferTxParams")).Return(db.TransferTxResult{}, &db.TransferLimitError{Limit: db.LimitDaily, Currency: util.USD, Max: 100, Used: 95, Headroom: 5})
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+		},
+		"Same account": {
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account1.ID,
+				"amount":          10,
+				"currency":        util.USD,
+			},
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+		},
+		"Idempotency key too long": {
+			body:           body,
+			idempotencyKey: strings.Repeat("k", maxIdempotencyKeyLength+1),
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user1.Username, time.Minute)
+			},
+		},
+		"Unauthorized user": {
+			body:           body,
+			expectedStatus: http.StatusUnauthorized,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				stubAccounts(mocksStore)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user2.Username, time.Minute)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			rateProvider, err := fx.NewStaticProvider(map[string]string{"USD/EUR": "0.5"})
+			require.NoError(t, err)
+			server.rateProvider = rateProvider
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(test.body)
+			require.NoError(t, err)
+
+			url := "/transfers/"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			if len(test.idempotencyKey) > 0 {
+				request.Header.Set(idempotencyKeyHeader, test.idempotencyKey)
+			}
+			test.setupAuth(t, request, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestListAccountTransfers(t *testing.T) {
+	user := randomUser("temp")
+	account := randomAccount(user.Username)
+
+	n := 5
+	transfers := make([]db.Transfer, n)
+	for i := 0; i < n; i++ {
+		transfers[i] = db.Transfer{
+			ID:            int64(n - i),
+			FromAccountID: account.ID,
+			ToAccountID:   account.ID + 1,
+			Amount:        util.RandomMoney(),
+		}
+	}
+
+	testCases := map[string]struct {
+		query          string
+		expectedStatus int
+		stubs          func() *mocks.Store
+		setupAuth      func(t *testing.T, req *http.Request, maker token.Maker)
+		checkResponse  func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"Status OK": {
+			query:          fmt.Sprintf("page_size=%d", n),
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				mocksStore.On("ListAccountTransfers", mock.AnythingOfType("*gin.Context"), mock.MatchedBy(func(arg db.ListAccountTransfersParams) bool {
+					return arg.AccountID == account.ID && arg.Incoming && arg.Outgoing
+				})).Return(transfers, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				var rsp listAccountTransfersResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Transfers, n)
+				require.Equal(t, transfers[n-1].ID, rsp.NextCursor)
+			},
+		},
+		"Incoming only": {
+			query:          fmt.Sprintf("page_size=%d&direction=in&cursor=10", n),
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				mocksStore.On("ListAccountTransfers", mock.AnythingOfType("*gin.Context"), mock.MatchedBy(func(arg db.ListAccountTransfersParams) bool {
+					return arg.Incoming && !arg.Outgoing && arg.Cursor == 10
+				})).Return([]db.Transfer{}, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				var rsp listAccountTransfersResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Empty(t, rsp.Transfers)
+				require.Zero(t, rsp.NextCursor)
+			},
+		},
+		"Invalid direction": {
+			query:          fmt.Sprintf("page_size=%d&direction=sideways", n),
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				return new(mocks.Store)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {},
+		},
+		"Invalid amount range": {
+			query:          fmt.Sprintf("page_size=%d&min_amount=10&max_amount=5", n),
+			expectedStatus: http.StatusBadRequest,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {},
+		},
+		"Unauthorized user": {
+			query:          fmt.Sprintf("page_size=%d", n),
+			expectedStatus: http.StatusUnauthorized,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				return mocksStore
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuth(t, req, maker, authorizationTypeBearer, "unauthorized", time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/transfers?%s", account.ID, test.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			test.setupAuth(t, request, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			test.checkResponse(t, recorder)
+		})
+	}
+}
@@ -2,12 +2,17 @@ package api
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"time"
 
 	db "github.com/RahilRehan/banco/db/sqlc"
 	"github.com/RahilRehan/banco/db/util"
+	"github.com/RahilRehan/banco/token"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
@@ -22,6 +27,7 @@ type userResponse struct {
 	Username          string    `json:"username"`
 	FullName          string    `json:"fullName"`
 	Email             string    `json:"email"`
+	Role              string    `json:"role"`
 	PasswordChangedAt time.Time `json:"passwordChangedAt"`
 	CreatedAt         time.Time `json:"createdAt"`
 }
@@ -32,8 +38,12 @@ type loginUserRequest struct {
 }
 
 type loginUserResponse struct {
-	AccessToken string       `json:"accessToken"`
-	User        userResponse `json:"userResponse"`
+	SessionID             uuid.UUID    `json:"sessionID"`
+	AccessToken           string       `json:"accessToken"`
+	AccessTokenExpiresAt  time.Time    `json:"accessTokenExpiresAt"`
+	RefreshToken          string       `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time    `json:"refreshTokenExpiresAt"`
+	User                  userResponse `json:"userResponse"`
 }
 
 func newUserResponse(user db.User) userResponse {
@@ -41,6 +51,7 @@ func newUserResponse(user db.User) userResponse {
 		Username:          user.Username,
 		FullName:          user.FullName,
 		Email:             user.Email,
+		Role:              string(user.Role),
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
 	}
@@ -66,7 +77,7 @@ func (s *server) createUser(ctx *gin.Context) {
 		Email:          req.Email,
 	}
 
-	user, err := s.store.CreateUser(ctx, arg)
+	user, err := s.store.CreateUserTx(ctx, arg)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
@@ -120,23 +131,102 @@ func (server *server) loginUser(ctx *gin.Context) {
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
 		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, string(user.Role), token.AccessToken, server.config.ACCESS_TOKEN_DURATION)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
-	accessToken, err := server.tokenMaker.CreateToken(user.Username, server.config.ACCESS_TOKEN_DURATION)
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, string(user.Role), token.RefreshToken, server.config.REFRESH_TOKEN_DURATION)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
+		ID:           refreshPayload.ID,
+		Username:     user.Username,
+		RefreshToken: refreshToken,
+		UserAgent:    ctx.Request.UserAgent(),
+		ClientIp:     ctx.ClientIP(),
+		IsBlocked:    false,
+		ExpiresAt:    refreshPayload.ExpiredAt,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
 	rsp := loginUserResponse{
-		AccessToken: accessToken,
-		User:        newUserResponse(user),
+		SessionID:             session.ID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
+		User:                  newUserResponse(user),
 	}
 	ctx.JSON(http.StatusOK, rsp)
 }
+
+type logoutUserRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// logoutUser revokes the access token of the request. When the refresh token is sent
+// too its session is blocked so it can't be renewed anymore.
+func (server *server) logoutUser(ctx *gin.Context) {
+	var req logoutUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if len(req.RefreshToken) > 0 {
+		refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
+		switch {
+		case err == token.ErrExpiredToken:
+			// nothing left to block
+		case err != nil:
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		case refreshPayload.Type != token.RefreshToken:
+			err := fmt.Errorf("%w: a refresh token is required", token.ErrWrongTokenType)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		case refreshPayload.Username != authPayload.Username:
+			err := errors.New("refresh token belongs to another user")
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		default:
+			if _, err := server.store.BlockSession(ctx, refreshPayload.ID); err != nil && err != sql.ErrNoRows {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			if err := server.revocations.RevokeToken(ctx, refreshPayload); err != nil {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+		}
+	}
+
+	if err := server.revocations.RevokeToken(ctx, authPayload); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -2,15 +2,20 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/RahilRehan/banco/db/mocks"
 	db "github.com/RahilRehan/banco/db/sqlc"
 	"github.com/RahilRehan/banco/db/util"
+	"github.com/RahilRehan/banco/token"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -44,7 +49,7 @@ func TestCreateUser(t *testing.T) {
 			expectedStatus: http.StatusCreated,
 			stubs: func() *mocks.Store {
 				mocksStore := new(mocks.Store)
-				mocksStore.On("CreateUser", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateUserParams")).Return(db.User{}, nil)
+				mocksStore.On("CreateUserTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateUserParams")).Return(db.User{}, nil)
 				return mocksStore
 			},
 		},
@@ -58,7 +63,7 @@ func TestCreateUser(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			stubs: func() *mocks.Store {
 				mocksStore := new(mocks.Store)
-				mocksStore.On("CreateUser", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateUserParams")).Return(*dbUser, nil)
+				mocksStore.On("CreateUserTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateUserParams")).Return(*dbUser, nil)
 				return mocksStore
 			},
 		},
@@ -72,7 +77,7 @@ func TestCreateUser(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			stubs: func() *mocks.Store {
 				mocksStore := new(mocks.Store)
-				mocksStore.On("CreateUser", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateUserParams")).Return(*dbUser, nil)
+				mocksStore.On("CreateUserTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateUserParams")).Return(*dbUser, nil)
 				return mocksStore
 			},
 		},
@@ -86,7 +91,7 @@ func TestCreateUser(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			stubs: func() *mocks.Store {
 				mocksStore := new(mocks.Store)
-				mocksStore.On("CreateUser", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateUserParams")).Return(db.User{}, errors.New("username twice"))
+				mocksStore.On("CreateUserTx", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateUserParams")).Return(db.User{}, errors.New("username twice"))
 				return mocksStore
 			},
 		},
@@ -112,6 +117,163 @@ func TestCreateUser(t *testing.T) {
 	}
 }
 
+func TestLoginUser(t *testing.T) {
+	password := "tester"
+	hashPass, err := util.HashPassword(password)
+	require.NoError(t, err)
+	user := randomUser(password)
+	dbUser := db.User{
+		Username:       user.Username,
+		Email:          user.Email,
+		FullName:       user.FullName,
+		HashedPassword: hashPass,
+	}
+
+	testCases := map[string]struct {
+		body           gin.H
+		expectedStatus int
+		stubs          func() *mocks.Store
+		checkResponse  func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"Status OK": {
+			body:           gin.H{"username": user.Username, "password": password},
+			expectedStatus: http.StatusOK,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetUser", mock.AnythingOfType("*gin.Context"), user.Username).Return(dbUser, nil)
+				mocksStore.On("CreateSession", mock.AnythingOfType("*gin.Context"), mock.MatchedBy(func(arg db.CreateSessionParams) bool {
+					return arg.Username == user.Username && arg.RefreshToken != "" && !arg.IsBlocked
+				})).Return(func(ctx context.Context, arg db.CreateSessionParams) db.Session {
+					return db.Session{ID: arg.ID, Username: arg.Username, RefreshToken: arg.RefreshToken, ExpiresAt: arg.ExpiresAt}
+				}, nil)
+				return mocksStore
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				var rsp loginUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotZero(t, rsp.SessionID)
+				require.NotEmpty(t, rsp.AccessToken)
+				require.NotEmpty(t, rsp.RefreshToken)
+				require.True(t, rsp.RefreshTokenExpiresAt.After(rsp.AccessTokenExpiresAt))
+			},
+		},
+		"Wrong password": {
+			body:           gin.H{"username": user.Username, "password": "wrongpassword"},
+			expectedStatus: http.StatusUnauthorized,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetUser", mock.AnythingOfType("*gin.Context"), user.Username).Return(dbUser, nil)
+				return mocksStore
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {},
+		},
+		"User not found": {
+			body:           gin.H{"username": user.Username, "password": password},
+			expectedStatus: http.StatusNotFound,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetUser", mock.AnythingOfType("*gin.Context"), user.Username).Return(db.User{}, sql.ErrNoRows)
+				return mocksStore
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {},
+		},
+		"Session error": {
+			body:           gin.H{"username": user.Username, "password": password},
+			expectedStatus: http.StatusInternalServerError,
+			stubs: func() *mocks.Store {
+				mocksStore := new(mocks.Store)
+				mocksStore.On("GetUser", mock.AnythingOfType("*gin.Context"), user.Username).Return(dbUser, nil)
+				mocksStore.On("CreateSession", mock.AnythingOfType("*gin.Context"), mock.AnythingOfType("db.CreateSessionParams")).Return(db.Session{}, errors.New("internal error"))
+				return mocksStore
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := test.stubs()
+
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(test.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			test.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestLogoutUser(t *testing.T) {
+	user := randomUser("temp")
+
+	testCases := map[string]struct {
+		withRefreshToken bool
+		refreshUsername  string
+		expectedStatus   int
+		buildStubs       func(store *mocks.Store, refreshPayload *token.Payload)
+	}{
+		"Access token only": {
+			expectedStatus: http.StatusNoContent,
+			buildStubs:     func(store *mocks.Store, refreshPayload *token.Payload) {},
+		},
+		"With refresh token": {
+			withRefreshToken: true,
+			refreshUsername:  user.Username,
+			expectedStatus:   http.StatusNoContent,
+			buildStubs: func(store *mocks.Store, refreshPayload *token.Payload) {
+				store.On("BlockSession", mock.AnythingOfType("*gin.Context"), refreshPayload.ID).Return(db.Session{ID: refreshPayload.ID, IsBlocked: true}, nil)
+			},
+		},
+		"Refresh token of another user": {
+			withRefreshToken: true,
+			refreshUsername:  "someoneelse",
+			expectedStatus:   http.StatusForbidden,
+			buildStubs:       func(store *mocks.Store, refreshPayload *token.Payload) {},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockStore := new(mocks.Store)
+			server := newTestServer(t, mockStore)
+			recorder := httptest.NewRecorder()
+
+			accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, util.CustomerRole, token.AccessToken, time.Minute)
+			require.NoError(t, err)
+
+			body := gin.H{}
+			var refreshPayload *token.Payload
+			if test.withRefreshToken {
+				var refreshToken string
+				refreshToken, refreshPayload, err = server.tokenMaker.CreateToken(test.refreshUsername, util.CustomerRole, token.RefreshToken, time.Hour)
+				require.NoError(t, err)
+				body["refresh_token"] = refreshToken
+			}
+			test.buildStubs(mockStore, refreshPayload)
+
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/logout", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, test.expectedStatus, recorder.Code)
+
+			revoked, err := server.revocations.IsRevoked(context.Background(), accessPayload)
+			require.NoError(t, err)
+			require.Equal(t, test.expectedStatus == http.StatusNoContent, revoked)
+		})
+	}
+}
+
 func randomUser(password string) *createUserRequest {
 	return &createUserRequest{
 		Username: util.RandomOwner(),
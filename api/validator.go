@@ -1,13 +1,42 @@
 package api
 
 import (
-	"github.com/RahilRehan/banco/db/util"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// validateCurrency only checks that the field looks like an ISO 4217 code. gin's
+// validator is shared by every server in the process, so whether the currency is
+// known is checked against each server's own registry by knownCurrencies.
 var validateCurrency validator.Func = func(fl validator.FieldLevel) bool {
 	if currency, ok := fl.Field().Interface().(string); ok {
-		return util.IsSupportedCurrency(currency)
+		return currencyCode.MatchString(currency)
 	}
 	return false
 }
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterValidation("currency", validateCurrency)
+	}
+}
+
+// knownCurrencies accepts the codes in the server registry, disabled ones included so
+// existing accounts in a disabled currency keep working, and writes a 400 otherwise.
+// Empty codes are optional fields that were left out.
+func (server *server) knownCurrencies(ctx *gin.Context, codes ...string) bool {
+	for _, code := range codes {
+		if code != "" && !server.currencies.IsRegistered(code) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("unknown currency %s", code)))
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,287 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/webhook"
+	"github.com/gin-gonic/gin"
+)
+
+type createWebhookRequest struct {
+	URL       string `json:"url" binding:"required,url"`
+	EventType string `json:"event_type" binding:"required,oneof=transfer.completed"`
+}
+
+type webhookURI struct {
+	ID        int64 `uri:"id" binding:"required,min=1"`
+	WebhookID int64 `uri:"webhook_id" binding:"required,min=1"`
+}
+
+type webhookDeliveryURI struct {
+	ID         int64 `uri:"id" binding:"required,min=1"`
+	WebhookID  int64 `uri:"webhook_id" binding:"required,min=1"`
+	DeliveryID int64 `uri:"delivery_id" binding:"required,min=1"`
+}
+
+type listWebhookDeliveriesRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending succeeded failed"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// webhookResponse leaves out the signing secret, it is only shown once when the
+// subscription is created.
+type webhookResponse struct {
+	ID        int64     `json:"id"`
+	AccountID int64     `json:"accountID"`
+	URL       string    `json:"url"`
+	EventType string    `json:"eventType"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type webhookDeliveryResponse struct {
+	db.WebhookDelivery
+	Attempts []db.WebhookDeliveryAttempt `json:"attemptLog"`
+}
+
+func newWebhookResponse(subscription db.WebhookSubscription) webhookResponse {
+	return webhookResponse{
+		ID:        subscription.ID,
+		AccountID: subscription.AccountID,
+		URL:       subscription.Url,
+		EventType: subscription.EventType,
+		Active:    subscription.Active,
+		CreatedAt: subscription.CreatedAt,
+	}
+}
+
+func (server *server) createWebhook(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req createWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.ownedAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+	if account.Status == db.AccountStatusClosed {
+		err := fmt.Errorf("%w: %d", db.ErrAccountClosed, account.ID)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+	// the host is only looked up for the owner of the account
+	if err := webhook.CheckURL(ctx, server.resolver, req.URL); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	subscription, err := server.store.CreateWebhookSubscription(ctx, db.CreateWebhookSubscriptionParams{
+		AccountID: account.ID,
+		Url:       req.URL,
+		EventType: req.EventType,
+		Secret:    secret,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := newWebhookResponse(subscription)
+	rsp.Secret = subscription.Secret
+	ctx.JSON(http.StatusCreated, rsp)
+}
+
+func (server *server) listWebhooks(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, valid := server.ownedAccount(ctx, uri.ID)
+	if !valid {
+		return
+	}
+
+	subscriptions, err := server.store.ListWebhookSubscriptions(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]webhookResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		rsp[i] = newWebhookResponse(subscription)
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// deleteWebhook deactivates a subscription, its deliveries are kept for the history.
+func (server *server) deleteWebhook(ctx *gin.Context) {
+	var uri webhookURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	subscription, valid := server.ownedWebhook(ctx, uri.ID, uri.WebhookID)
+	if !valid {
+		return
+	}
+
+	subscription, err := server.store.DeactivateWebhookSubscription(ctx, subscription.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, newWebhookResponse(subscription))
+}
+
+func (server *server) listWebhookDeliveries(ctx *gin.Context) {
+	var uri webhookURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var req listWebhookDeliveriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	subscription, valid := server.ownedWebhook(ctx, uri.ID, uri.WebhookID)
+	if !valid {
+		return
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		Status:         req.Status,
+		LimitCount:     req.PageSize,
+		OffsetCount:    (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+func (server *server) getWebhookDelivery(ctx *gin.Context) {
+	var uri webhookDeliveryURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	delivery, valid := server.ownedWebhookDelivery(ctx, uri)
+	if !valid {
+		return
+	}
+
+	attempts, err := server.store.ListWebhookDeliveryAttempts(ctx, delivery.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, webhookDeliveryResponse{WebhookDelivery: delivery, Attempts: attempts})
+}
+
+// replayWebhookDelivery sends a failed delivery again with a fresh set of attempts.
+func (server *server) replayWebhookDelivery(ctx *gin.Context) {
+	var uri webhookDeliveryURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	delivery, valid := server.ownedWebhookDelivery(ctx, uri)
+	if !valid {
+		return
+	}
+
+	delivery, err := server.store.ReplayWebhookDelivery(ctx, delivery.ID)
+	if err != nil {
+		// only failed deliveries are replayed
+		if err == sql.ErrNoRows {
+			err := fmt.Errorf("webhook delivery %d has not failed", uri.DeliveryID)
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, delivery)
+}
+
+// ownedWebhook loads a subscription of an account of the authenticated user, writing the
+// error response itself when there is none.
+func (server *server) ownedWebhook(ctx *gin.Context, accountID, webhookID int64) (db.WebhookSubscription, bool) {
+	account, valid := server.ownedAccount(ctx, accountID)
+	if !valid {
+		return db.WebhookSubscription{}, false
+	}
+
+	subscription, err := server.store.GetWebhookSubscription(ctx, webhookID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return subscription, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return subscription, false
+	}
+
+	if subscription.AccountID != account.ID {
+		err := errors.New("webhook does not belong to the account")
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return subscription, false
+	}
+	return subscription, true
+}
+
+func (server *server) ownedWebhookDelivery(ctx *gin.Context, uri webhookDeliveryURI) (db.WebhookDelivery, bool) {
+	subscription, valid := server.ownedWebhook(ctx, uri.ID, uri.WebhookID)
+	if !valid {
+		return db.WebhookDelivery{}, false
+	}
+
+	delivery, err := server.store.GetWebhookDelivery(ctx, uri.DeliveryID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return delivery, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return delivery, false
+	}
+
+	if delivery.SubscriptionID != subscription.ID {
+		err := errors.New("delivery does not belong to the webhook")
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return delivery, false
+	}
+	return delivery, true
+}
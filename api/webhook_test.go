@@ -0,0 +1,321 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/mocks"
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testResolver answers host lookups from the map instead of the network.
+type testResolver map[string][]net.IPAddr
+
+func (r testResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func TestCreateWebhook(t *testing.T) {
+	user := randomUser("temp")
+	account := randomAccount(user.Username)
+	closed := *account
+	closed.Status = db.AccountStatusClosed
+
+	testCases := map[string]struct {
+		body          gin.H
+		username      string
+		stubs         func(store *mocks.Store)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			body:     gin.H{"url": "https://example.com/hooks", "event_type": db.EventTransferCompleted},
+			username: user.Username,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("CreateWebhookSubscription", mock.AnythingOfType("*gin.Context"), mock.MatchedBy(func(arg db.CreateWebhookSubscriptionParams) bool {
+					return arg.AccountID == account.ID && arg.Url == "https://example.com/hooks" && arg.EventType == db.EventTransferCompleted && len(arg.Secret) > 0
+				})).Return(func(ctx context.Context, arg db.CreateWebhookSubscriptionParams) db.WebhookSubscription {
+					return db.WebhookSubscription{ID: 1, AccountID: arg.AccountID, Url: arg.Url, EventType: arg.EventType, Secret: arg.Secret, Active: true}
+				}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				var rsp webhookResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, account.ID, rsp.AccountID)
+				require.True(t, rsp.Active)
+				require.Contains(t, rsp.Secret, "whsec_")
+			},
+		},
+		"Invalid url": {
+			body:     gin.H{"url": "not a url", "event_type": db.EventTransferCompleted},
+			username: user.Username,
+			stubs:    func(store *mocks.Store) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Unsupported scheme": {
+			body:     gin.H{"url": "ftp://example.com/hooks", "event_type": db.EventTransferCompleted},
+			username: user.Username,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Loopback address": {
+			body:     gin.H{"url": "http://127.0.0.1:8080/hooks", "event_type": db.EventTransferCompleted},
+			username: user.Username,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Metadata address": {
+			body:     gin.H{"url": "http://169.254.169.254/latest/meta-data", "event_type": db.EventTransferCompleted},
+			username: user.Username,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Host resolving to a private address": {
+			body:     gin.H{"url": "https://internal.example.com/hooks", "event_type": db.EventTransferCompleted},
+			username: user.Username,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Account created event": {
+			body:     gin.H{"url": "https://example.com/hooks", "event_type": db.EventAccountCreated},
+			username: user.Username,
+			stubs:    func(store *mocks.Store) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Unsupported event type": {
+			body:     gin.H{"url": "https://example.com/hooks", "event_type": db.EventUserCreated},
+			username: user.Username,
+			stubs:    func(store *mocks.Store) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		"Closed account": {
+			body:     gin.H{"url": "https://example.com/hooks", "event_type": db.EventTransferCompleted},
+			username: user.Username,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(closed, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Unauthorized user with an internal url": {
+			body:     gin.H{"url": "https://internal.example.com/hooks", "event_type": db.EventTransferCompleted},
+			username: "unauthorized",
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		"Unauthorized user": {
+			body:     gin.H{"url": "https://example.com/hooks", "event_type": db.EventTransferCompleted},
+			username: "unauthorized",
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := new(mocks.Store)
+			test.stubs(store)
+
+			server := newTestServer(t, store)
+			server.resolver = testResolver{
+				"example.com":          {{IP: net.ParseIP("93.184.216.34")}},
+				"internal.example.com": {{IP: net.ParseIP("10.0.0.7")}},
+			}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(test.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/accounts/%d/webhooks", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			addAuth(t, request, server.tokenMaker, authorizationTypeBearer, test.username, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			test.checkResponse(t, recorder)
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListWebhooks(t *testing.T) {
+	user := randomUser("temp")
+	account := randomAccount(user.Username)
+	subscription := db.WebhookSubscription{
+		ID:        util.RandomInt(1, 1000),
+		AccountID: account.ID,
+		Url:       "https://example.com/hooks",
+		EventType: db.EventTransferCompleted,
+		Secret:    "whsec_secret",
+		Active:    true,
+	}
+
+	store := new(mocks.Store)
+	store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+	store.On("ListWebhookSubscriptions", mock.AnythingOfType("*gin.Context"), account.ID).Return([]db.WebhookSubscription{subscription}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d/webhooks", account.ID), nil)
+	require.NoError(t, err)
+	addAuth(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	// the secret is only shown on creation
+	require.NotContains(t, recorder.Body.String(), "whsec_secret")
+
+	var rsp []webhookResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp, 1)
+	require.Equal(t, subscription.ID, rsp[0].ID)
+}
+
+func TestReplayWebhookDelivery(t *testing.T) {
+	user := randomUser("temp")
+	account := randomAccount(user.Username)
+	subscription := db.WebhookSubscription{ID: 3, AccountID: account.ID, Active: true}
+	delivery := db.WebhookDelivery{ID: 5, SubscriptionID: subscription.ID, Status: db.WebhookDeliveryStatusFailed, Attempts: 8}
+
+	testCases := map[string]struct {
+		webhookID     int64
+		stubs         func(store *mocks.Store)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		"OK": {
+			webhookID: subscription.ID,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetWebhookSubscription", mock.AnythingOfType("*gin.Context"), subscription.ID).Return(subscription, nil)
+				store.On("GetWebhookDelivery", mock.AnythingOfType("*gin.Context"), delivery.ID).Return(delivery, nil)
+				store.On("ReplayWebhookDelivery", mock.AnythingOfType("*gin.Context"), delivery.ID).Return(db.WebhookDelivery{
+					ID:             delivery.ID,
+					SubscriptionID: subscription.ID,
+					Status:         db.WebhookDeliveryStatusPending,
+				}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var rsp db.WebhookDelivery
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, db.WebhookDeliveryStatusPending, rsp.Status)
+				require.Zero(t, rsp.Attempts)
+			},
+		},
+		"Not failed": {
+			webhookID: subscription.ID,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetWebhookSubscription", mock.AnythingOfType("*gin.Context"), subscription.ID).Return(subscription, nil)
+				store.On("GetWebhookDelivery", mock.AnythingOfType("*gin.Context"), delivery.ID).Return(delivery, nil)
+				store.On("ReplayWebhookDelivery", mock.AnythingOfType("*gin.Context"), delivery.ID).Return(db.WebhookDelivery{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		"Webhook of another account": {
+			webhookID: subscription.ID,
+			stubs: func(store *mocks.Store) {
+				other := subscription
+				other.AccountID = account.ID + 1
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetWebhookSubscription", mock.AnythingOfType("*gin.Context"), subscription.ID).Return(other, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Delivery of another webhook": {
+			webhookID: subscription.ID,
+			stubs: func(store *mocks.Store) {
+				other := delivery
+				other.SubscriptionID = subscription.ID + 1
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetWebhookSubscription", mock.AnythingOfType("*gin.Context"), subscription.ID).Return(subscription, nil)
+				store.On("GetWebhookDelivery", mock.AnythingOfType("*gin.Context"), delivery.ID).Return(other, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		"Webhook not found": {
+			webhookID: subscription.ID,
+			stubs: func(store *mocks.Store) {
+				store.On("GetAccount", mock.AnythingOfType("*gin.Context"), account.ID).Return(*account, nil)
+				store.On("GetWebhookSubscription", mock.AnythingOfType("*gin.Context"), subscription.ID).Return(db.WebhookSubscription{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store := new(mocks.Store)
+			test.stubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/webhooks/%d/deliveries/%d/replay", account.ID, test.webhookID, delivery.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+			addAuth(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			test.checkResponse(t, recorder)
+			store.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+)
+
+// Source loads the currencies table, db.Store satisfies it.
+type Source interface {
+	ListCurrencies(ctx context.Context) ([]db.Currency, error)
+}
+
+// Registry is an in-memory copy of the currencies table so request validation
+// doesn't hit the database. Call Reload after changing the table.
+type Registry struct {
+	source Source
+
+	mu         sync.RWMutex
+	currencies map[string]db.Currency
+	codes      []string
+}
+
+func NewRegistry(ctx context.Context, source Source) (*Registry, error) {
+	registry := &Registry{source: source}
+	if err := registry.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+func (r *Registry) Reload(ctx context.Context) error {
+	currencies, err := r.source.ListCurrencies(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot load currencies: %w", err)
+	}
+
+	byCode := make(map[string]db.Currency, len(currencies))
+	codes := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		byCode[currency.Code] = currency
+		codes = append(codes, currency.Code)
+	}
+
+	r.mu.Lock()
+	r.currencies = byCode
+	r.codes = codes
+	r.mu.Unlock()
+	return nil
+}
+
+// Lookup returns a registered currency, enabled or not.
+func (r *Registry) Lookup(code string) (db.Currency, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	currency, ok := r.currencies[code]
+	return currency, ok
+}
+
+func (r *Registry) IsRegistered(code string) bool {
+	_, ok := r.Lookup(code)
+	return ok
+}
+
+func (r *Registry) IsEnabled(code string) bool {
+	currency, ok := r.Lookup(code)
+	return ok && currency.Enabled
+}
+
+// List returns every registered currency ordered by code.
+func (r *Registry) List() []db.Currency {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	currencies := make([]db.Currency, len(r.codes))
+	for i, code := range r.codes {
+		currencies[i] = r.currencies[code]
+	}
+	return currencies
+}
@@ -0,0 +1,49 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	currencies []db.Currency
+	err        error
+}
+
+func (s *fakeSource) ListCurrencies(ctx context.Context) ([]db.Currency, error) {
+	return s.currencies, s.err
+}
+
+func TestRegistry(t *testing.T) {
+	source := &fakeSource{currencies: []db.Currency{
+		{Code: "CAD", Exponent: 2, Enabled: false},
+		{Code: "JPY", Exponent: 0, Enabled: true},
+	}}
+
+	registry, err := NewRegistry(context.Background(), source)
+	require.NoError(t, err)
+
+	jpy, ok := registry.Lookup("JPY")
+	require.True(t, ok)
+	require.Equal(t, int32(0), jpy.Exponent)
+
+	require.True(t, registry.IsRegistered("CAD"))
+	require.False(t, registry.IsEnabled("CAD"))
+	require.True(t, registry.IsEnabled("JPY"))
+	require.False(t, registry.IsRegistered("RS"))
+	require.Len(t, registry.List(), 2)
+
+	source.currencies = append(source.currencies, db.Currency{Code: "USD", Exponent: 2, Enabled: true})
+	require.NoError(t, registry.Reload(context.Background()))
+	require.True(t, registry.IsEnabled("USD"))
+	require.Equal(t, "USD", registry.List()[2].Code)
+}
+
+func TestRegistryLoadError(t *testing.T) {
+	_, err := NewRegistry(context.Background(), &fakeSource{err: errors.New("boom")})
+	require.Error(t, err)
+}
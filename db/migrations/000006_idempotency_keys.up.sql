@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS "idempotency_keys" (
+   "username" varchar NOT NULL,
+   "key" varchar NOT NULL,
+   "request_hash" varchar NOT NULL,
+   "response" jsonb NOT NULL,
+   "created_at" timestamptz NOT NULL DEFAULT (now()),
+   PRIMARY KEY ("username", "key")
+);
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "idempotency_keys"."request_hash" IS 'sha256 of the request the key was first used with';
@@ -0,0 +1,3 @@
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "kind";
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "transfer_id";
+DROP TYPE IF EXISTS "entry_kind";
@@ -0,0 +1,29 @@
+CREATE TYPE "entry_kind" AS ENUM (
+   'transfer',
+   'deposit',
+   'withdrawal',
+   'fee',
+   'adjustment'
+);
+
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+ALTER TABLE "entries" ADD COLUMN "kind" entry_kind NOT NULL DEFAULT 'adjustment';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+-- entries written by TransferTx share the transaction timestamp with their transfer
+UPDATE "entries" e
+SET "transfer_id" = t."id", "kind" = 'transfer'
+FROM "transfers" t
+WHERE
+   t."created_at" = e."created_at" AND (
+      (t."from_account_id" = e."account_id" AND t."amount" = -e."amount") OR
+      (t."to_account_id" = e."account_id" AND t."amount" = e."amount")
+   );
+
+ALTER TABLE "entries" ALTER COLUMN "kind" DROP DEFAULT;
+ALTER TABLE "entries" ADD CONSTRAINT "entries_transfer_kind_check" CHECK ("kind" <> 'transfer' OR "transfer_id" IS NOT NULL);
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'transfer that created the entry, if any';
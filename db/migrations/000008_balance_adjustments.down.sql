@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "balance_adjustments";
+DROP TYPE IF EXISTS "adjustment_reason";
@@ -0,0 +1,27 @@
+CREATE TYPE "adjustment_reason" AS ENUM (
+   'correction',
+   'goodwill',
+   'chargeback',
+   'fee_refund',
+   'write_off'
+);
+
+CREATE TABLE IF NOT EXISTS "balance_adjustments" (
+   "id" bigserial PRIMARY KEY,
+   "account_id" bigint NOT NULL,
+   "entry_id" bigint NOT NULL,
+   "amount" bigint NOT NULL,
+   "reason_code" adjustment_reason NOT NULL,
+   "note" varchar NOT NULL,
+   "actor" varchar NOT NULL,
+   "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("actor") REFERENCES "users" ("username");
+
+CREATE INDEX ON "balance_adjustments" ("account_id");
+
+COMMENT ON COLUMN "balance_adjustments"."amount" IS 'can be positive or negative, must not be zero';
+COMMENT ON COLUMN "balance_adjustments"."actor" IS 'admin who made the adjustment';
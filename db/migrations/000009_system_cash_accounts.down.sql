@@ -0,0 +1,3 @@
+DELETE FROM "entries" WHERE "account_id" IN (SELECT "id" FROM "accounts" WHERE "owner" = 'banco-system');
+DELETE FROM "accounts" WHERE "owner" = 'banco-system';
+DELETE FROM "users" WHERE "username" = 'banco-system';
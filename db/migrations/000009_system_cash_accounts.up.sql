@@ -0,0 +1,13 @@
+-- the system user can't log in (empty password hash) and can't be registered
+-- through the api because usernames there must be alphanumeric
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email")
+VALUES ('banco-system', '', 'banco system', 'system@banco.invalid')
+ON CONFLICT DO NOTHING;
+
+-- cash accounts are the other side of every deposit and withdrawal
+INSERT INTO "accounts" ("owner", "balance", "currency")
+VALUES
+   ('banco-system', 0, 'USD'),
+   ('banco-system', 0, 'EUR'),
+   ('banco-system', 0, 'CAD')
+ON CONFLICT DO NOTHING;
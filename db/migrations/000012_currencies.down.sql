@@ -0,0 +1,2 @@
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "accounts_currency_fkey";
+DROP TABLE IF EXISTS "currencies";
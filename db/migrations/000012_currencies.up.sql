@@ -0,0 +1,25 @@
+CREATE TABLE "currencies" (
+  "code" varchar(3) PRIMARY KEY,
+  "exponent" integer NOT NULL,
+  "enabled" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "currencies_exponent_check" CHECK ("exponent" BETWEEN 0 AND 4)
+);
+
+COMMENT ON COLUMN "currencies"."code" IS 'ISO 4217 alphabetic code';
+COMMENT ON COLUMN "currencies"."exponent" IS 'number of minor units digits, 2 for USD cents';
+COMMENT ON COLUMN "currencies"."enabled" IS 'disabled currencies can not be used for new accounts';
+
+INSERT INTO "currencies" ("code", "exponent")
+VALUES
+   ('USD', 2),
+   ('EUR', 2),
+   ('CAD', 2);
+
+-- accounts opened before the registry existed may use codes that were never supported,
+-- keep them working but don't allow new ones
+INSERT INTO "currencies" ("code", "exponent", "enabled")
+SELECT DISTINCT "currency", 2, false FROM "accounts"
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
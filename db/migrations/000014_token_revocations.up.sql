@@ -0,0 +1,21 @@
+CREATE TABLE "revoked_tokens" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "revoked_tokens" ("expires_at");
+
+COMMENT ON COLUMN "revoked_tokens"."id" IS 'id of the revoked token payload';
+COMMENT ON COLUMN "revoked_tokens"."expires_at" IS 'when the token expires anyway, the row can be deleted after that';
+
+CREATE TABLE "user_revocations" (
+  "username" varchar PRIMARY KEY,
+  "revoked_before" timestamptz NOT NULL,
+  "expires_at" timestamptz NOT NULL
+);
+
+ALTER TABLE "user_revocations" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "user_revocations"."revoked_before" IS 'tokens of the user issued at or before this time are rejected';
@@ -0,0 +1,9 @@
+CREATE TYPE "user_role" AS ENUM (
+   'customer',
+   'support',
+   'admin'
+);
+
+ALTER TABLE "users" ADD COLUMN "role" user_role NOT NULL DEFAULT 'customer';
+
+COMMENT ON COLUMN "users"."role" IS 'carried in the access token, admin and support routes check it';
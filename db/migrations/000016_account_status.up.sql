@@ -0,0 +1,8 @@
+CREATE TYPE "account_status" AS ENUM (
+   'active',
+   'frozen'
+);
+
+ALTER TABLE "accounts" ADD COLUMN "status" account_status NOT NULL DEFAULT 'active';
+
+COMMENT ON COLUMN "accounts"."status" IS 'money only moves in and out of active accounts';
@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS "accounts_owner_currency_idx";
+
+UPDATE "accounts" SET "status" = 'frozen' WHERE "status" = 'closed';
+ALTER TABLE "accounts" ALTER COLUMN "status" DROP DEFAULT;
+ALTER TYPE "account_status" RENAME TO "account_status_old";
+CREATE TYPE "account_status" AS ENUM (
+   'active',
+   'frozen'
+);
+ALTER TABLE "accounts" ALTER COLUMN "status" TYPE account_status USING "status"::text::account_status;
+ALTER TABLE "accounts" ALTER COLUMN "status" SET DEFAULT 'active';
+DROP TYPE "account_status_old";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "closed_at";
+
+CREATE UNIQUE INDEX ON "accounts" ("owner", "currency");
@@ -0,0 +1,9 @@
+ALTER TYPE "account_status" ADD VALUE 'closed';
+
+ALTER TABLE "accounts" ADD COLUMN "closed_at" timestamptz;
+
+-- a closed account keeps its history, the owner can open a new one in the same currency
+DROP INDEX IF EXISTS "accounts_owner_currency_idx";
+CREATE UNIQUE INDEX ON "accounts" ("owner", "currency") WHERE "closed_at" IS NULL;
+
+COMMENT ON COLUMN "accounts"."closed_at" IS 'set when the account is closed, closed accounts are never reopened';
@@ -0,0 +1,21 @@
+CREATE TABLE "audit_events" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "resource_type" varchar NOT NULL,
+  "resource_id" varchar NOT NULL,
+  "request_id" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "before" jsonb NOT NULL DEFAULT 'null',
+  "after" jsonb NOT NULL DEFAULT 'null',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "audit_events" ("actor");
+CREATE INDEX ON "audit_events" ("resource_type", "resource_id");
+CREATE INDEX ON "audit_events" ("request_id");
+CREATE INDEX ON "audit_events" ("created_at");
+
+COMMENT ON COLUMN "audit_events"."actor" IS 'username of the authenticated user, empty for anonymous requests and background jobs';
+COMMENT ON COLUMN "audit_events"."action" IS 'http method and route for request events, e.g. account.status for store events';
+COMMENT ON COLUMN "audit_events"."request_id" IS 'ties the store events of a request to its request event';
@@ -0,0 +1,17 @@
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "event_type" varchar NOT NULL,
+  "aggregate_type" varchar NOT NULL,
+  "aggregate_id" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "published_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "outbox" ("next_attempt_at") WHERE "published_at" IS NULL;
+
+COMMENT ON COLUMN "outbox"."event_type" IS 'e.g. transfer.completed, account.created, user.created';
+COMMENT ON COLUMN "outbox"."published_at" IS 'set once every sink took the event, pending until then';
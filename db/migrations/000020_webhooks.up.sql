@@ -0,0 +1,58 @@
+CREATE TYPE "webhook_delivery_status" AS ENUM (
+   'pending',
+   'succeeded',
+   'failed'
+);
+
+CREATE TABLE "webhook_subscriptions" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "url" varchar NOT NULL,
+  "event_type" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "webhook_subscriptions" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "webhook_subscriptions" ("account_id", "event_type") WHERE "active";
+
+COMMENT ON COLUMN "webhook_subscriptions"."secret" IS 'hmac key the deliveries are signed with';
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "subscription_id" bigint NOT NULL,
+  "event_id" bigint NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" webhook_delivery_status NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_status_code" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "delivered_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscriptions" ("id");
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("event_id") REFERENCES "outbox" ("id");
+
+CREATE UNIQUE INDEX ON "webhook_deliveries" ("subscription_id", "event_id");
+CREATE INDEX ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
+
+COMMENT ON COLUMN "webhook_deliveries"."event_id" IS 'outbox event being delivered, one delivery per subscription and event';
+COMMENT ON COLUMN "webhook_deliveries"."last_status_code" IS 'http status of the last attempt, 0 when no response came back';
+
+CREATE TABLE "webhook_delivery_attempts" (
+  "id" bigserial PRIMARY KEY,
+  "delivery_id" bigint NOT NULL,
+  "status_code" int NOT NULL,
+  "error" varchar NOT NULL,
+  "duration_ms" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "webhook_delivery_attempts" ADD FOREIGN KEY ("delivery_id") REFERENCES "webhook_deliveries" ("id");
+
+CREATE INDEX ON "webhook_delivery_attempts" ("delivery_id");
@@ -0,0 +1,26 @@
+CREATE TABLE "transfer_limits" (
+  "id" bigserial PRIMARY KEY,
+  "currency" varchar NOT NULL,
+  "account_id" bigint,
+  "owner" varchar,
+  "per_transaction" bigint NOT NULL DEFAULT 0,
+  "daily" bigint NOT NULL DEFAULT 0,
+  "monthly" bigint NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "transfer_limits_scope_check" CHECK ("account_id" IS NULL OR "owner" IS NULL),
+  CONSTRAINT "transfer_limits_amounts_check" CHECK ("per_transaction" >= 0 AND "daily" >= 0 AND "monthly" >= 0)
+);
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+-- one row per scope: the currency default, an account or a user in a currency
+CREATE UNIQUE INDEX "transfer_limits_scope_key" ON "transfer_limits" ("currency", (COALESCE("account_id", 0)), (COALESCE("owner", '')));
+
+COMMENT ON TABLE "transfer_limits" IS 'rows without account and owner are the defaults of a currency, an account row replaces them for that account, an owner row caps all accounts of the user in the currency';
+COMMENT ON COLUMN "transfer_limits"."per_transaction" IS '0 means no limit, like daily and monthly';
+COMMENT ON COLUMN "transfer_limits"."daily" IS 'total of outgoing transfers per utc calendar day';
+COMMENT ON COLUMN "transfer_limits"."monthly" IS 'total of outgoing transfers per utc calendar month';
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
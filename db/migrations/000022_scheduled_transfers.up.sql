@@ -0,0 +1,51 @@
+CREATE TYPE "scheduled_transfer_status" AS ENUM (
+   'active',
+   'completed',
+   'cancelled'
+);
+
+CREATE TYPE "scheduled_run_status" AS ENUM (
+   'succeeded',
+   'failed'
+);
+
+CREATE TABLE "scheduled_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "schedule" varchar NOT NULL DEFAULT '',
+  "start_at" timestamptz NOT NULL,
+  "next_run_at" timestamptz,
+  "status" scheduled_transfer_status NOT NULL DEFAULT 'active',
+  "run_count" int NOT NULL DEFAULT 0,
+  "last_run_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "scheduled_transfers_amount_check" CHECK ("amount" > 0)
+);
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "scheduled_transfers" ("from_account_id");
+CREATE INDEX ON "scheduled_transfers" ("next_run_at") WHERE "status" = 'active';
+
+COMMENT ON COLUMN "scheduled_transfers"."schedule" IS 'empty for a one-off transfer at start_at, else a cron expression or an RRULE starting at start_at, in utc';
+COMMENT ON COLUMN "scheduled_transfers"."next_run_at" IS 'null once the schedule has no more runs';
+
+CREATE TABLE "scheduled_transfer_runs" (
+  "id" bigserial PRIMARY KEY,
+  "scheduled_transfer_id" bigint NOT NULL,
+  "scheduled_for" timestamptz NOT NULL,
+  "status" scheduled_run_status NOT NULL,
+  "transfer_id" bigint,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("scheduled_transfer_id") REFERENCES "scheduled_transfers" ("id");
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+-- a run happens at most once, whatever the number of schedulers
+CREATE UNIQUE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id", "scheduled_for");
@@ -0,0 +1,17 @@
+CREATE TYPE "transfer_status" AS ENUM (
+   'completed',
+   'partially_reversed',
+   'reversed'
+);
+
+ALTER TABLE "transfers" ADD COLUMN "status" transfer_status NOT NULL DEFAULT 'completed';
+ALTER TABLE "transfers" ADD COLUMN "refunded_amount" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "transfers" ADD COLUMN "reversal_of" bigint;
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversal_of") REFERENCES "transfers" ("id");
+ALTER TABLE "transfers" ADD CONSTRAINT "transfers_refunded_amount_check" CHECK ("refunded_amount" BETWEEN 0 AND "amount");
+
+CREATE INDEX ON "transfers" ("reversal_of");
+
+COMMENT ON COLUMN "transfers"."refunded_amount" IS 'part of amount sent back by reversals, in the source currency';
+COMMENT ON COLUMN "transfers"."reversal_of" IS 'transfer this one reverses, it moves money the other way';
@@ -0,0 +1,36 @@
+CREATE TYPE "hold_status" AS ENUM (
+   'active',
+   'captured',
+   'released',
+   'expired'
+);
+
+ALTER TABLE "accounts" ADD COLUMN "held_balance" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_held_balance_check" CHECK ("held_balance" >= 0);
+
+COMMENT ON COLUMN "accounts"."held_balance" IS 'sum of the active holds on the account, taken off its available balance';
+
+CREATE TABLE "holds" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "captured_amount" bigint NOT NULL DEFAULT 0,
+  "status" hold_status NOT NULL DEFAULT 'active',
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "holds_amount_check" CHECK ("amount" > 0),
+  CONSTRAINT "holds_captured_amount_check" CHECK ("captured_amount" BETWEEN 0 AND "amount")
+);
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "holds" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE INDEX ON "holds" ("account_id");
+CREATE INDEX ON "holds" ("expires_at") WHERE "status" = 'active';
+
+COMMENT ON COLUMN "holds"."captured_amount" IS 'part of amount moved to to_account_id by the capture, the rest went back to the available balance';
+COMMENT ON COLUMN "holds"."transfer_id" IS 'transfer made by the capture';
@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	db "github.com/RahilRehan/banco/db/sqlc"
+	uuid "github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -35,19 +36,19 @@ func (_m *Store) AddAccountBalance(ctx context.Context, arg db.AddAccountBalance
 	return r0, r1
 }
 
-// CreateAccount provides a mock function with given fields: ctx, arg
-func (_m *Store) CreateAccount(ctx context.Context, arg db.CreateAccountParams) (db.Account, error) {
+// AddAccountHeldBalance provides a mock function with given fields: ctx, arg
+func (_m *Store) AddAccountHeldBalance(ctx context.Context, arg db.AddAccountHeldBalanceParams) (db.Account, error) {
 	ret := _m.Called(ctx, arg)
 
 	var r0 db.Account
-	if rf, ok := ret.Get(0).(func(context.Context, db.CreateAccountParams) db.Account); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, db.AddAccountHeldBalanceParams) db.Account); ok {
 		r0 = rf(ctx, arg)
 	} else {
 		r0 = ret.Get(0).(db.Account)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, db.CreateAccountParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, db.AddAccountHeldBalanceParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -56,19 +57,19 @@ func (_m *Store) CreateAccount(ctx context.Context, arg db.CreateAccountParams)
 	return r0, r1
 }
 
-// CreateEntry provides a mock function with given fields: ctx, arg
-func (_m *Store) CreateEntry(ctx context.Context, arg db.CreateEntryParams) (db.Entry, error) {
+// AddTransferRefund provides a mock function with given fields: ctx, arg
+func (_m *Store) AddTransferRefund(ctx context.Context, arg db.AddTransferRefundParams) (db.Transfer, error) {
 	ret := _m.Called(ctx, arg)
 
-	var r0 db.Entry
-	if rf, ok := ret.Get(0).(func(context.Context, db.CreateEntryParams) db.Entry); ok {
+	var r0 db.Transfer
+	if rf, ok := ret.Get(0).(func(context.Context, db.AddTransferRefundParams) db.Transfer); ok {
 		r0 = rf(ctx, arg)
 	} else {
-		r0 = ret.Get(0).(db.Entry)
+		r0 = ret.Get(0).(db.Transfer)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, db.CreateEntryParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, db.AddTransferRefundParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -77,20 +78,20 @@ func (_m *Store) CreateEntry(ctx context.Context, arg db.CreateEntryParams) (db.
 	return r0, r1
 }
 
-// CreateTransfer provides a mock function with given fields: ctx, arg
-func (_m *Store) CreateTransfer(ctx context.Context, arg db.CreateTransferParams) (db.Transfer, error) {
-	ret := _m.Called(ctx, arg)
+// AdjustBalanceTx provides a mock function with given fields: ctx, args
+func (_m *Store) AdjustBalanceTx(ctx context.Context, args db.AdjustBalanceTxParams) (db.AdjustBalanceTxResult, error) {
+	ret := _m.Called(ctx, args)
 
-	var r0 db.Transfer
-	if rf, ok := ret.Get(0).(func(context.Context, db.CreateTransferParams) db.Transfer); ok {
-		r0 = rf(ctx, arg)
+	var r0 db.AdjustBalanceTxResult
+	if rf, ok := ret.Get(0).(func(context.Context, db.AdjustBalanceTxParams) db.AdjustBalanceTxResult); ok {
+		r0 = rf(ctx, args)
 	} else {
-		r0 = ret.Get(0).(db.Transfer)
+		r0 = ret.Get(0).(db.AdjustBalanceTxResult)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, db.CreateTransferParams) error); ok {
-		r1 = rf(ctx, arg)
+	if rf, ok := ret.Get(1).(func(context.Context, db.AdjustBalanceTxParams) error); ok {
+		r1 = rf(ctx, args)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -98,19 +99,19 @@ func (_m *Store) CreateTransfer(ctx context.Context, arg db.CreateTransferParams
 	return r0, r1
 }
 
-// CreateUser provides a mock function with given fields: ctx, arg
-func (_m *Store) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
+// AdvanceScheduledTransfer provides a mock function with given fields: ctx, arg
+func (_m *Store) AdvanceScheduledTransfer(ctx context.Context, arg db.AdvanceScheduledTransferParams) (db.ScheduledTransfer, error) {
 	ret := _m.Called(ctx, arg)
 
-	var r0 db.User
-	if rf, ok := ret.Get(0).(func(context.Context, db.CreateUserParams) db.User); ok {
+	var r0 db.ScheduledTransfer
+	if rf, ok := ret.Get(0).(func(context.Context, db.AdvanceScheduledTransferParams) db.ScheduledTransfer); ok {
 		r0 = rf(ctx, arg)
 	} else {
-		r0 = ret.Get(0).(db.User)
+		r0 = ret.Get(0).(db.ScheduledTransfer)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, db.CreateUserParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, db.AdvanceScheduledTransferParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -119,33 +120,40 @@ func (_m *Store) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.Us
 	return r0, r1
 }
 
-// DeleteAccount provides a mock function with given fields: ctx, id
-func (_m *Store) DeleteAccount(ctx context.Context, id int64) error {
-	ret := _m.Called(ctx, id)
+// BatchTransferTx provides a mock function with given fields: ctx, args
+func (_m *Store) BatchTransferTx(ctx context.Context, args db.BatchTransferTxParams) (db.BatchTransferTxResult, error) {
+	ret := _m.Called(ctx, args)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, id)
+	var r0 db.BatchTransferTxResult
+	if rf, ok := ret.Get(0).(func(context.Context, db.BatchTransferTxParams) db.BatchTransferTxResult); ok {
+		r0 = rf(ctx, args)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(db.BatchTransferTxResult)
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.BatchTransferTxParams) error); ok {
+		r1 = rf(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// GetAccount provides a mock function with given fields: ctx, id
-func (_m *Store) GetAccount(ctx context.Context, id int64) (db.Account, error) {
+// BlockSession provides a mock function with given fields: ctx, id
+func (_m *Store) BlockSession(ctx context.Context, id uuid.UUID) (db.Session, error) {
 	ret := _m.Called(ctx, id)
 
-	var r0 db.Account
-	if rf, ok := ret.Get(0).(func(context.Context, int64) db.Account); ok {
+	var r0 db.Session
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) db.Session); ok {
 		r0 = rf(ctx, id)
 	} else {
-		r0 = ret.Get(0).(db.Account)
+		r0 = ret.Get(0).(db.Session)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
 		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
@@ -154,20 +162,20 @@ func (_m *Store) GetAccount(ctx context.Context, id int64) (db.Account, error) {
 	return r0, r1
 }
 
-// GetAccountForUpdate provides a mock function with given fields: ctx, id
-func (_m *Store) GetAccountForUpdate(ctx context.Context, id int64) (db.Account, error) {
-	ret := _m.Called(ctx, id)
+// BlockUserSessions provides a mock function with given fields: ctx, username
+func (_m *Store) BlockUserSessions(ctx context.Context, username string) (int64, error) {
+	ret := _m.Called(ctx, username)
 
-	var r0 db.Account
-	if rf, ok := ret.Get(0).(func(context.Context, int64) db.Account); ok {
-		r0 = rf(ctx, id)
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, username)
 	} else {
-		r0 = ret.Get(0).(db.Account)
+		r0 = ret.Get(0).(int64)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -175,15 +183,15 @@ func (_m *Store) GetAccountForUpdate(ctx context.Context, id int64) (db.Account,
 	return r0, r1
 }
 
-// GetEntry provides a mock function with given fields: ctx, id
-func (_m *Store) GetEntry(ctx context.Context, id int64) (db.Entry, error) {
+// CancelScheduledTransfer provides a mock function with given fields: ctx, id
+func (_m *Store) CancelScheduledTransfer(ctx context.Context, id int64) (db.ScheduledTransfer, error) {
 	ret := _m.Called(ctx, id)
 
-	var r0 db.Entry
-	if rf, ok := ret.Get(0).(func(context.Context, int64) db.Entry); ok {
+	var r0 db.ScheduledTransfer
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.ScheduledTransfer); ok {
 		r0 = rf(ctx, id)
 	} else {
-		r0 = ret.Get(0).(db.Entry)
+		r0 = ret.Get(0).(db.ScheduledTransfer)
 	}
 
 	var r1 error
@@ -196,20 +204,20 @@ func (_m *Store) GetEntry(ctx context.Context, id int64) (db.Entry, error) {
 	return r0, r1
 }
 
-// GetTransfer provides a mock function with given fields: ctx, id
-func (_m *Store) GetTransfer(ctx context.Context, id int64) (db.Transfer, error) {
-	ret := _m.Called(ctx, id)
+// CaptureHold provides a mock function with given fields: ctx, args
+func (_m *Store) CaptureHold(ctx context.Context, args db.CaptureHoldParams) (db.CaptureHoldResult, error) {
+	ret := _m.Called(ctx, args)
 
-	var r0 db.Transfer
-	if rf, ok := ret.Get(0).(func(context.Context, int64) db.Transfer); ok {
-		r0 = rf(ctx, id)
+	var r0 db.CaptureHoldResult
+	if rf, ok := ret.Get(0).(func(context.Context, db.CaptureHoldParams) db.CaptureHoldResult); ok {
+		r0 = rf(ctx, args)
 	} else {
-		r0 = ret.Get(0).(db.Transfer)
+		r0 = ret.Get(0).(db.CaptureHoldResult)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, db.CaptureHoldParams) error); ok {
+		r1 = rf(ctx, args)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -217,20 +225,20 @@ func (_m *Store) GetTransfer(ctx context.Context, id int64) (db.Transfer, error)
 	return r0, r1
 }
 
-// GetUser provides a mock function with given fields: ctx, username
-func (_m *Store) GetUser(ctx context.Context, username string) (db.User, error) {
-	ret := _m.Called(ctx, username)
+// ChangeAccountStatusTx provides a mock function with given fields: ctx, args
+func (_m *Store) ChangeAccountStatusTx(ctx context.Context, args db.ChangeAccountStatusTxParams) (db.Account, error) {
+	ret := _m.Called(ctx, args)
 
-	var r0 db.User
-	if rf, ok := ret.Get(0).(func(context.Context, string) db.User); ok {
-		r0 = rf(ctx, username)
+	var r0 db.Account
+	if rf, ok := ret.Get(0).(func(context.Context, db.ChangeAccountStatusTxParams) db.Account); ok {
+		r0 = rf(ctx, args)
 	} else {
-		r0 = ret.Get(0).(db.User)
+		r0 = ret.Get(0).(db.Account)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, username)
+	if rf, ok := ret.Get(1).(func(context.Context, db.ChangeAccountStatusTxParams) error); ok {
+		r1 = rf(ctx, args)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -238,21 +246,65 @@ func (_m *Store) GetUser(ctx context.Context, username string) (db.User, error)
 	return r0, r1
 }
 
-// ListAccounts provides a mock function with given fields: ctx, arg
-func (_m *Store) ListAccounts(ctx context.Context, arg db.ListAccountsParams) ([]db.Account, error) {
+// ClaimDueScheduledTransfer provides a mock function with given fields: ctx
+func (_m *Store) ClaimDueScheduledTransfer(ctx context.Context) (db.ScheduledTransfer, error) {
+	ret := _m.Called(ctx)
+
+	var r0 db.ScheduledTransfer
+	if rf, ok := ret.Get(0).(func(context.Context) db.ScheduledTransfer); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(db.ScheduledTransfer)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimExpiredHolds provides a mock function with given fields: ctx, limit
+func (_m *Store) ClaimExpiredHolds(ctx context.Context, limit int32) ([]db.Hold, error) {
+	ret := _m.Called(ctx, limit)
+
+	var r0 []db.Hold
+	if rf, ok := ret.Get(0).(func(context.Context, int32) []db.Hold); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Hold)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimOutboxEvents provides a mock function with given fields: ctx, arg
+func (_m *Store) ClaimOutboxEvents(ctx context.Context, arg db.ClaimOutboxEventsParams) ([]db.OutboxEvent, error) {
 	ret := _m.Called(ctx, arg)
 
-	var r0 []db.Account
-	if rf, ok := ret.Get(0).(func(context.Context, db.ListAccountsParams) []db.Account); ok {
+	var r0 []db.OutboxEvent
+	if rf, ok := ret.Get(0).(func(context.Context, db.ClaimOutboxEventsParams) []db.OutboxEvent); ok {
 		r0 = rf(ctx, arg)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]db.Account)
+			r0 = ret.Get(0).([]db.OutboxEvent)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, db.ListAccountsParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, db.ClaimOutboxEventsParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -261,21 +313,21 @@ func (_m *Store) ListAccounts(ctx context.Context, arg db.ListAccountsParams) ([
 	return r0, r1
 }
 
-// ListEntries provides a mock function with given fields: ctx, arg
-func (_m *Store) ListEntries(ctx context.Context, arg db.ListEntriesParams) ([]db.Entry, error) {
+// ClaimWebhookDeliveries provides a mock function with given fields: ctx, arg
+func (_m *Store) ClaimWebhookDeliveries(ctx context.Context, arg db.ClaimWebhookDeliveriesParams) ([]db.ClaimWebhookDeliveriesRow, error) {
 	ret := _m.Called(ctx, arg)
 
-	var r0 []db.Entry
-	if rf, ok := ret.Get(0).(func(context.Context, db.ListEntriesParams) []db.Entry); ok {
+	var r0 []db.ClaimWebhookDeliveriesRow
+	if rf, ok := ret.Get(0).(func(context.Context, db.ClaimWebhookDeliveriesParams) []db.ClaimWebhookDeliveriesRow); ok {
 		r0 = rf(ctx, arg)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]db.Entry)
+			r0 = ret.Get(0).([]db.ClaimWebhookDeliveriesRow)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, db.ListEntriesParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, db.ClaimWebhookDeliveriesParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -284,21 +336,19 @@ func (_m *Store) ListEntries(ctx context.Context, arg db.ListEntriesParams) ([]d
 	return r0, r1
 }
 
-// ListTransfers provides a mock function with given fields: ctx, arg
-func (_m *Store) ListTransfers(ctx context.Context, arg db.ListTransfersParams) ([]db.Transfer, error) {
+// CreateAccount provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateAccount(ctx context.Context, arg db.CreateAccountParams) (db.Account, error) {
 	ret := _m.Called(ctx, arg)
 
-	var r0 []db.Transfer
-	if rf, ok := ret.Get(0).(func(context.Context, db.ListTransfersParams) []db.Transfer); ok {
+	var r0 db.Account
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateAccountParams) db.Account); ok {
 		r0 = rf(ctx, arg)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]db.Transfer)
-		}
+		r0 = ret.Get(0).(db.Account)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, db.ListTransfersParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateAccountParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -307,19 +357,19 @@ func (_m *Store) ListTransfers(ctx context.Context, arg db.ListTransfersParams)
 	return r0, r1
 }
 
-// TransferTx provides a mock function with given fields: ctx, args
-func (_m *Store) TransferTx(ctx context.Context, args db.TransferTxParams) (db.TransferTxResult, error) {
+// CreateAccountTx provides a mock function with given fields: ctx, args
+func (_m *Store) CreateAccountTx(ctx context.Context, args db.CreateAccountParams) (db.Account, error) {
 	ret := _m.Called(ctx, args)
 
-	var r0 db.TransferTxResult
-	if rf, ok := ret.Get(0).(func(context.Context, db.TransferTxParams) db.TransferTxResult); ok {
+	var r0 db.Account
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateAccountParams) db.Account); ok {
 		r0 = rf(ctx, args)
 	} else {
-		r0 = ret.Get(0).(db.TransferTxResult)
+		r0 = ret.Get(0).(db.Account)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, db.TransferTxParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateAccountParams) error); ok {
 		r1 = rf(ctx, args)
 	} else {
 		r1 = ret.Error(1)
@@ -328,19 +378,145 @@ func (_m *Store) TransferTx(ctx context.Context, args db.TransferTxParams) (db.T
 	return r0, r1
 }
 
-// UpdateAccount provides a mock function with given fields: ctx, arg
-func (_m *Store) UpdateAccount(ctx context.Context, arg db.UpdateAccountParams) (db.Account, error) {
+// CreateAuditEvent provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateAuditEvent(ctx context.Context, arg db.CreateAuditEventParams) (db.AuditEvent, error) {
 	ret := _m.Called(ctx, arg)
 
-	var r0 db.Account
-	if rf, ok := ret.Get(0).(func(context.Context, db.UpdateAccountParams) db.Account); ok {
+	var r0 db.AuditEvent
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateAuditEventParams) db.AuditEvent); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.AuditEvent)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateAuditEventParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateBalanceAdjustment provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateBalanceAdjustment(ctx context.Context, arg db.CreateBalanceAdjustmentParams) (db.BalanceAdjustment, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.BalanceAdjustment
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateBalanceAdjustmentParams) db.BalanceAdjustment); ok {
 		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.BalanceAdjustment)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateBalanceAdjustmentParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateCashAccount provides a mock function with given fields: ctx, currency
+func (_m *Store) CreateCashAccount(ctx context.Context, currency string) (db.Account, error) {
+	ret := _m.Called(ctx, currency)
+
+	var r0 db.Account
+	if rf, ok := ret.Get(0).(func(context.Context, string) db.Account); ok {
+		r0 = rf(ctx, currency)
 	} else {
 		r0 = ret.Get(0).(db.Account)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, db.UpdateAccountParams) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateCurrency provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateCurrency(ctx context.Context, arg db.CreateCurrencyParams) (db.Currency, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.Currency
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateCurrencyParams) db.Currency); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.Currency)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateCurrencyParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateCurrencyTx provides a mock function with given fields: ctx, args
+func (_m *Store) CreateCurrencyTx(ctx context.Context, args db.CreateCurrencyParams) (db.Currency, error) {
+	ret := _m.Called(ctx, args)
+
+	var r0 db.Currency
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateCurrencyParams) db.Currency); ok {
+		r0 = rf(ctx, args)
+	} else {
+		r0 = ret.Get(0).(db.Currency)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateCurrencyParams) error); ok {
+		r1 = rf(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateEntry provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateEntry(ctx context.Context, arg db.CreateEntryParams) (db.Entry, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.Entry
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateEntryParams) db.Entry); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.Entry)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateEntryParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateHold provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateHold(ctx context.Context, arg db.CreateHoldParams) (db.Hold, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.Hold
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateHoldParams) db.Hold); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.Hold)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateHoldParams) error); ok {
 		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
@@ -348,3 +524,1818 @@ func (_m *Store) UpdateAccount(ctx context.Context, arg db.UpdateAccountParams)
 
 	return r0, r1
 }
+
+// CreateIdempotencyKey provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateIdempotencyKey(ctx context.Context, arg db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.IdempotencyKey
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateIdempotencyKeyParams) db.IdempotencyKey); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.IdempotencyKey)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateIdempotencyKeyParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateOutboxEvent provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateOutboxEvent(ctx context.Context, arg db.CreateOutboxEventParams) (db.OutboxEvent, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.OutboxEvent
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateOutboxEventParams) db.OutboxEvent); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.OutboxEvent)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateOutboxEventParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateRevokedToken provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateRevokedToken(ctx context.Context, arg db.CreateRevokedTokenParams) error {
+	ret := _m.Called(ctx, arg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateRevokedTokenParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateScheduledTransfer provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateScheduledTransfer(ctx context.Context, arg db.CreateScheduledTransferParams) (db.ScheduledTransfer, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.ScheduledTransfer
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateScheduledTransferParams) db.ScheduledTransfer); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.ScheduledTransfer)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateScheduledTransferParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateScheduledTransferRun provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateScheduledTransferRun(ctx context.Context, arg db.CreateScheduledTransferRunParams) (db.ScheduledTransferRun, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.ScheduledTransferRun
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateScheduledTransferRunParams) db.ScheduledTransferRun); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.ScheduledTransferRun)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateScheduledTransferRunParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateSession provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateSession(ctx context.Context, arg db.CreateSessionParams) (db.Session, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.Session
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateSessionParams) db.Session); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.Session)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateSessionParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTransfer provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateTransfer(ctx context.Context, arg db.CreateTransferParams) (db.Transfer, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.Transfer
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateTransferParams) db.Transfer); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.Transfer)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateTransferParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateUser provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.User
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateUserParams) db.User); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateUserParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateUserTx provides a mock function with given fields: ctx, args
+func (_m *Store) CreateUserTx(ctx context.Context, args db.CreateUserParams) (db.User, error) {
+	ret := _m.Called(ctx, args)
+
+	var r0 db.User
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateUserParams) db.User); ok {
+		r0 = rf(ctx, args)
+	} else {
+		r0 = ret.Get(0).(db.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateUserParams) error); ok {
+		r1 = rf(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWebhookDeliveryAttempt provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateWebhookDeliveryAttempt(ctx context.Context, arg db.CreateWebhookDeliveryAttemptParams) (db.WebhookDeliveryAttempt, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.WebhookDeliveryAttempt
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateWebhookDeliveryAttemptParams) db.WebhookDeliveryAttempt); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.WebhookDeliveryAttempt)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateWebhookDeliveryAttemptParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWebhookSubscription provides a mock function with given fields: ctx, arg
+func (_m *Store) CreateWebhookSubscription(ctx context.Context, arg db.CreateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.WebhookSubscription
+	if rf, ok := ret.Get(0).(func(context.Context, db.CreateWebhookSubscriptionParams) db.WebhookSubscription); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.WebhookSubscription)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CreateWebhookSubscriptionParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeactivateWebhookSubscription provides a mock function with given fields: ctx, id
+func (_m *Store) DeactivateWebhookSubscription(ctx context.Context, id int64) (db.WebhookSubscription, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.WebhookSubscription
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.WebhookSubscription); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.WebhookSubscription)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteExpiredRevokedTokens provides a mock function with given fields: ctx
+func (_m *Store) DeleteExpiredRevokedTokens(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteExpiredUserRevocations provides a mock function with given fields: ctx
+func (_m *Store) DeleteExpiredUserRevocations(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTransferLimit provides a mock function with given fields: ctx, id
+func (_m *Store) DeleteTransferLimit(ctx context.Context, id int64) (db.TransferLimit, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.TransferLimit
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.TransferLimit); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.TransferLimit)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeliverWebhooksTx provides a mock function with given fields: ctx, args
+func (_m *Store) DeliverWebhooksTx(ctx context.Context, args db.DeliverWebhooksTxParams) (db.DeliverWebhooksTxResult, error) {
+	ret := _m.Called(ctx, args)
+
+	var r0 db.DeliverWebhooksTxResult
+	if rf, ok := ret.Get(0).(func(context.Context, db.DeliverWebhooksTxParams) db.DeliverWebhooksTxResult); ok {
+		r0 = rf(ctx, args)
+	} else {
+		r0 = ret.Get(0).(db.DeliverWebhooksTxResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.DeliverWebhooksTxParams) error); ok {
+		r1 = rf(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DepositTx provides a mock function with given fields: ctx, args
+func (_m *Store) DepositTx(ctx context.Context, args db.CashTxParams) (db.CashTxResult, error) {
+	ret := _m.Called(ctx, args)
+
+	var r0 db.CashTxResult
+	if rf, ok := ret.Get(0).(func(context.Context, db.CashTxParams) db.CashTxResult); ok {
+		r0 = rf(ctx, args)
+	} else {
+		r0 = ret.Get(0).(db.CashTxResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CashTxParams) error); ok {
+		r1 = rf(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EnqueueWebhookDeliveries provides a mock function with given fields: ctx, arg
+func (_m *Store) EnqueueWebhookDeliveries(ctx context.Context, arg db.EnqueueWebhookDeliveriesParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, db.EnqueueWebhookDeliveriesParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.EnqueueWebhookDeliveriesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpireHolds provides a mock function with given fields: ctx, limit
+func (_m *Store) ExpireHolds(ctx context.Context, limit int32) (int, error) {
+	ret := _m.Called(ctx, limit)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, int32) int); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishHold provides a mock function with given fields: ctx, arg
+func (_m *Store) FinishHold(ctx context.Context, arg db.FinishHoldParams) (db.Hold, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.Hold
+	if rf, ok := ret.Get(0).(func(context.Context, db.FinishHoldParams) db.Hold); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.Hold)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.FinishHoldParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccount provides a mock function with given fields: ctx, id
+func (_m *Store) GetAccount(ctx context.Context, id int64) (db.Account, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.Account
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.Account); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.Account)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountBalanceAt provides a mock function with given fields: ctx, arg
+func (_m *Store) GetAccountBalanceAt(ctx context.Context, arg db.GetAccountBalanceAtParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetAccountBalanceAtParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.GetAccountBalanceAtParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountForUpdate provides a mock function with given fields: ctx, id
+func (_m *Store) GetAccountForUpdate(ctx context.Context, id int64) (db.Account, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.Account
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.Account); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.Account)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountTransferLimit provides a mock function with given fields: ctx, arg
+func (_m *Store) GetAccountTransferLimit(ctx context.Context, arg db.GetAccountTransferLimitParams) (db.TransferLimit, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.TransferLimit
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetAccountTransferLimitParams) db.TransferLimit); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.TransferLimit)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.GetAccountTransferLimitParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCashAccount provides a mock function with given fields: ctx, currency
+func (_m *Store) GetCashAccount(ctx context.Context, currency string) (db.Account, error) {
+	ret := _m.Called(ctx, currency)
+
+	var r0 db.Account
+	if rf, ok := ret.Get(0).(func(context.Context, string) db.Account); ok {
+		r0 = rf(ctx, currency)
+	} else {
+		r0 = ret.Get(0).(db.Account)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCurrency provides a mock function with given fields: ctx, code
+func (_m *Store) GetCurrency(ctx context.Context, code string) (db.Currency, error) {
+	ret := _m.Called(ctx, code)
+
+	var r0 db.Currency
+	if rf, ok := ret.Get(0).(func(context.Context, string) db.Currency); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Get(0).(db.Currency)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEntry provides a mock function with given fields: ctx, id
+func (_m *Store) GetEntry(ctx context.Context, id int64) (db.Entry, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.Entry
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.Entry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.Entry)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHold provides a mock function with given fields: ctx, id
+func (_m *Store) GetHold(ctx context.Context, id int64) (db.Hold, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.Hold
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.Hold); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.Hold)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHoldForUpdate provides a mock function with given fields: ctx, id
+func (_m *Store) GetHoldForUpdate(ctx context.Context, id int64) (db.Hold, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.Hold
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.Hold); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.Hold)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIdempotencyKey provides a mock function with given fields: ctx, arg
+func (_m *Store) GetIdempotencyKey(ctx context.Context, arg db.GetIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.IdempotencyKey
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetIdempotencyKeyParams) db.IdempotencyKey); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.IdempotencyKey)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.GetIdempotencyKeyParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetScheduledTransfer provides a mock function with given fields: ctx, id
+func (_m *Store) GetScheduledTransfer(ctx context.Context, id int64) (db.ScheduledTransfer, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.ScheduledTransfer
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.ScheduledTransfer); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.ScheduledTransfer)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSession provides a mock function with given fields: ctx, id
+func (_m *Store) GetSession(ctx context.Context, id uuid.UUID) (db.Session, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.Session
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) db.Session); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.Session)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransfer provides a mock function with given fields: ctx, id
+func (_m *Store) GetTransfer(ctx context.Context, id int64) (db.Transfer, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.Transfer
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.Transfer); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.Transfer)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransferForUpdate provides a mock function with given fields: ctx, id
+func (_m *Store) GetTransferForUpdate(ctx context.Context, id int64) (db.Transfer, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.Transfer
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.Transfer); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.Transfer)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUser provides a mock function with given fields: ctx, username
+func (_m *Store) GetUser(ctx context.Context, username string) (db.User, error) {
+	ret := _m.Called(ctx, username)
+
+	var r0 db.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) db.User); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(db.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserTransferLimit provides a mock function with given fields: ctx, arg
+func (_m *Store) GetUserTransferLimit(ctx context.Context, arg db.GetUserTransferLimitParams) (db.TransferLimit, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.TransferLimit
+	if rf, ok := ret.Get(0).(func(context.Context, db.GetUserTransferLimitParams) db.TransferLimit); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.TransferLimit)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.GetUserTransferLimitParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhookDelivery provides a mock function with given fields: ctx, id
+func (_m *Store) GetWebhookDelivery(ctx context.Context, id int64) (db.WebhookDelivery, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.WebhookDelivery); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.WebhookDelivery)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhookSubscription provides a mock function with given fields: ctx, id
+func (_m *Store) GetWebhookSubscription(ctx context.Context, id int64) (db.WebhookSubscription, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.WebhookSubscription
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.WebhookSubscription); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.WebhookSubscription)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HoldExpired provides a mock function with given fields: ctx, id
+func (_m *Store) HoldExpired(ctx context.Context, id int64) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int64) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IdempotentTransferTx provides a mock function with given fields: ctx, args
+func (_m *Store) IdempotentTransferTx(ctx context.Context, args db.IdempotentTransferTxParams) (db.TransferTxResult, error) {
+	ret := _m.Called(ctx, args)
+
+	var r0 db.TransferTxResult
+	if rf, ok := ret.Get(0).(func(context.Context, db.IdempotentTransferTxParams) db.TransferTxResult); ok {
+		r0 = rf(ctx, args)
+	} else {
+		r0 = ret.Get(0).(db.TransferTxResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.IdempotentTransferTxParams) error); ok {
+		r1 = rf(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsTokenRevoked provides a mock function with given fields: ctx, arg
+func (_m *Store) IsTokenRevoked(ctx context.Context, arg db.IsTokenRevokedParams) (bool, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, db.IsTokenRevokedParams) bool); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.IsTokenRevokedParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAccountBalanceMismatches provides a mock function with given fields: ctx
+func (_m *Store) ListAccountBalanceMismatches(ctx context.Context) ([]db.ListAccountBalanceMismatchesRow, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []db.ListAccountBalanceMismatchesRow
+	if rf, ok := ret.Get(0).(func(context.Context) []db.ListAccountBalanceMismatchesRow); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.ListAccountBalanceMismatchesRow)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAccountTransfers provides a mock function with given fields: ctx, arg
+func (_m *Store) ListAccountTransfers(ctx context.Context, arg db.ListAccountTransfersParams) ([]db.Transfer, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []db.Transfer
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListAccountTransfersParams) []db.Transfer); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Transfer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListAccountTransfersParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAccounts provides a mock function with given fields: ctx, arg
+func (_m *Store) ListAccounts(ctx context.Context, arg db.ListAccountsParams) ([]db.Account, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []db.Account
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListAccountsParams) []db.Account); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Account)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListAccountsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAccountsByIDs provides a mock function with given fields: ctx, ids
+func (_m *Store) ListAccountsByIDs(ctx context.Context, ids []int64) ([]db.Account, error) {
+	ret := _m.Called(ctx, ids)
+
+	var r0 []db.Account
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) []db.Account); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Account)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAllAccounts provides a mock function with given fields: ctx, arg
+func (_m *Store) ListAllAccounts(ctx context.Context, arg db.ListAllAccountsParams) ([]db.Account, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []db.Account
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListAllAccountsParams) []db.Account); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Account)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListAllAccountsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAuditEvents provides a mock function with given fields: ctx, arg
+func (_m *Store) ListAuditEvents(ctx context.Context, arg db.ListAuditEventsParams) ([]db.AuditEvent, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []db.AuditEvent
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListAuditEventsParams) []db.AuditEvent); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.AuditEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListAuditEventsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListBalanceAdjustments provides a mock function with given fields: ctx, arg
+func (_m *Store) ListBalanceAdjustments(ctx context.Context, arg db.ListBalanceAdjustmentsParams) ([]db.BalanceAdjustment, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []db.BalanceAdjustment
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListBalanceAdjustmentsParams) []db.BalanceAdjustment); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.BalanceAdjustment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListBalanceAdjustmentsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListCurrencies provides a mock function with given fields: ctx
+func (_m *Store) ListCurrencies(ctx context.Context) ([]db.Currency, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []db.Currency
+	if rf, ok := ret.Get(0).(func(context.Context) []db.Currency); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Currency)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListEntries provides a mock function with given fields: ctx, arg
+func (_m *Store) ListEntries(ctx context.Context, arg db.ListEntriesParams) ([]db.Entry, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []db.Entry
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListEntriesParams) []db.Entry); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Entry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListEntriesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListEntryLegs provides a mock function with given fields: ctx, id
+func (_m *Store) ListEntryLegs(ctx context.Context, id int64) ([]db.Entry, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []db.Entry
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []db.Entry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Entry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListHolds provides a mock function with given fields: ctx, arg
+func (_m *Store) ListHolds(ctx context.Context, arg db.ListHoldsParams) ([]db.Hold, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []db.Hold
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListHoldsParams) []db.Hold); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Hold)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListHoldsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOutboxEvents provides a mock function with given fields: ctx, arg
+func (_m *Store) ListOutboxEvents(ctx context.Context, arg db.ListOutboxEventsParams) ([]db.OutboxEvent, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []db.OutboxEvent
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListOutboxEventsParams) []db.OutboxEvent); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.OutboxEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListOutboxEventsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListScheduledTransferRuns provides a mock function with given fields: ctx, arg
+func (_m *Store) ListScheduledTransferRuns(ctx context.Context, arg db.ListScheduledTransferRunsParams) ([]db.ScheduledTransferRun, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []db.ScheduledTransferRun
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListScheduledTransferRunsParams) []db.ScheduledTransferRun); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.ScheduledTransferRun)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListScheduledTransferRunsParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListScheduledTransfers provides a mock function with given fields: ctx, fromAccountID
+func (_m *Store) ListScheduledTransfers(ctx context.Context, fromAccountID int64) ([]db.ScheduledTransfer, error) {
+	ret := _m.Called(ctx, fromAccountID)
+
+	var r0 []db.ScheduledTransfer
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []db.ScheduledTransfer); ok {
+		r0 = rf(ctx, fromAccountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.ScheduledTransfer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, fromAccountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListStatementEntries provides a mock function with given fields: ctx, arg
+func (_m *Store) ListStatementEntries(ctx context.Context, arg db.ListStatementEntriesParams) ([]db.Entry, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []db.Entry
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListStatementEntriesParams) []db.Entry); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Entry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListStatementEntriesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTransferEntries provides a mock function with given fields: ctx, transferID
+func (_m *Store) ListTransferEntries(ctx context.Context, transferID int64) ([]db.Entry, error) {
+	ret := _m.Called(ctx, transferID)
+
+	var r0 []db.Entry
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []db.Entry); ok {
+		r0 = rf(ctx, transferID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Entry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, transferID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTransferLimits provides a mock function with given fields: ctx, currency
+func (_m *Store) ListTransferLimits(ctx context.Context, currency string) ([]db.TransferLimit, error) {
+	ret := _m.Called(ctx, currency)
+
+	var r0 []db.TransferLimit
+	if rf, ok := ret.Get(0).(func(context.Context, string) []db.TransferLimit); ok {
+		r0 = rf(ctx, currency)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.TransferLimit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTransfers provides a mock function with given fields: ctx, arg
+func (_m *Store) ListTransfers(ctx context.Context, arg db.ListTransfersParams) ([]db.Transfer, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []db.Transfer
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListTransfersParams) []db.Transfer); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.Transfer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListTransfersParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUnbalancedTransfers provides a mock function with given fields: ctx
+func (_m *Store) ListUnbalancedTransfers(ctx context.Context) ([]db.ListUnbalancedTransfersRow, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []db.ListUnbalancedTransfersRow
+	if rf, ok := ret.Get(0).(func(context.Context) []db.ListUnbalancedTransfersRow); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.ListUnbalancedTransfersRow)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebhookDeliveries provides a mock function with given fields: ctx, arg
+func (_m *Store) ListWebhookDeliveries(ctx context.Context, arg db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 []db.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(context.Context, db.ListWebhookDeliveriesParams) []db.WebhookDelivery); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.WebhookDelivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ListWebhookDeliveriesParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebhookDeliveryAttempts provides a mock function with given fields: ctx, deliveryID
+func (_m *Store) ListWebhookDeliveryAttempts(ctx context.Context, deliveryID int64) ([]db.WebhookDeliveryAttempt, error) {
+	ret := _m.Called(ctx, deliveryID)
+
+	var r0 []db.WebhookDeliveryAttempt
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []db.WebhookDeliveryAttempt); ok {
+		r0 = rf(ctx, deliveryID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.WebhookDeliveryAttempt)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebhookSubscriptions provides a mock function with given fields: ctx, accountID
+func (_m *Store) ListWebhookSubscriptions(ctx context.Context, accountID int64) ([]db.WebhookSubscription, error) {
+	ret := _m.Called(ctx, accountID)
+
+	var r0 []db.WebhookSubscription
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []db.WebhookSubscription); ok {
+		r0 = rf(ctx, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]db.WebhookSubscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockUser provides a mock function with given fields: ctx, username
+func (_m *Store) LockUser(ctx context.Context, username string) (string, error) {
+	ret := _m.Called(ctx, username)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkOutboxEventFailed provides a mock function with given fields: ctx, arg
+func (_m *Store) MarkOutboxEventFailed(ctx context.Context, arg db.MarkOutboxEventFailedParams) error {
+	ret := _m.Called(ctx, arg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.MarkOutboxEventFailedParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkOutboxEventPublished provides a mock function with given fields: ctx, id
+func (_m *Store) MarkOutboxEventPublished(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PlaceHold provides a mock function with given fields: ctx, args
+func (_m *Store) PlaceHold(ctx context.Context, args db.PlaceHoldParams) (db.HoldResult, error) {
+	ret := _m.Called(ctx, args)
+
+	var r0 db.HoldResult
+	if rf, ok := ret.Get(0).(func(context.Context, db.PlaceHoldParams) db.HoldResult); ok {
+		r0 = rf(ctx, args)
+	} else {
+		r0 = ret.Get(0).(db.HoldResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.PlaceHoldParams) error); ok {
+		r1 = rf(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProcessOutbox provides a mock function with given fields: ctx, args
+func (_m *Store) ProcessOutbox(ctx context.Context, args db.ProcessOutboxParams) (db.ProcessOutboxResult, error) {
+	ret := _m.Called(ctx, args)
+
+	var r0 db.ProcessOutboxResult
+	if rf, ok := ret.Get(0).(func(context.Context, db.ProcessOutboxParams) db.ProcessOutboxResult); ok {
+		r0 = rf(ctx, args)
+	} else {
+		r0 = ret.Get(0).(db.ProcessOutboxResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ProcessOutboxParams) error); ok {
+		r1 = rf(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reconcile provides a mock function with given fields: ctx
+func (_m *Store) Reconcile(ctx context.Context) (db.ReconciliationReport, error) {
+	ret := _m.Called(ctx)
+
+	var r0 db.ReconciliationReport
+	if rf, ok := ret.Get(0).(func(context.Context) db.ReconciliationReport); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(db.ReconciliationReport)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseHold provides a mock function with given fields: ctx, holdID
+func (_m *Store) ReleaseHold(ctx context.Context, holdID int64) (db.HoldResult, error) {
+	ret := _m.Called(ctx, holdID)
+
+	var r0 db.HoldResult
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.HoldResult); ok {
+		r0 = rf(ctx, holdID)
+	} else {
+		r0 = ret.Get(0).(db.HoldResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, holdID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplayWebhookDelivery provides a mock function with given fields: ctx, id
+func (_m *Store) ReplayWebhookDelivery(ctx context.Context, id int64) (db.WebhookDelivery, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 db.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(context.Context, int64) db.WebhookDelivery); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(db.WebhookDelivery)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReverseTransferTx provides a mock function with given fields: ctx, args
+func (_m *Store) ReverseTransferTx(ctx context.Context, args db.ReverseTransferTxParams) (db.ReverseTransferTxResult, error) {
+	ret := _m.Called(ctx, args)
+
+	var r0 db.ReverseTransferTxResult
+	if rf, ok := ret.Get(0).(func(context.Context, db.ReverseTransferTxParams) db.ReverseTransferTxResult); ok {
+		r0 = rf(ctx, args)
+	} else {
+		r0 = ret.Get(0).(db.ReverseTransferTxResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.ReverseTransferTxParams) error); ok {
+		r1 = rf(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RunScheduledTransferTx provides a mock function with given fields: ctx, args
+func (_m *Store) RunScheduledTransferTx(ctx context.Context, args db.RunScheduledTransferTxParams) (db.RunScheduledTransferTxResult, error) {
+	ret := _m.Called(ctx, args)
+
+	var r0 db.RunScheduledTransferTxResult
+	if rf, ok := ret.Get(0).(func(context.Context, db.RunScheduledTransferTxParams) db.RunScheduledTransferTxResult); ok {
+		r0 = rf(ctx, args)
+	} else {
+		r0 = ret.Get(0).(db.RunScheduledTransferTxResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.RunScheduledTransferTxParams) error); ok {
+		r1 = rf(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SumAccountTransfersSince provides a mock function with given fields: ctx, arg
+func (_m *Store) SumAccountTransfersSince(ctx context.Context, arg db.SumAccountTransfersSinceParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, db.SumAccountTransfersSinceParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.SumAccountTransfersSinceParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SumUserHeldBalance provides a mock function with given fields: ctx, arg
+func (_m *Store) SumUserHeldBalance(ctx context.Context, arg db.SumUserHeldBalanceParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, db.SumUserHeldBalanceParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.SumUserHeldBalanceParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SumUserTransfersSince provides a mock function with given fields: ctx, arg
+func (_m *Store) SumUserTransfersSince(ctx context.Context, arg db.SumUserTransfersSinceParams) (int64, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, db.SumUserTransfersSinceParams) int64); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.SumUserTransfersSinceParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransferTx provides a mock function with given fields: ctx, args
+func (_m *Store) TransferTx(ctx context.Context, args db.TransferTxParams) (db.TransferTxResult, error) {
+	ret := _m.Called(ctx, args)
+
+	var r0 db.TransferTxResult
+	if rf, ok := ret.Get(0).(func(context.Context, db.TransferTxParams) db.TransferTxResult); ok {
+		r0 = rf(ctx, args)
+	} else {
+		r0 = ret.Get(0).(db.TransferTxResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.TransferTxParams) error); ok {
+		r1 = rf(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAccountOverdraftLimit provides a mock function with given fields: ctx, arg
+func (_m *Store) UpdateAccountOverdraftLimit(ctx context.Context, arg db.UpdateAccountOverdraftLimitParams) (db.Account, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.Account
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpdateAccountOverdraftLimitParams) db.Account); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.Account)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.UpdateAccountOverdraftLimitParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAccountStatus provides a mock function with given fields: ctx, arg
+func (_m *Store) UpdateAccountStatus(ctx context.Context, arg db.UpdateAccountStatusParams) (db.Account, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.Account
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpdateAccountStatusParams) db.Account); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.Account)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.UpdateAccountStatusParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateCurrencyEnabled provides a mock function with given fields: ctx, arg
+func (_m *Store) UpdateCurrencyEnabled(ctx context.Context, arg db.UpdateCurrencyEnabledParams) (db.Currency, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.Currency
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpdateCurrencyEnabledParams) db.Currency); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.Currency)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.UpdateCurrencyEnabledParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateScheduledTransfer provides a mock function with given fields: ctx, arg
+func (_m *Store) UpdateScheduledTransfer(ctx context.Context, arg db.UpdateScheduledTransferParams) (db.ScheduledTransfer, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.ScheduledTransfer
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpdateScheduledTransferParams) db.ScheduledTransfer); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.ScheduledTransfer)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.UpdateScheduledTransferParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUserRole provides a mock function with given fields: ctx, arg
+func (_m *Store) UpdateUserRole(ctx context.Context, arg db.UpdateUserRoleParams) (db.User, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.User
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpdateUserRoleParams) db.User); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.UpdateUserRoleParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateWebhookDelivery provides a mock function with given fields: ctx, arg
+func (_m *Store) UpdateWebhookDelivery(ctx context.Context, arg db.UpdateWebhookDeliveryParams) error {
+	ret := _m.Called(ctx, arg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpdateWebhookDeliveryParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertTransferLimit provides a mock function with given fields: ctx, arg
+func (_m *Store) UpsertTransferLimit(ctx context.Context, arg db.UpsertTransferLimitParams) (db.TransferLimit, error) {
+	ret := _m.Called(ctx, arg)
+
+	var r0 db.TransferLimit
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpsertTransferLimitParams) db.TransferLimit); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(db.TransferLimit)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.UpsertTransferLimitParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertUserRevocation provides a mock function with given fields: ctx, arg
+func (_m *Store) UpsertUserRevocation(ctx context.Context, arg db.UpsertUserRevocationParams) error {
+	ret := _m.Called(ctx, arg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.UpsertUserRevocationParams) error); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithdrawTx provides a mock function with given fields: ctx, args
+func (_m *Store) WithdrawTx(ctx context.Context, args db.CashTxParams) (db.CashTxResult, error) {
+	ret := _m.Called(ctx, args)
+
+	var r0 db.CashTxResult
+	if rf, ok := ret.Get(0).(func(context.Context, db.CashTxParams) db.CashTxResult); ok {
+		r0 = rf(ctx, args)
+	} else {
+		r0 = ret.Get(0).(db.CashTxResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, db.CashTxParams) error); ok {
+		r1 = rf(ctx, args)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -16,6 +16,11 @@ SELECT * FROM accounts
 WHERE id = $1
 FOR NO KEY UPDATE;
 
+-- name: ListAccountsByIDs :many
+SELECT * FROM accounts
+WHERE id = ANY(sqlc.arg(ids)::bigint[])
+ORDER BY id;
+
 -- name: ListAccounts :many
 SELECT * FROM accounts
 WHERE owner = $1
@@ -23,11 +28,12 @@ ORDER BY id
 LIMIT $2
 OFFSET $3;
 
--- name: UpdateAccount :one
-UPDATE accounts
-SET balance = $2
-WHERE id = $1
-RETURNING *;
+-- name: ListAllAccounts :many
+SELECT * FROM accounts
+WHERE sqlc.arg(owner)::varchar = '' OR owner = sqlc.arg(owner)
+ORDER BY id
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
 
 -- name: AddAccountBalance :one
 UPDATE accounts
@@ -35,5 +41,34 @@ SET balance = balance + sqlc.arg(amount)
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
--- name: DeleteAccount :exec
-DELETE FROM accounts WHERE id = $1;
\ No newline at end of file
+-- name: AddAccountHeldBalance :one
+UPDATE accounts
+SET held_balance = held_balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = sqlc.arg(status),
+    closed_at = CASE WHEN sqlc.arg(status) = 'closed'::account_status THEN now() ELSE closed_at END
+WHERE id = sqlc.arg(id) AND owner <> 'banco-system'
+RETURNING *;
+
+-- name: CreateCashAccount :one
+INSERT INTO accounts (
+    owner,
+    balance,
+    currency
+) VALUES (
+    'banco-system', 0, $1
+) RETURNING *;
+
+-- name: GetCashAccount :one
+SELECT * FROM accounts
+WHERE owner = 'banco-system' AND currency = $1;
@@ -0,0 +1,27 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+    actor,
+    action,
+    resource_type,
+    resource_id,
+    request_id,
+    client_ip,
+    before,
+    after
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: ListAuditEvents :many
+SELECT * FROM audit_events
+WHERE
+    (sqlc.arg(actor)::varchar = '' OR actor = sqlc.arg(actor)) AND
+    (sqlc.arg(action)::varchar = '' OR action = sqlc.arg(action)) AND
+    (sqlc.arg(resource_type)::varchar = '' OR resource_type = sqlc.arg(resource_type)) AND
+    (sqlc.arg(resource_id)::varchar = '' OR resource_id = sqlc.arg(resource_id)) AND
+    (sqlc.arg(request_id)::varchar = '' OR request_id = sqlc.arg(request_id)) AND
+    created_at >= sqlc.arg(start_time) AND
+    created_at < sqlc.arg(end_time) AND
+    id < sqlc.arg(cursor)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_size);
@@ -0,0 +1,21 @@
+-- name: CreateCurrency :one
+INSERT INTO currencies (
+    code,
+    exponent
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: GetCurrency :one
+SELECT * FROM currencies
+WHERE code = $1 LIMIT 1;
+
+-- name: ListCurrencies :many
+SELECT * FROM currencies
+ORDER BY code;
+
+-- name: UpdateCurrencyEnabled :one
+UPDATE currencies
+SET enabled = $2
+WHERE code = $1
+RETURNING *;
@@ -1,9 +1,11 @@
 -- name: CreateEntry :one
 INSERT into entries (
     account_id,
-    amount
+    amount,
+    transfer_id,
+    kind
 ) VALUES (
-    $1, $2
+    $1, $2, $3, $4
 ) RETURNING *;
 
 -- name: GetEntry :one
@@ -15,4 +17,32 @@ SELECT * FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
-OFFSET $3;
\ No newline at end of file
+OFFSET $3;
+
+-- name: GetAccountBalanceAt :one
+SELECT (a.balance - COALESCE(SUM(e.amount), 0))::bigint AS balance
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id AND e.created_at >= sqlc.arg(at)
+WHERE a.id = sqlc.arg(account_id)
+GROUP BY a.id;
+
+-- name: ListStatementEntries :many
+SELECT * FROM entries
+WHERE
+    account_id = sqlc.arg(account_id) AND
+    created_at >= sqlc.arg(start_time) AND
+    created_at < sqlc.arg(end_time)
+ORDER BY id;
+
+-- name: ListTransferEntries :many
+SELECT * FROM entries
+WHERE transfer_id = sqlc.arg(transfer_id)::bigint
+ORDER BY id;
+
+-- name: ListEntryLegs :many
+SELECT * FROM entries
+WHERE transfer_id = (
+    SELECT e.transfer_id FROM entries e
+    WHERE e.id = $1
+)
+ORDER BY id;
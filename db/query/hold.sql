@@ -0,0 +1,46 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+    account_id,
+    to_account_id,
+    amount,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = $1;
+
+-- name: GetHoldForUpdate :one
+SELECT * FROM holds
+WHERE id = $1
+FOR NO KEY UPDATE;
+
+-- name: HoldExpired :one
+SELECT expires_at <= now() AS expired FROM holds
+WHERE id = $1;
+
+-- name: ListHolds :many
+SELECT * FROM holds
+WHERE account_id = sqlc.arg(account_id)
+    AND (sqlc.arg(status)::varchar = '' OR status::varchar = sqlc.arg(status))
+ORDER BY id DESC
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: FinishHold :one
+UPDATE holds
+SET status = sqlc.arg(status),
+    captured_amount = sqlc.arg(captured_amount),
+    transfer_id = sqlc.arg(transfer_id),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'active'
+RETURNING *;
+
+-- name: ClaimExpiredHolds :many
+SELECT * FROM holds
+WHERE status = 'active' AND expires_at <= now()
+ORDER BY expires_at
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
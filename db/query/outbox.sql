@@ -0,0 +1,40 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox (
+    event_type,
+    aggregate_type,
+    aggregate_id,
+    payload
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ClaimOutboxEvents :many
+UPDATE outbox
+SET next_attempt_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::float8)
+WHERE id IN (
+    SELECT id FROM outbox
+    WHERE published_at IS NULL AND next_attempt_at <= now()
+    ORDER BY id
+    LIMIT sqlc.arg(limit_count)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE outbox
+SET published_at = now(),
+    attempts = attempts + 1,
+    last_error = ''
+WHERE id = $1;
+
+-- name: MarkOutboxEventFailed :exec
+UPDATE outbox
+SET attempts = attempts + 1,
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = sqlc.arg(next_attempt_at)
+WHERE id = sqlc.arg(id);
+
+-- name: ListOutboxEvents :many
+SELECT * FROM outbox
+WHERE aggregate_type = $1 AND aggregate_id = $2
+ORDER BY id;
@@ -0,0 +1,21 @@
+-- name: ListAccountBalanceMismatches :many
+SELECT a.id AS account_id, a.balance, COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id;
+
+-- name: ListUnbalancedTransfers :many
+-- cross currency transfers are balanced per currency through the system accounts.
+SELECT
+    t.id AS transfer_id,
+    COALESCE(a.currency, '')::varchar AS currency,
+    COUNT(e.id) AS entries_count,
+    COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM transfers t
+LEFT JOIN entries e ON e.transfer_id = t.id
+LEFT JOIN accounts a ON a.id = e.account_id
+GROUP BY t.id, a.currency
+HAVING COUNT(e.id) = 0 OR COALESCE(SUM(e.amount), 0) <> 0
+ORDER BY t.id;
@@ -0,0 +1,39 @@
+-- name: CreateRevokedToken :exec
+INSERT INTO revoked_tokens (
+    id,
+    username,
+    expires_at
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (id) DO NOTHING;
+
+-- name: UpsertUserRevocation :exec
+INSERT INTO user_revocations (
+    username,
+    revoked_before,
+    expires_at
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (username) DO UPDATE
+SET revoked_before = EXCLUDED.revoked_before,
+    expires_at = EXCLUDED.expires_at;
+
+-- name: IsTokenRevoked :one
+SELECT (
+    EXISTS (
+        SELECT 1 FROM revoked_tokens
+        WHERE revoked_tokens.id = sqlc.arg(id)
+    ) OR EXISTS (
+        SELECT 1 FROM user_revocations
+        WHERE user_revocations.username = sqlc.arg(username)
+        AND user_revocations.revoked_before >= sqlc.arg(issued_at)
+    )
+)::boolean AS revoked;
+
+-- name: DeleteExpiredRevokedTokens :execrows
+DELETE FROM revoked_tokens
+WHERE expires_at < now();
+
+-- name: DeleteExpiredUserRevocations :execrows
+DELETE FROM user_revocations
+WHERE expires_at < now();
@@ -0,0 +1,72 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+    from_account_id,
+    to_account_id,
+    amount,
+    schedule,
+    start_at,
+    next_run_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1;
+
+-- name: ListScheduledTransfers :many
+SELECT * FROM scheduled_transfers
+WHERE from_account_id = $1
+ORDER BY id;
+
+-- name: UpdateScheduledTransfer :one
+UPDATE scheduled_transfers
+SET amount = sqlc.arg(amount),
+    schedule = sqlc.arg(schedule),
+    start_at = sqlc.arg(start_at),
+    next_run_at = sqlc.arg(next_run_at),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'active'
+RETURNING *;
+
+-- name: CancelScheduledTransfer :one
+UPDATE scheduled_transfers
+SET status = 'cancelled',
+    next_run_at = NULL,
+    updated_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING *;
+
+-- name: ClaimDueScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE status = 'active' AND next_run_at <= now()
+ORDER BY next_run_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: AdvanceScheduledTransfer :one
+UPDATE scheduled_transfers
+SET run_count = run_count + 1,
+    last_run_at = sqlc.arg(last_run_at),
+    next_run_at = sqlc.arg(next_run_at),
+    status = sqlc.arg(status),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateScheduledTransferRun :one
+INSERT INTO scheduled_transfer_runs (
+    scheduled_transfer_id,
+    scheduled_for,
+    status,
+    transfer_id,
+    error
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListScheduledTransferRuns :many
+SELECT * FROM scheduled_transfer_runs
+WHERE scheduled_transfer_id = $1
+ORDER BY id DESC
+LIMIT $2;
@@ -2,15 +2,33 @@
 INSERT INTO transfers (
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    to_amount,
+    exchange_rate,
+    reversal_of
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4, $5, $6
 ) RETURNING *;
 
 -- name: GetTransfer :one
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: AddTransferRefund :one
+UPDATE transfers
+SET refunded_amount = refunded_amount + sqlc.arg(amount),
+    status = CASE
+        WHEN refunded_amount + sqlc.arg(amount) = amount THEN 'reversed'::transfer_status
+        ELSE 'partially_reversed'::transfer_status
+    END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
 -- name: ListTransfers :many
 SELECT * FROM transfers
 WHERE
@@ -18,4 +36,19 @@ WHERE
     to_account_id = $2
 ORDER BY id
 LIMIT $3
-OFFSET $4;
\ No newline at end of file
+OFFSET $4;
+
+-- name: ListAccountTransfers :many
+SELECT * FROM transfers
+WHERE
+    (
+        (sqlc.arg(outgoing)::bool AND from_account_id = sqlc.arg(account_id)) OR
+        (sqlc.arg(incoming)::bool AND to_account_id = sqlc.arg(account_id))
+    ) AND
+    created_at >= sqlc.arg(start_time) AND
+    created_at < sqlc.arg(end_time) AND
+    amount >= sqlc.arg(min_amount) AND
+    amount <= sqlc.arg(max_amount) AND
+    id < sqlc.arg(cursor)
+ORDER BY id DESC
+LIMIT sqlc.arg(page_size);
@@ -0,0 +1,56 @@
+-- name: UpsertTransferLimit :one
+INSERT INTO transfer_limits (
+    currency,
+    account_id,
+    owner,
+    per_transaction,
+    daily,
+    monthly
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (currency, (COALESCE(account_id, 0)), (COALESCE(owner, ''))) DO UPDATE
+SET per_transaction = EXCLUDED.per_transaction,
+    daily = EXCLUDED.daily,
+    monthly = EXCLUDED.monthly,
+    updated_at = now()
+RETURNING *;
+
+-- name: ListTransferLimits :many
+SELECT * FROM transfer_limits
+WHERE sqlc.arg(currency)::varchar = '' OR currency = sqlc.arg(currency)
+ORDER BY currency, account_id NULLS FIRST, owner NULLS FIRST;
+
+-- name: DeleteTransferLimit :one
+DELETE FROM transfer_limits
+WHERE id = $1
+RETURNING *;
+
+-- name: GetAccountTransferLimit :one
+-- the limit of the account itself, or else the default of its currency
+SELECT * FROM transfer_limits
+WHERE owner IS NULL AND currency = sqlc.arg(currency) AND (account_id = sqlc.arg(account_id)::bigint OR account_id IS NULL)
+ORDER BY account_id NULLS LAST
+LIMIT 1;
+
+-- name: GetUserTransferLimit :one
+SELECT * FROM transfer_limits
+WHERE owner = sqlc.arg(owner)::varchar AND currency = sqlc.arg(currency);
+
+-- name: SumAccountTransfersSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint FROM transfers
+WHERE from_account_id = sqlc.arg(account_id) AND created_at >= sqlc.arg(since) AND reversal_of IS NULL;
+
+-- name: SumUserTransfersSince :one
+SELECT COALESCE(SUM(t.amount), 0)::bigint FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+WHERE a.owner = sqlc.arg(owner) AND a.currency = sqlc.arg(currency) AND t.created_at >= sqlc.arg(since) AND t.reversal_of IS NULL;
+
+-- name: SumUserHeldBalance :one
+SELECT COALESCE(SUM(held_balance), 0)::bigint FROM accounts
+WHERE owner = sqlc.arg(owner) AND currency = sqlc.arg(currency);
+
+-- name: LockUser :one
+SELECT username AS locked_username FROM users
+WHERE username = $1
+FOR NO KEY UPDATE;
@@ -0,0 +1,96 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+    account_id,
+    url,
+    event_type,
+    secret
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions
+WHERE id = $1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions
+WHERE account_id = $1 AND active
+ORDER BY id;
+
+-- name: DeactivateWebhookSubscription :one
+UPDATE webhook_subscriptions
+SET active = false
+WHERE id = $1
+RETURNING *;
+
+-- name: EnqueueWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (
+    subscription_id,
+    event_id,
+    event_type,
+    payload
+)
+SELECT id, sqlc.arg(event_id)::bigint, sqlc.arg(event_type)::varchar, sqlc.arg(payload)::jsonb
+FROM webhook_subscriptions
+WHERE account_id = ANY(sqlc.arg(account_ids)::bigint[]) AND event_type = sqlc.arg(event_type)::varchar AND active
+ON CONFLICT DO NOTHING;
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries d
+SET next_attempt_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::float8)
+FROM webhook_subscriptions s
+WHERE s.id = d.subscription_id AND d.id IN (
+    SELECT due.id FROM webhook_deliveries due
+    JOIN webhook_subscriptions sub ON sub.id = due.subscription_id
+    WHERE due.status = 'pending' AND due.next_attempt_at <= now() AND sub.active
+    ORDER BY due.id
+    LIMIT sqlc.arg(limit_count)
+    FOR UPDATE OF due SKIP LOCKED
+)
+RETURNING d.*, s.url, s.secret;
+
+-- name: UpdateWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = sqlc.arg(status),
+    attempts = attempts + 1,
+    last_status_code = sqlc.arg(last_status_code),
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    delivered_at = CASE WHEN sqlc.arg(status) = 'succeeded'::webhook_delivery_status THEN now() ELSE delivered_at END
+WHERE id = sqlc.arg(id);
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE
+    subscription_id = sqlc.arg(subscription_id) AND
+    (sqlc.arg(status)::varchar = '' OR status::varchar = sqlc.arg(status))
+ORDER BY id DESC
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: ReplayWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = 'pending',
+    attempts = 0,
+    next_attempt_at = now()
+WHERE id = $1 AND status = 'failed'
+RETURNING *;
+
+-- name: CreateWebhookDeliveryAttempt :one
+INSERT INTO webhook_delivery_attempts (
+    delivery_id,
+    status_code,
+    error,
+    duration_ms
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListWebhookDeliveryAttempts :many
+SELECT * FROM webhook_delivery_attempts
+WHERE delivery_id = $1
+ORDER BY id;
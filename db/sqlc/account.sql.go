@@ -5,13 +5,15 @@ package db
 
 import (
 	"context"
+
+	"github.com/lib/pq"
 )
 
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, closed_at, held_balance
 `
 
 type AddAccountBalanceParams struct {
@@ -28,6 +30,39 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.ClosedAt,
+		&i.HeldBalance,
+	)
+	return i, err
+}
+
+const addAccountHeldBalance = `-- name: AddAccountHeldBalance :one
+UPDATE accounts
+SET held_balance = held_balance + $1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, closed_at, held_balance
+`
+
+type AddAccountHeldBalanceParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddAccountHeldBalance(ctx context.Context, arg AddAccountHeldBalanceParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, addAccountHeldBalance, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.ClosedAt,
+		&i.HeldBalance,
 	)
 	return i, err
 }
@@ -39,7 +74,7 @@ INSERT into accounts (
     currency
 ) VALUES (
     $1, $2, $3
-) RETURNING id, owner, balance, currency, created_at
+) RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, closed_at, held_balance
 `
 
 type CreateAccountParams struct {
@@ -57,21 +92,43 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.ClosedAt,
+		&i.HeldBalance,
 	)
 	return i, err
 }
 
-const deleteAccount = `-- name: DeleteAccount :exec
-DELETE FROM accounts WHERE id = $1
+const createCashAccount = `-- name: CreateCashAccount :one
+INSERT INTO accounts (
+    owner,
+    balance,
+    currency
+) VALUES (
+    'banco-system', 0, $1
+) RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, closed_at, held_balance
 `
 
-func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteAccount, id)
-	return err
+func (q *Queries) CreateCashAccount(ctx context.Context, currency string) (Account, error) {
+	row := q.db.QueryRowContext(ctx, createCashAccount, currency)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.ClosedAt,
+		&i.HeldBalance,
+	)
+	return i, err
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, closed_at, held_balance FROM accounts
 WHERE id = $1
 `
 
@@ -84,12 +141,16 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.ClosedAt,
+		&i.HeldBalance,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, closed_at, held_balance FROM accounts
 WHERE id = $1
 FOR NO KEY UPDATE
 `
@@ -103,12 +164,38 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.ClosedAt,
+		&i.HeldBalance,
+	)
+	return i, err
+}
+
+const getCashAccount = `-- name: GetCashAccount :one
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, closed_at, held_balance FROM accounts
+WHERE owner = 'banco-system' AND currency = $1
+`
+
+func (q *Queries) GetCashAccount(ctx context.Context, currency string) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getCashAccount, currency)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.ClosedAt,
+		&i.HeldBalance,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, closed_at, held_balance FROM accounts
 WHERE owner = $1
 ORDER BY id
 LIMIT $2
@@ -136,6 +223,96 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Status,
+			&i.ClosedAt,
+			&i.HeldBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsByIDs = `-- name: ListAccountsByIDs :many
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, closed_at, held_balance FROM accounts
+WHERE id = ANY($1::bigint[])
+ORDER BY id
+`
+
+func (q *Queries) ListAccountsByIDs(ctx context.Context, ids []int64) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Status,
+			&i.ClosedAt,
+			&i.HeldBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllAccounts = `-- name: ListAllAccounts :many
+SELECT id, owner, balance, currency, created_at, overdraft_limit, status, closed_at, held_balance FROM accounts
+WHERE $1::varchar = '' OR owner = $1
+ORDER BY id
+LIMIT $3
+OFFSET $2
+`
+
+type ListAllAccountsParams struct {
+	Owner       string `json:"owner"`
+	OffsetCount int32  `json:"offsetCount"`
+	LimitCount  int32  `json:"limitCount"`
+}
+
+func (q *Queries) ListAllAccounts(ctx context.Context, arg ListAllAccountsParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listAllAccounts, arg.Owner, arg.OffsetCount, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.OverdraftLimit,
+			&i.Status,
+			&i.ClosedAt,
+			&i.HeldBalance,
 		); err != nil {
 			return nil, err
 		}
@@ -150,20 +327,50 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 	return items, nil
 }
 
-const updateAccount = `-- name: UpdateAccount :one
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
 UPDATE accounts
-SET balance = $2
+SET overdraft_limit = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, closed_at, held_balance
+`
+
+type UpdateAccountOverdraftLimitParams struct {
+	ID             int64 `json:"id"`
+	OverdraftLimit int64 `json:"overdraftLimit"`
+}
+
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountOverdraftLimit, arg.ID, arg.OverdraftLimit)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.ClosedAt,
+		&i.HeldBalance,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE accounts
+SET status = $1,
+    closed_at = CASE WHEN $1 = 'closed'::account_status THEN now() ELSE closed_at END
+WHERE id = $2 AND owner <> 'banco-system'
+RETURNING id, owner, balance, currency, created_at, overdraft_limit, status, closed_at, held_balance
 `
 
-type UpdateAccountParams struct {
-	ID      int64 `json:"id"`
-	Balance int64 `json:"balance"`
+type UpdateAccountStatusParams struct {
+	Status AccountStatus `json:"status"`
+	ID     int64         `json:"id"`
 }
 
-func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, updateAccount, arg.ID, arg.Balance)
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountStatus, arg.Status, arg.ID)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -171,6 +378,10 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.OverdraftLimit,
+		&i.Status,
+		&i.ClosedAt,
+		&i.HeldBalance,
 	)
 	return i, err
 }
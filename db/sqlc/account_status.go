@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrAccountClosed   = errors.New("account is closed")
+	ErrAccountNotEmpty = errors.New("account balance must be zero to close it")
+)
+
+type ChangeAccountStatusTxParams struct {
+	AccountID int64         `json:"accountID"`
+	Status    AccountStatus `json:"status"`
+}
+
+// ChangeAccountStatusTx moves an account to another status under a row lock. Closed is
+// final and only reachable with a zero balance, so no money is left behind and no
+// transfer can slip in between the check and the update.
+func (store *SQLStore) ChangeAccountStatusTx(ctx context.Context, args ChangeAccountStatusTxParams) (Account, error) {
+	var result Account
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, args.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if account.Status == AccountStatusClosed {
+			return fmt.Errorf("%w: %d", ErrAccountClosed, account.ID)
+		}
+		if args.Status == AccountStatusClosed && account.Balance != 0 {
+			return fmt.Errorf("%w, account %d holds %d", ErrAccountNotEmpty, account.ID, account.Balance)
+		}
+		if args.Status == AccountStatusClosed && account.HeldBalance != 0 {
+			return fmt.Errorf("%w, account %d has %d on hold", ErrAccountNotEmpty, account.ID, account.HeldBalance)
+		}
+
+		result, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID:     account.ID,
+			Status: args.Status,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditActionAccountStatus, "accounts", account.ID, account, result)
+	})
+	if err != nil {
+		return Account{}, err
+	}
+	return result, nil
+}
+
+// checkActive fails when money can't move in or out of one of the accounts.
+func checkActive(accounts ...Account) error {
+	for _, account := range accounts {
+		switch account.Status {
+		case AccountStatusFrozen:
+			return fmt.Errorf("%w: %d", ErrAccountFrozen, account.ID)
+		case AccountStatusClosed:
+			return fmt.Errorf("%w: %d", ErrAccountClosed, account.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeAccountStatusTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	frozen, err := store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusFrozen, frozen.Status)
+	require.False(t, frozen.ClosedAt.Valid)
+
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    AccountStatusClosed,
+	})
+	require.ErrorIs(t, err, ErrAccountNotEmpty)
+
+	_, err = store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  account.ID,
+		Amount:     -account.Balance,
+		ReasonCode: AdjustmentReasonWriteOff,
+		Note:       "closing",
+		Actor:      account.Owner,
+	})
+	require.NoError(t, err)
+
+	closed, err := store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    AccountStatusClosed,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountStatusClosed, closed.Status)
+	require.True(t, closed.ClosedAt.Valid)
+
+	// closed is final
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    AccountStatusActive,
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	// the history stays and the owner can open a new account in the same currency
+	entries, err := store.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: account.ID,
+		Limit:     5,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+
+	_, err = store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account.Owner,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+}
+
+func TestTransferTxClosedAccount(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+
+	user := createRandomUser(t)
+	account2, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Currency: util.USD,
+	})
+	require.NoError(t, err)
+
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: account2.ID,
+		Status:    AccountStatusClosed,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	_, err = store.DepositTx(context.Background(), CashTxParams{
+		AccountID: account2.ID,
+		Amount:    10,
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+}
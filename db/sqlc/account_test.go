@@ -14,8 +14,8 @@ func createRandomAccount(t *testing.T) Account {
 	user := createRandomUser(t)
 	arg := CreateAccountParams{
 		Owner:    user.Username,
-		Balance:  util.RandomMoney(),
-		Currency: util.RandomCurrency(),
+		Balance:  util.RandomInt(100, 1000),
+		Currency: util.USD,
 	}
 	account, err := testQueries.CreateAccount(context.Background(), arg)
 	require.NoError(t, err)
@@ -24,6 +24,7 @@ func createRandomAccount(t *testing.T) Account {
 	require.Equal(t, arg.Owner, account.Owner)
 	require.Equal(t, arg.Balance, account.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, AccountStatusActive, account.Status)
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
@@ -49,37 +50,6 @@ func TestGetAccount(t *testing.T) {
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
-func TestUpdateAccount(t *testing.T) {
-	account1 := createRandomAccount(t)
-
-	var args = UpdateAccountParams{
-		ID:      account1.ID,
-		Balance: util.RandomMoney(),
-	}
-	account2, err := testQueries.UpdateAccount(context.Background(), args)
-	if err != nil {
-		return
-	}
-
-	require.Equal(t, account1.ID, account2.ID)
-	require.Equal(t, account1.Owner, account2.Owner)
-	require.Equal(t, args.Balance, account2.Balance)
-	require.Equal(t, account1.Currency, account2.Currency)
-
-}
-
-func TestDeleteAccount(t *testing.T) {
-	account1 := createRandomAccount(t)
-	err := testQueries.DeleteAccount(context.Background(), account1.ID)
-	require.NoError(t, err)
-
-	account2, err := testQueries.GetAccount(context.Background(), account1.ID)
-
-	require.Error(t, err)
-	require.EqualError(t, err, sql.ErrNoRows.Error())
-	require.Empty(t, account2)
-}
-
 func TestListAccounts(t *testing.T) {
 
 	var lastAccount Account
@@ -103,3 +73,46 @@ func TestListAccounts(t *testing.T) {
 		require.Equal(t, lastAccount.Owner, account.Owner)
 	}
 }
+
+func TestListAllAccounts(t *testing.T) {
+	var lastAccount Account
+	for i := 0; i < 5; i++ {
+		lastAccount = createRandomAccount(t)
+	}
+
+	accounts, err := testQueries.ListAllAccounts(context.Background(), ListAllAccountsParams{
+		LimitCount:  5,
+		OffsetCount: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, accounts, 5)
+
+	accounts, err = testQueries.ListAllAccounts(context.Background(), ListAllAccountsParams{
+		Owner:       lastAccount.Owner,
+		LimitCount:  5,
+		OffsetCount: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, lastAccount.ID, accounts[0].ID)
+}
+
+func TestUpdateAccountStatus(t *testing.T) {
+	account1 := createRandomAccount(t)
+
+	account2, err := testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:     account1.ID,
+		Status: AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, account2.ID)
+	require.Equal(t, AccountStatusFrozen, account2.Status)
+
+	cashAccount, err := testQueries.GetCashAccount(context.Background(), util.USD)
+	require.NoError(t, err)
+	_, err = testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:     cashAccount.ID,
+		Status: AccountStatusFrozen,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
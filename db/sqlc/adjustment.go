@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrSystemAccount = errors.New("system accounts can't be adjusted")
+
+type AdjustBalanceTxParams struct {
+	AccountID  int64            `json:"accountID"`
+	Amount     int64            `json:"amount"`
+	ReasonCode AdjustmentReason `json:"reasonCode"`
+	Note       string           `json:"note"`
+	Actor      string           `json:"actor"`
+}
+
+type AdjustBalanceTxResult struct {
+	Adjustment BalanceAdjustment `json:"adjustment"`
+	Account    Account           `json:"account"`
+	Entry      Entry             `json:"entry"`
+}
+
+// AdjustBalanceTx changes an account balance by a signed amount, recording an adjustment
+// entry and an audit record of who did it and why in the same transaction. Closed
+// accounts and the system cash accounts can't be adjusted, the cash accounts only
+// mirror the money that came in and went out. Like a withdrawal, a debit fails with
+// ErrInsufficientFunds when the available balance doesn't cover it.
+func (store *SQLStore) AdjustBalanceTx(ctx context.Context, args AdjustBalanceTxParams) (AdjustBalanceTxResult, error) {
+	var result AdjustBalanceTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, args.AccountID)
+		if err != nil {
+			return err
+		}
+		// frozen accounts can still be corrected, closed ones must stay at zero
+		if account.Status == AccountStatusClosed {
+			return fmt.Errorf("%w: %d", ErrAccountClosed, account.ID)
+		}
+		if account.Owner == SystemOwner {
+			return fmt.Errorf("%w: %d", ErrSystemAccount, account.ID)
+		}
+		if args.Amount < 0 && account.AvailableBalance()+args.Amount < 0 {
+			return ErrInsufficientFunds
+		}
+
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: args.AccountID,
+			Amount:    args.Amount,
+			Kind:      EntryKindAdjustment,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			Amount: args.Amount,
+			ID:     args.AccountID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Adjustment, err = q.CreateBalanceAdjustment(ctx, CreateBalanceAdjustmentParams{
+			AccountID:  args.AccountID,
+			EntryID:    result.Entry.ID,
+			Amount:     args.Amount,
+			ReasonCode: args.ReasonCode,
+			Note:       args.Note,
+			Actor:      args.Actor,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditActionAdjustment, "accounts", account.ID, account, result.Account)
+	})
+	if err != nil {
+		return AdjustBalanceTxResult{}, err
+	}
+	return result, nil
+}
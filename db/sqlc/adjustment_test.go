@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdjustBalanceTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+	admin := createRandomUser(t)
+
+	args := AdjustBalanceTxParams{
+		AccountID:  account.ID,
+		Amount:     -25,
+		ReasonCode: AdjustmentReasonCorrection,
+		Note:       "duplicate deposit",
+		Actor:      admin.Username,
+	}
+
+	result, err := store.AdjustBalanceTx(context.Background(), args)
+	require.NoError(t, err)
+
+	require.Equal(t, account.Balance+args.Amount, result.Account.Balance)
+
+	require.Equal(t, account.ID, result.Entry.AccountID)
+	require.Equal(t, args.Amount, result.Entry.Amount)
+	require.Equal(t, EntryKindAdjustment, result.Entry.Kind)
+
+	require.Equal(t, result.Entry.ID, result.Adjustment.EntryID)
+	require.Equal(t, args.ReasonCode, result.Adjustment.ReasonCode)
+	require.Equal(t, args.Note, result.Adjustment.Note)
+	require.Equal(t, args.Actor, result.Adjustment.Actor)
+
+	adjustments, err := store.ListBalanceAdjustments(context.Background(), ListBalanceAdjustmentsParams{
+		AccountID: account.ID,
+		Limit:     5,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.Len(t, adjustments, 1)
+	require.Equal(t, result.Adjustment.ID, adjustments[0].ID)
+}
+
+func TestAdjustBalanceTxUnknownActor(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	_, err := store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  account.ID,
+		Amount:     10,
+		ReasonCode: AdjustmentReasonGoodwill,
+		Note:       "sorry",
+		Actor:      "nobody",
+	})
+	require.Error(t, err)
+
+	// the whole adjustment is rolled back
+	account2, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, account2.Balance)
+}
+
+func TestAdjustBalanceTxSystemAccount(t *testing.T) {
+	store := NewStore(testDB)
+	admin := createRandomUser(t)
+	cash, err := store.GetCashAccount(context.Background(), util.USD)
+	require.NoError(t, err)
+	require.Equal(t, SystemOwner, cash.Owner)
+
+	_, err = store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  cash.ID,
+		Amount:     1000,
+		ReasonCode: AdjustmentReasonCorrection,
+		Note:       "top up the vault",
+		Actor:      admin.Username,
+	})
+	require.ErrorIs(t, err, ErrSystemAccount)
+
+	unchanged, err := store.GetAccount(context.Background(), cash.ID)
+	require.NoError(t, err)
+	require.Equal(t, cash.Balance, unchanged.Balance)
+}
+
+func TestAdjustBalanceTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+	admin := createRandomUser(t)
+
+	account, err := store.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account.ID,
+		OverdraftLimit: 50,
+	})
+	require.NoError(t, err)
+
+	args := AdjustBalanceTxParams{
+		AccountID:  account.ID,
+		Amount:     -(account.Balance + account.OverdraftLimit + 1),
+		ReasonCode: AdjustmentReasonChargeback,
+		Note:       "disputed card payment",
+		Actor:      admin.Username,
+	}
+	_, err = store.AdjustBalanceTx(context.Background(), args)
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	unchanged, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, unchanged.Balance)
+
+	// the overdraft limit can be used up to the last unit
+	args.Amount++
+	result, err := store.AdjustBalanceTx(context.Background(), args)
+	require.NoError(t, err)
+	require.Equal(t, -account.OverdraftLimit, result.Account.Balance)
+}
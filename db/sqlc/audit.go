@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// AuditContextKey is where the request metadata for audit events is kept in a context,
+// it is a plain string because gin.Context only looks up string keys.
+const AuditContextKey = "audit_context"
+
+// actions of the audit events written by the store
+const (
+	AuditActionTransfer      = "transfer.create"
+	AuditActionReversal      = "transfer.reverse"
+	AuditActionDeposit       = "account.deposit"
+	AuditActionWithdrawal    = "account.withdrawal"
+	AuditActionAdjustment    = "account.adjust"
+	AuditActionAccountStatus = "account.status"
+	AuditActionHoldPlace     = "hold.place"
+	AuditActionHoldCapture   = "hold.capture"
+	AuditActionHoldRelease   = "hold.release"
+	AuditActionHoldExpire    = "hold.expire"
+)
+
+// AuditContext tells who is behind the changes made with a context.
+type AuditContext struct {
+	Actor     string
+	RequestID string
+	ClientIP  string
+	// Recorded is set once a transaction that wrote an audit event with this context
+	// commits, the api then leaves out its own event for the request.
+	Recorded bool
+	// staged tells that the open transaction wrote an audit event
+	staged bool
+}
+
+// WithAuditContext attaches audit metadata to a context that isn't a gin.Context,
+// e.g. for background jobs.
+func WithAuditContext(ctx context.Context, audit *AuditContext) context.Context {
+	return context.WithValue(ctx, AuditContextKey, audit)
+}
+
+func auditContextFrom(ctx context.Context) AuditContext {
+	if audit, ok := ctx.Value(AuditContextKey).(*AuditContext); ok && audit != nil {
+		return *audit
+	}
+	return AuditContext{}
+}
+
+// settleAudit marks the audit context of ctx recorded when the transaction that staged
+// an event committed, a rolled back one leaves it as it was.
+func settleAudit(ctx context.Context, committed bool) {
+	audit, ok := ctx.Value(AuditContextKey).(*AuditContext)
+	if !ok || audit == nil {
+		return
+	}
+	if committed && audit.staged {
+		audit.Recorded = true
+	}
+	audit.staged = false
+}
+
+// recordAudit writes an audit event with before and after snapshots of the resource in
+// the transaction of the change, so the trail can't miss a committed change.
+func recordAudit(ctx context.Context, q *Queries, action, resourceType string, resourceID int64, before, after interface{}) error {
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	audit := auditContextFrom(ctx)
+	_, err = q.CreateAuditEvent(ctx, CreateAuditEventParams{
+		Actor:        audit.Actor,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   strconv.FormatInt(resourceID, 10),
+		RequestID:    audit.RequestID,
+		ClientIp:     audit.ClientIP,
+		Before:       beforeJSON,
+		After:        afterJSON,
+	})
+	if err != nil {
+		return err
+	}
+	if audit, ok := ctx.Value(AuditContextKey).(*AuditContext); ok && audit != nil {
+		audit.staged = true
+	}
+	return nil
+}
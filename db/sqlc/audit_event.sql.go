@@ -0,0 +1,134 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: audit_event.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const createAuditEvent = `-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+    actor,
+    action,
+    resource_type,
+    resource_id,
+    request_id,
+    client_ip,
+    before,
+    after
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, actor, action, resource_type, resource_id, request_id, client_ip, before, after, created_at
+`
+
+type CreateAuditEventParams struct {
+	Actor        string          `json:"actor"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resourceType"`
+	ResourceID   string          `json:"resourceID"`
+	RequestID    string          `json:"requestID"`
+	ClientIp     string          `json:"clientIp"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+}
+
+func (q *Queries) CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error) {
+	row := q.db.QueryRowContext(ctx, createAuditEvent,
+		arg.Actor,
+		arg.Action,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.RequestID,
+		arg.ClientIp,
+		arg.Before,
+		arg.After,
+	)
+	var i AuditEvent
+	err := row.Scan(
+		&i.ID,
+		&i.Actor,
+		&i.Action,
+		&i.ResourceType,
+		&i.ResourceID,
+		&i.RequestID,
+		&i.ClientIp,
+		&i.Before,
+		&i.After,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAuditEvents = `-- name: ListAuditEvents :many
+SELECT id, actor, action, resource_type, resource_id, request_id, client_ip, before, after, created_at FROM audit_events
+WHERE
+    ($1::varchar = '' OR actor = $1) AND
+    ($2::varchar = '' OR action = $2) AND
+    ($3::varchar = '' OR resource_type = $3) AND
+    ($4::varchar = '' OR resource_id = $4) AND
+    ($5::varchar = '' OR request_id = $5) AND
+    created_at >= $6 AND
+    created_at < $7 AND
+    id < $8
+ORDER BY id DESC
+LIMIT $9
+`
+
+type ListAuditEventsParams struct {
+	Actor        string    `json:"actor"`
+	Action       string    `json:"action"`
+	ResourceType string    `json:"resourceType"`
+	ResourceID   string    `json:"resourceID"`
+	RequestID    string    `json:"requestID"`
+	StartTime    time.Time `json:"startTime"`
+	EndTime      time.Time `json:"endTime"`
+	Cursor       int64     `json:"cursor"`
+	PageSize     int32     `json:"pageSize"`
+}
+
+func (q *Queries) ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listAuditEvents,
+		arg.Actor,
+		arg.Action,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.RequestID,
+		arg.StartTime,
+		arg.EndTime,
+		arg.Cursor,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AuditEvent{}
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.Actor,
+			&i.Action,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.RequestID,
+			&i.ClientIp,
+			&i.Before,
+			&i.After,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
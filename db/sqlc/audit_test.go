@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxAuditEvent(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	audit := &AuditContext{
+		Actor:     account1.Owner,
+		RequestID: util.RandomString(12),
+		ClientIP:  "10.0.0.1",
+	}
+	ctx := WithAuditContext(context.Background(), audit)
+
+	result, err := store.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	require.True(t, audit.Recorded)
+
+	events, err := store.ListAuditEvents(context.Background(), ListAuditEventsParams{
+		RequestID: audit.RequestID,
+		EndTime:   time.Now().Add(time.Minute),
+		Cursor:    math.MaxInt64,
+		PageSize:  5,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	event := events[0]
+	require.Equal(t, audit.Actor, event.Actor)
+	require.Equal(t, AuditActionTransfer, event.Action)
+	require.Equal(t, "transfers", event.ResourceType)
+	require.Equal(t, strconv.FormatInt(result.Transfer.ID, 10), event.ResourceID)
+	require.Equal(t, audit.ClientIP, event.ClientIp)
+
+	var before []Account
+	require.NoError(t, json.Unmarshal(event.Before, &before))
+	require.Len(t, before, 2)
+	require.Equal(t, account1.Balance, before[0].Balance)
+	require.Equal(t, account2.Balance, before[1].Balance)
+
+	var after TransferTxResult
+	require.NoError(t, json.Unmarshal(event.After, &after))
+	require.Equal(t, account1.Balance-10, after.FromAccount.Balance)
+	require.Equal(t, account2.Balance+10, after.ToAccount.Balance)
+}
+
+func TestRefusedTransferTxNotRecorded(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	audit := &AuditContext{RequestID: util.RandomString(12)}
+	ctx := WithAuditContext(context.Background(), audit)
+
+	_, err := store.TransferTx(ctx, TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+	// the api writes the request event itself
+	require.False(t, audit.Recorded)
+}
+
+func TestChangeAccountStatusTxAuditEvent(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	_, err := store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{
+		AccountID: account.ID,
+		Status:    AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+
+	events, err := store.ListAuditEvents(context.Background(), ListAuditEventsParams{
+		Action:       AuditActionAccountStatus,
+		ResourceType: "accounts",
+		ResourceID:   strconv.FormatInt(account.ID, 10),
+		EndTime:      time.Now().Add(time.Minute),
+		Cursor:       math.MaxInt64,
+		PageSize:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	// no audit context, e.g. a background job
+	require.Empty(t, events[0].Actor)
+
+	var before, after Account
+	require.NoError(t, json.Unmarshal(events[0].Before, &before))
+	require.NoError(t, json.Unmarshal(events[0].After, &after))
+	require.Equal(t, AccountStatusActive, before.Status)
+	require.Equal(t, AccountStatusFrozen, after.Status)
+}
@@ -0,0 +1,99 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: balance_adjustment.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createBalanceAdjustment = `-- name: CreateBalanceAdjustment :one
+INSERT INTO balance_adjustments (
+    account_id,
+    entry_id,
+    amount,
+    reason_code,
+    note,
+    actor
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, account_id, entry_id, amount, reason_code, note, actor, created_at
+`
+
+type CreateBalanceAdjustmentParams struct {
+	AccountID  int64            `json:"accountID"`
+	EntryID    int64            `json:"entryID"`
+	Amount     int64            `json:"amount"`
+	ReasonCode AdjustmentReason `json:"reasonCode"`
+	Note       string           `json:"note"`
+	Actor      string           `json:"actor"`
+}
+
+func (q *Queries) CreateBalanceAdjustment(ctx context.Context, arg CreateBalanceAdjustmentParams) (BalanceAdjustment, error) {
+	row := q.db.QueryRowContext(ctx, createBalanceAdjustment,
+		arg.AccountID,
+		arg.EntryID,
+		arg.Amount,
+		arg.ReasonCode,
+		arg.Note,
+		arg.Actor,
+	)
+	var i BalanceAdjustment
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.EntryID,
+		&i.Amount,
+		&i.ReasonCode,
+		&i.Note,
+		&i.Actor,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listBalanceAdjustments = `-- name: ListBalanceAdjustments :many
+SELECT id, account_id, entry_id, amount, reason_code, note, actor, created_at FROM balance_adjustments
+WHERE account_id = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3
+`
+
+type ListBalanceAdjustmentsParams struct {
+	AccountID int64 `json:"accountID"`
+	Limit     int32 `json:"limit"`
+	Offset    int32 `json:"offset"`
+}
+
+func (q *Queries) ListBalanceAdjustments(ctx context.Context, arg ListBalanceAdjustmentsParams) ([]BalanceAdjustment, error) {
+	rows, err := q.db.QueryContext(ctx, listBalanceAdjustments, arg.AccountID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []BalanceAdjustment{}
+	for rows.Next() {
+		var i BalanceAdjustment
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.EntryID,
+			&i.Amount,
+			&i.ReasonCode,
+			&i.Note,
+			&i.Actor,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
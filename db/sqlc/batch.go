@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type BatchTransferLeg struct {
+	ToAccountID int64 `json:"toAccountID"`
+	Amount      int64 `json:"amount"`
+}
+
+type BatchTransferTxParams struct {
+	FromAccountID int64              `json:"fromAccountID"`
+	Legs          []BatchTransferLeg `json:"legs"`
+	// Atomic makes the whole batch fail with the first leg that fails, otherwise each
+	// failed leg is rolled back on its own and the others go through
+	Atomic bool `json:"atomic"`
+}
+
+// BatchTransferItem is the outcome of one leg, in the order of the legs.
+type BatchTransferItem struct {
+	Transfer *TransferTxResult `json:"transfer,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+type BatchTransferTxResult struct {
+	FromAccount Account             `json:"fromAccount"`
+	Items       []BatchTransferItem `json:"items"`
+	Succeeded   int                 `json:"succeeded"`
+	Failed      int                 `json:"failed"`
+}
+
+// BatchLegError tells which leg of an atomic batch made it fail.
+type BatchLegError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchLegError) Error() string {
+	return fmt.Sprintf("leg %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchLegError) Unwrap() error {
+	return e.Err
+}
+
+// BatchTransferTx sends money from one account to many of the same currency in a
+// single transaction. The source and every destination are locked once up front, in
+// id order, and each leg is a normal transfer, limits included. An atomic batch fails
+// as a whole with a *BatchLegError, otherwise a leg the accounts refuse (see
+// legRejected) is rolled back to its savepoint and recorded in its item. Any other
+// error, from the database or a cancelled context, still aborts the whole batch.
+func (store *SQLStore) BatchTransferTx(ctx context.Context, args BatchTransferTxParams) (BatchTransferTxResult, error) {
+	var result BatchTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		ids := make([]int64, 0, len(args.Legs)+1)
+		ids = append(ids, args.FromAccountID)
+		for _, leg := range args.Legs {
+			ids = append(ids, leg.ToAccountID)
+		}
+		locked, err := lockAccounts(ctx, q, ids...)
+		if err != nil {
+			return err
+		}
+
+		// the account locks are taken before any savepoint, rolling a leg back keeps them
+		result.Items = make([]BatchTransferItem, len(args.Legs))
+		for i, leg := range args.Legs {
+			if !args.Atomic {
+				if _, err := q.db.ExecContext(ctx, "SAVEPOINT batch_leg"); err != nil {
+					return err
+				}
+			}
+
+			transfer, legErr := batchLeg(ctx, q, args.FromAccountID, leg, locked)
+			if legErr != nil {
+				if args.Atomic || !legRejected(legErr) {
+					return &BatchLegError{Index: i, Err: legErr}
+				}
+				if _, err := q.db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_leg"); err != nil {
+					return err
+				}
+				result.Items[i].Error = legErr.Error()
+				result.Failed++
+				continue
+			}
+			if !args.Atomic {
+				if _, err := q.db.ExecContext(ctx, "RELEASE SAVEPOINT batch_leg"); err != nil {
+					return err
+				}
+			}
+
+			// later legs check their funds against the balances this one left
+			locked[transfer.FromAccount.ID] = transfer.FromAccount
+			locked[transfer.ToAccount.ID] = transfer.ToAccount
+			result.Items[i].Transfer = &transfer
+			result.Succeeded++
+		}
+
+		result.FromAccount = locked[args.FromAccountID]
+		return nil
+	})
+	if err != nil {
+		return BatchTransferTxResult{}, err
+	}
+	return result, nil
+}
+
+// legRejected tells whether a leg failed for the accounts or its currency, so that
+// a per item batch can go on without it.
+func legRejected(err error) bool {
+	return IsRejected(err) || errors.Is(err, ErrExchangeRateRequired)
+}
+
+// batchLeg makes the transfer of one leg, batches stay within one currency.
+func batchLeg(ctx context.Context, q *Queries, fromAccountID int64, leg BatchTransferLeg, locked map[int64]Account) (TransferTxResult, error) {
+	if locked[leg.ToAccountID].Currency != locked[fromAccountID].Currency {
+		return TransferTxResult{}, ErrExchangeRateRequired
+	}
+	return transferLocked(ctx, q, TransferTxParams{
+		FromAccountID: fromAccountID,
+		ToAccountID:   leg.ToAccountID,
+		Amount:        leg.Amount,
+		ToAmount:      leg.Amount,
+		ExchangeRate:  "1",
+	}, locked, Account{}, Account{}, true)
+}
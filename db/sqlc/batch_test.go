@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	from := createRandomAccount(t)
+	to1 := createRandomAccount(t)
+	to2 := createRandomAccount(t)
+
+	result, err := store.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		FromAccountID: from.ID,
+		Legs:          []BatchTransferLeg{{ToAccountID: to1.ID, Amount: 10}, {ToAccountID: to2.ID, Amount: 20}, {ToAccountID: to1.ID, Amount: 5}},
+		Atomic:        true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Succeeded)
+	require.Zero(t, result.Failed)
+	require.Len(t, result.Items, 3)
+	require.Equal(t, from.Balance-35, result.FromAccount.Balance)
+
+	for _, item := range result.Items {
+		require.Empty(t, item.Error)
+		require.NotNil(t, item.Transfer)
+		require.Equal(t, from.ID, item.Transfer.Transfer.FromAccountID)
+	}
+	require.Equal(t, to1.Balance+15, result.Items[2].Transfer.ToAccount.Balance)
+
+	updated, err := store.GetAccount(context.Background(), to2.ID)
+	require.NoError(t, err)
+	require.Equal(t, to2.Balance+20, updated.Balance)
+}
+
+func TestAtomicBatchTransferTxFails(t *testing.T) {
+	store := NewStore(testDB)
+	from := createRandomAccount(t)
+	to1 := createRandomAccount(t)
+	to2 := createRandomAccount(t)
+
+	_, err := store.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		FromAccountID: from.ID,
+		Legs:          []BatchTransferLeg{{ToAccountID: to1.ID, Amount: 10}, {ToAccountID: to2.ID, Amount: from.Balance}},
+		Atomic:        true,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+	var legErr *BatchLegError
+	require.True(t, errors.As(err, &legErr))
+	require.Equal(t, 1, legErr.Index)
+
+	// the first leg was rolled back with the rest
+	updated, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance, updated.Balance)
+
+	updated, err = store.GetAccount(context.Background(), to1.ID)
+	require.NoError(t, err)
+	require.Equal(t, to1.Balance, updated.Balance)
+}
+
+func TestPerItemBatchTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	from := createRandomAccount(t)
+	to1 := createRandomAccount(t)
+	to2 := createRandomAccount(t)
+
+	result, err := store.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		FromAccountID: from.ID,
+		Legs: []BatchTransferLeg{
+			{ToAccountID: to1.ID, Amount: 10},
+			{ToAccountID: to2.ID, Amount: from.Balance},
+			{ToAccountID: to2.ID, Amount: 20},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, result.Succeeded)
+	require.Equal(t, 1, result.Failed)
+
+	require.NotNil(t, result.Items[0].Transfer)
+	require.Nil(t, result.Items[1].Transfer)
+	require.Contains(t, result.Items[1].Error, ErrInsufficientFunds.Error())
+	require.NotNil(t, result.Items[2].Transfer)
+	require.Equal(t, from.Balance-30, result.FromAccount.Balance)
+
+	updated, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance-30, updated.Balance)
+
+	entries, err := store.ListEntries(context.Background(), ListEntriesParams{AccountID: to2.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestPerItemBatchTransferTxDatabaseError(t *testing.T) {
+	store := NewStore(testDB)
+	from := createRandomAccount(t)
+	to := createRandomAccount(t)
+	full := createRandomAccount(t)
+
+	// any credit to this account overflows its balance column
+	_, err := testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		Amount: math.MaxInt64 - full.Balance,
+		ID:     full.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = store.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		FromAccountID: from.ID,
+		Legs: []BatchTransferLeg{
+			{ToAccountID: to.ID, Amount: 10},
+			{ToAccountID: full.ID, Amount: 10},
+		},
+	})
+	var legErr *BatchLegError
+	require.ErrorAs(t, err, &legErr)
+	require.Equal(t, 1, legErr.Index)
+	require.False(t, IsRejected(err))
+
+	// the database error is not an item failure, the leg that went through is undone too
+	updated, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance, updated.Balance)
+
+	entries, err := store.ListEntries(context.Background(), ListEntriesParams{AccountID: to.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// SystemOwner owns the cash account of every currency, the other side of deposits,
+// withdrawals and cross currency transfers.
+const SystemOwner = "banco-system"
+
+var ErrNoCashAccount = errors.New("no cash account for currency")
+
+type CashTxParams struct {
+	AccountID int64 `json:"accountID"`
+	Amount    int64 `json:"amount"`
+}
+
+type CashTxResult struct {
+	Account   Account `json:"account"`
+	Entry     Entry   `json:"entry"`
+	CashEntry Entry   `json:"cashEntry"`
+}
+
+// DepositTx credits an account and debits the system cash account of the same currency,
+// so the entries of a deposit still sum to zero.
+func (store *SQLStore) DepositTx(ctx context.Context, args CashTxParams) (CashTxResult, error) {
+	return store.cashTx(ctx, args.AccountID, args.Amount, EntryKindDeposit)
+}
+
+// WithdrawTx debits an account and credits the system cash account of the same currency.
+// It fails with ErrInsufficientFunds when the available balance doesn't cover the amount.
+func (store *SQLStore) WithdrawTx(ctx context.Context, args CashTxParams) (CashTxResult, error) {
+	return store.cashTx(ctx, args.AccountID, -args.Amount, EntryKindWithdrawal)
+}
+
+func (store *SQLStore) cashTx(ctx context.Context, accountID, amount int64, kind EntryKind) (CashTxResult, error) {
+	var result CashTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, accountID)
+		if err != nil {
+			return err
+		}
+
+		cashAccount, err := systemCashAccount(ctx, q, account.Currency)
+		if err != nil {
+			return err
+		}
+
+		locked, err := lockAccounts(ctx, q, account.ID, cashAccount.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkActive(locked[account.ID]); err != nil {
+			return err
+		}
+		if amount < 0 && locked[account.ID].AvailableBalance()+amount < 0 {
+			return ErrInsufficientFunds
+		}
+
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: account.ID,
+			Amount:    amount,
+			Kind:      kind,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.CashEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: cashAccount.ID,
+			Amount:    -amount,
+			Kind:      kind,
+		})
+		if err != nil {
+			return err
+		}
+
+		if account.ID < cashAccount.ID {
+			result.Account, _, err = addMoney(ctx, q, account.ID, amount, cashAccount.ID, -amount)
+		} else {
+			_, result.Account, err = addMoney(ctx, q, cashAccount.ID, -amount, account.ID, amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		action := AuditActionDeposit
+		if kind == EntryKindWithdrawal {
+			action = AuditActionWithdrawal
+		}
+		return recordAudit(ctx, q, action, "accounts", account.ID, locked[account.ID], result.Account)
+	})
+	if err != nil {
+		return CashTxResult{}, err
+	}
+	return result, nil
+}
+
+func systemCashAccount(ctx context.Context, q *Queries, currency string) (Account, error) {
+	account, err := q.GetCashAccount(ctx, currency)
+	if err == sql.ErrNoRows {
+		return account, fmt.Errorf("%w %s", ErrNoCashAccount, currency)
+	}
+	return account, err
+}
+
+// lockAccounts locks the given accounts in ascending id order, the same order
+// addMoney updates them in, so concurrent transactions can't deadlock.
+func lockAccounts(ctx context.Context, q *Queries, ids ...int64) (map[int64]Account, error) {
+	sorted := append([]int64(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	accounts := make(map[int64]Account, len(sorted))
+	for _, id := range sorted {
+		account, err := q.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		accounts[id] = account
+	}
+	return accounts, nil
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomAccountWithCurrency(t *testing.T, currency string) Account {
+	user := createRandomUser(t)
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  0,
+		Currency: currency,
+	})
+	require.NoError(t, err)
+	return account
+}
+
+func TestDepositAndWithdrawTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccountWithCurrency(t, util.USD)
+
+	cashAccount, err := store.GetCashAccount(context.Background(), util.USD)
+	require.NoError(t, err)
+
+	deposit, err := store.DepositTx(context.Background(), CashTxParams{AccountID: account.ID, Amount: 100})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), deposit.Account.Balance)
+	require.Equal(t, int64(100), deposit.Entry.Amount)
+	require.Equal(t, EntryKindDeposit, deposit.Entry.Kind)
+	require.Equal(t, cashAccount.ID, deposit.CashEntry.AccountID)
+	require.Equal(t, int64(-100), deposit.CashEntry.Amount)
+
+	withdrawal, err := store.WithdrawTx(context.Background(), CashTxParams{AccountID: account.ID, Amount: 30})
+	require.NoError(t, err)
+	require.Equal(t, int64(70), withdrawal.Account.Balance)
+	require.Equal(t, int64(-30), withdrawal.Entry.Amount)
+	require.Equal(t, EntryKindWithdrawal, withdrawal.Entry.Kind)
+	require.Equal(t, int64(30), withdrawal.CashEntry.Amount)
+
+	_, err = store.WithdrawTx(context.Background(), CashTxParams{AccountID: account.ID, Amount: 71})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	updatedAccount, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(70), updatedAccount.Balance)
+}
+
+func TestWithdrawTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccountWithCurrency(t, util.EUR)
+
+	_, err := store.DepositTx(context.Background(), CashTxParams{AccountID: account.ID, Amount: 50})
+	require.NoError(t, err)
+
+	n := 10
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.WithdrawTx(context.Background(), CashTxParams{AccountID: account.ID, Amount: 10})
+			errs <- err
+		}()
+	}
+
+	var failed int
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			require.ErrorIs(t, err, ErrInsufficientFunds)
+			failed++
+		}
+	}
+	require.Equal(t, 5, failed)
+
+	updatedAccount, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Zero(t, updatedAccount.Balance)
+}
+
+func TestDepositTxNoCashAccount(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccountWithCurrency(t, "RS")
+
+	_, err := store.DepositTx(context.Background(), CashTxParams{AccountID: account.ID, Amount: 10})
+	require.ErrorIs(t, err, ErrNoCashAccount)
+}
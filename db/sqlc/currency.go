@@ -0,0 +1,23 @@
+package db
+
+import "context"
+
+// CreateCurrencyTx adds a currency together with its banco-system cash account, the
+// other side of deposits, withdrawals and conversions in that currency.
+func (store *SQLStore) CreateCurrencyTx(ctx context.Context, args CreateCurrencyParams) (Currency, error) {
+	var result Currency
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreateCurrency(ctx, args)
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateCashAccount(ctx, result.Code)
+		return err
+	})
+	if err != nil {
+		return Currency{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,108 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: currency.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createCurrency = `-- name: CreateCurrency :one
+INSERT INTO currencies (
+    code,
+    exponent
+) VALUES (
+    $1, $2
+) RETURNING code, exponent, enabled, created_at
+`
+
+type CreateCurrencyParams struct {
+	Code     string `json:"code"`
+	Exponent int32  `json:"exponent"`
+}
+
+func (q *Queries) CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, createCurrency, arg.Code, arg.Exponent)
+	var i Currency
+	err := row.Scan(
+		&i.Code,
+		&i.Exponent,
+		&i.Enabled,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getCurrency = `-- name: GetCurrency :one
+SELECT code, exponent, enabled, created_at FROM currencies
+WHERE code = $1 LIMIT 1
+`
+
+func (q *Queries) GetCurrency(ctx context.Context, code string) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, getCurrency, code)
+	var i Currency
+	err := row.Scan(
+		&i.Code,
+		&i.Exponent,
+		&i.Enabled,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listCurrencies = `-- name: ListCurrencies :many
+SELECT code, exponent, enabled, created_at FROM currencies
+ORDER BY code
+`
+
+func (q *Queries) ListCurrencies(ctx context.Context) ([]Currency, error) {
+	rows, err := q.db.QueryContext(ctx, listCurrencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Currency{}
+	for rows.Next() {
+		var i Currency
+		if err := rows.Scan(
+			&i.Code,
+			&i.Exponent,
+			&i.Enabled,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateCurrencyEnabled = `-- name: UpdateCurrencyEnabled :one
+UPDATE currencies
+SET enabled = $2
+WHERE code = $1
+RETURNING code, exponent, enabled, created_at
+`
+
+type UpdateCurrencyEnabledParams struct {
+	Code    string `json:"code"`
+	Enabled bool   `json:"enabled"`
+}
+
+func (q *Queries) UpdateCurrencyEnabled(ctx context.Context, arg UpdateCurrencyEnabledParams) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, updateCurrencyEnabled, arg.Code, arg.Enabled)
+	var i Currency
+	err := row.Scan(
+		&i.Code,
+		&i.Exponent,
+		&i.Enabled,
+		&i.CreatedAt,
+	)
+	return i, err
+}
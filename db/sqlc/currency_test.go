@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAndDisableCurrency(t *testing.T) {
+	code := util.RandomString(3)
+
+	currency, err := testQueries.CreateCurrency(context.Background(), CreateCurrencyParams{
+		Code:     code,
+		Exponent: 0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, code, currency.Code)
+	require.True(t, currency.Enabled)
+
+	currency, err = testQueries.UpdateCurrencyEnabled(context.Background(), UpdateCurrencyEnabledParams{
+		Code:    code,
+		Enabled: false,
+	})
+	require.NoError(t, err)
+	require.False(t, currency.Enabled)
+
+	currencies, err := testQueries.ListCurrencies(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, currencies, currency)
+}
+
+func TestAccountCurrencyMustBeRegistered(t *testing.T) {
+	user := createRandomUser(t)
+	_, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Currency: "RS",
+	})
+	require.Error(t, err)
+}
+
+func TestCreateCurrencyTx(t *testing.T) {
+	store := NewStore(testDB)
+	code := util.RandomString(3)
+
+	currency, err := store.CreateCurrencyTx(context.Background(), CreateCurrencyParams{
+		Code:     code,
+		Exponent: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, code, currency.Code)
+
+	cash, err := store.GetCashAccount(context.Background(), code)
+	require.NoError(t, err)
+	require.Zero(t, cash.Balance)
+
+	// deposits in the new currency have a cash account to come from
+	user := createRandomUser(t)
+	account, err := store.CreateAccount(context.Background(), CreateAccountParams{Owner: user.Username, Currency: code})
+	require.NoError(t, err)
+	result, err := store.DepositTx(context.Background(), CashTxParams{AccountID: account.ID, Amount: 100})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), result.Account.Balance)
+
+	_, err = store.CreateCurrencyTx(context.Background(), CreateCurrencyParams{Code: code, Exponent: 2})
+	require.Error(t, err)
+}
@@ -5,36 +5,69 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
 INSERT into entries (
     account_id,
-    amount
+    amount,
+    transfer_id,
+    kind
 ) VALUES (
-    $1, $2
-) RETURNING id, account_id, amount, created_at
+    $1, $2, $3, $4
+) RETURNING id, account_id, amount, created_at, transfer_id, kind
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"accountID"`
-	Amount    int64 `json:"amount"`
+	AccountID  int64         `json:"accountID"`
+	Amount     int64         `json:"amount"`
+	TransferID sql.NullInt64 `json:"transferID"`
+	Kind       EntryKind     `json:"kind"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.TransferID,
+		arg.Kind,
+	)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.Kind,
 	)
 	return i, err
 }
 
+const getAccountBalanceAt = `-- name: GetAccountBalanceAt :one
+SELECT (a.balance - COALESCE(SUM(e.amount), 0))::bigint AS balance
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id AND e.created_at >= $1
+WHERE a.id = $2
+GROUP BY a.id
+`
+
+type GetAccountBalanceAtParams struct {
+	At        time.Time `json:"at"`
+	AccountID int64     `json:"accountID"`
+}
+
+func (q *Queries) GetAccountBalanceAt(ctx context.Context, arg GetAccountBalanceAtParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getAccountBalanceAt, arg.At, arg.AccountID)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
+
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id, kind FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -46,12 +79,14 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.Kind,
 	)
 	return i, err
 }
 
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id, kind FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
@@ -78,6 +113,128 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
+			&i.Kind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntryLegs = `-- name: ListEntryLegs :many
+SELECT id, account_id, amount, created_at, transfer_id, kind FROM entries
+WHERE transfer_id = (
+    SELECT e.transfer_id FROM entries e
+    WHERE e.id = $1
+)
+ORDER BY id
+`
+
+func (q *Queries) ListEntryLegs(ctx context.Context, id int64) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listEntryLegs, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.Kind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStatementEntries = `-- name: ListStatementEntries :many
+SELECT id, account_id, amount, created_at, transfer_id, kind FROM entries
+WHERE
+    account_id = $1 AND
+    created_at >= $2 AND
+    created_at < $3
+ORDER BY id
+`
+
+type ListStatementEntriesParams struct {
+	AccountID int64     `json:"accountID"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+}
+
+func (q *Queries) ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listStatementEntries, arg.AccountID, arg.StartTime, arg.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.Kind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransferEntries = `-- name: ListTransferEntries :many
+SELECT id, account_id, amount, created_at, transfer_id, kind FROM entries
+WHERE transfer_id = $1::bigint
+ORDER BY id
+`
+
+func (q *Queries) ListTransferEntries(ctx context.Context, transferID int64) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listTransferEntries, transferID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.Kind,
 		); err != nil {
 			return nil, err
 		}
@@ -13,6 +13,7 @@ func createRandomEntry(t *testing.T, account Account) Entry {
 	args := CreateEntryParams{
 		AccountID: account.ID,
 		Amount:    util.RandomMoney(),
+		Kind:      EntryKindAdjustment,
 	}
 	entry, err := testQueries.CreateEntry(context.Background(), args)
 
@@ -21,6 +22,8 @@ func createRandomEntry(t *testing.T, account Account) Entry {
 
 	require.Equal(t, args.AccountID, entry.AccountID)
 	require.Equal(t, args.Amount, entry.Amount)
+	require.Equal(t, args.Kind, entry.Kind)
+	require.False(t, entry.TransferID.Valid)
 
 	require.NotZero(t, entry.ID)
 	require.NotZero(t, entry.CreatedAt)
@@ -68,3 +71,59 @@ func TestListEntry(t *testing.T) {
 	}
 
 }
+
+func TestListStatementEntries(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	entries, err := testQueries.ListStatementEntries(context.Background(), ListStatementEntriesParams{
+		AccountID: account1.ID,
+		StartTime: time.Now().Add(-time.Minute),
+		EndTime:   time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, result.FromEntry.ID, entries[0].ID)
+	require.Equal(t, result.Transfer.ID, entries[0].TransferID.Int64)
+
+	opening, err := testQueries.GetAccountBalanceAt(context.Background(), GetAccountBalanceAtParams{
+		AccountID: account1.ID,
+		At:        result.FromEntry.CreatedAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, opening)
+}
+
+func TestListEntryLegs(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	for _, entry := range []Entry{result.FromEntry, result.ToEntry} {
+		legs, err := testQueries.ListEntryLegs(context.Background(), entry.ID)
+		require.NoError(t, err)
+		require.Len(t, legs, 2)
+		require.Equal(t, result.FromEntry.ID, legs[0].ID)
+		require.Equal(t, result.ToEntry.ID, legs[1].ID)
+		require.Zero(t, legs[0].Amount+legs[1].Amount)
+	}
+
+	legs, err := testQueries.ListTransferEntries(context.Background(), result.Transfer.ID)
+	require.NoError(t, err)
+	require.Len(t, legs, 2)
+}
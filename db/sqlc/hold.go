@@ -0,0 +1,246 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrHoldNotActive        = errors.New("hold is no longer active")
+	ErrHoldExpired          = errors.New("hold has expired")
+	ErrCaptureExceedsHold   = errors.New("capture exceeds the held amount")
+	ErrHoldCurrencyMismatch = errors.New("hold must be captured into an account of the same currency")
+)
+
+type PlaceHoldParams struct {
+	AccountID   int64     `json:"accountID"`
+	ToAccountID int64     `json:"toAccountID"`
+	Amount      int64     `json:"amount"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+type HoldResult struct {
+	Hold    Hold    `json:"hold"`
+	Account Account `json:"account"`
+}
+
+// PlaceHold reserves an amount of the account for a later capture into ToAccountID.
+// The held amount stays on the ledger balance but is taken off the available balance,
+// so transfers and withdrawals can't spend it meanwhile. The transfer limits are checked
+// here, a hold counts as sent until it ends, and the capture doesn't check them again.
+func (store *SQLStore) PlaceHold(ctx context.Context, args PlaceHoldParams) (HoldResult, error) {
+	var result HoldResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		locked, err := lockAccounts(ctx, q, args.AccountID, args.ToAccountID)
+		if err != nil {
+			return err
+		}
+		account, toAccount := locked[args.AccountID], locked[args.ToAccountID]
+
+		if account.Currency != toAccount.Currency {
+			return ErrHoldCurrencyMismatch
+		}
+		if err := checkActive(account, toAccount); err != nil {
+			return err
+		}
+		if err := checkTransferLimits(ctx, q, account, args.Amount, time.Now().UTC()); err != nil {
+			return err
+		}
+		if account.AvailableBalance() < args.Amount {
+			return ErrInsufficientFunds
+		}
+
+		result.Account, err = q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+			Amount: args.Amount,
+			ID:     account.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.CreateHold(ctx, CreateHoldParams(args))
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditActionHoldPlace, "holds", result.Hold.ID, account, result)
+	})
+	if err != nil {
+		return HoldResult{}, err
+	}
+	return result, nil
+}
+
+type CaptureHoldParams struct {
+	HoldID int64 `json:"holdID"`
+	// Amount is how much of the hold to move, zero means all of it. What isn't captured
+	// goes back to the available balance.
+	Amount int64 `json:"amount"`
+}
+
+type CaptureHoldResult struct {
+	Hold     Hold             `json:"hold"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// CaptureHold ends an active hold by transferring all or part of it to the account it
+// was placed for. The limits were checked when the hold was placed, the transfer is
+// exempt from them. Expiry is judged by the database clock, like the sweeper does.
+func (store *SQLStore) CaptureHold(ctx context.Context, args CaptureHoldParams) (CaptureHoldResult, error) {
+	var result CaptureHoldResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := q.GetHoldForUpdate(ctx, args.HoldID)
+		if err != nil {
+			return err
+		}
+		if hold.Status != HoldStatusActive {
+			return fmt.Errorf("%w: %d is %s", ErrHoldNotActive, hold.ID, hold.Status)
+		}
+		expired, err := q.HoldExpired(ctx, hold.ID)
+		if err != nil {
+			return err
+		}
+		if expired {
+			return fmt.Errorf("%w: %d", ErrHoldExpired, hold.ID)
+		}
+
+		amount := args.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+		if amount < 0 || amount > hold.Amount {
+			return fmt.Errorf("%w: %d held", ErrCaptureExceedsHold, hold.Amount)
+		}
+
+		// both accounts are locked in id order, the same order every transfer takes them in
+		locked, err := lockAccounts(ctx, q, hold.AccountID, hold.ToAccountID)
+		if err != nil {
+			return err
+		}
+		locked[hold.AccountID], err = q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+			Amount: -hold.Amount,
+			ID:     hold.AccountID,
+		})
+		if err != nil {
+			return err
+		}
+
+		// holds are placed within one currency
+		result.Transfer, err = transferLocked(ctx, q, TransferTxParams{
+			FromAccountID: hold.AccountID,
+			ToAccountID:   hold.ToAccountID,
+			Amount:        amount,
+			ToAmount:      amount,
+			ExchangeRate:  "1",
+		}, locked, Account{}, Account{}, false)
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.FinishHold(ctx, FinishHoldParams{
+			Status:         HoldStatusCaptured,
+			CapturedAmount: amount,
+			TransferID:     sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true},
+			ID:             hold.ID,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditActionHoldCapture, "holds", hold.ID, hold, result.Hold)
+	})
+	if err != nil {
+		return CaptureHoldResult{}, err
+	}
+	return result, nil
+}
+
+// ReleaseHold ends an active hold without moving any money, the held amount is
+// available again.
+func (store *SQLStore) ReleaseHold(ctx context.Context, holdID int64) (HoldResult, error) {
+	var result HoldResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := q.GetHoldForUpdate(ctx, holdID)
+		if err != nil {
+			return err
+		}
+		if hold.Status != HoldStatusActive {
+			return fmt.Errorf("%w: %d is %s", ErrHoldNotActive, hold.ID, hold.Status)
+		}
+
+		result, err = endHold(ctx, q, hold, HoldStatusReleased)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditActionHoldRelease, "holds", hold.ID, hold, result.Hold)
+	})
+	if err != nil {
+		return HoldResult{}, err
+	}
+	return result, nil
+}
+
+// ExpireHolds releases up to limit active holds past their deadline and returns how
+// many it expired. Holds claimed by another sweeper or being captured are skipped.
+func (store *SQLStore) ExpireHolds(ctx context.Context, limit int32) (int, error) {
+	var expired int
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		holds, err := q.ClaimExpiredHolds(ctx, limit)
+		if err != nil {
+			return err
+		}
+
+		// the accounts are locked in id order, like transfers do, before any is updated
+		ids := make([]int64, len(holds))
+		for i, hold := range holds {
+			ids[i] = hold.AccountID
+		}
+		if _, err := lockAccounts(ctx, q, ids...); err != nil {
+			return err
+		}
+
+		for _, hold := range holds {
+			result, err := endHold(ctx, q, hold, HoldStatusExpired)
+			if err != nil {
+				return err
+			}
+			if err := recordAudit(ctx, q, AuditActionHoldExpire, "holds", hold.ID, hold, result.Hold); err != nil {
+				return err
+			}
+		}
+		expired = len(holds)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return expired, nil
+}
+
+// endHold gives the amount of a hold back to its account and ends it with status.
+func endHold(ctx context.Context, q *Queries, hold Hold, status HoldStatus) (HoldResult, error) {
+	var result HoldResult
+
+	account, err := q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+		Amount: -hold.Amount,
+		ID:     hold.AccountID,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Hold, err = q.FinishHold(ctx, FinishHoldParams{
+		Status: status,
+		ID:     hold.ID,
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Account = account
+	return result, nil
+}
@@ -0,0 +1,247 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: hold.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const claimExpiredHolds = `-- name: ClaimExpiredHolds :many
+SELECT id, account_id, to_account_id, amount, captured_amount, status, transfer_id, expires_at, created_at, updated_at FROM holds
+WHERE status = 'active' AND expires_at <= now()
+ORDER BY expires_at
+LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+func (q *Queries) ClaimExpiredHolds(ctx context.Context, limit int32) ([]Hold, error) {
+	rows, err := q.db.QueryContext(ctx, claimExpiredHolds, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Hold{}
+	for rows.Next() {
+		var i Hold
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CapturedAmount,
+			&i.Status,
+			&i.TransferID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createHold = `-- name: CreateHold :one
+INSERT INTO holds (
+    account_id,
+    to_account_id,
+    amount,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING id, account_id, to_account_id, amount, captured_amount, status, transfer_id, expires_at, created_at, updated_at
+`
+
+type CreateHoldParams struct {
+	AccountID   int64     `json:"accountID"`
+	ToAccountID int64     `json:"toAccountID"`
+	Amount      int64     `json:"amount"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+func (q *Queries) CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error) {
+	row := q.db.QueryRowContext(ctx, createHold,
+		arg.AccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ExpiresAt,
+	)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const finishHold = `-- name: FinishHold :one
+UPDATE holds
+SET status = $1,
+    captured_amount = $2,
+    transfer_id = $3,
+    updated_at = now()
+WHERE id = $4 AND status = 'active'
+RETURNING id, account_id, to_account_id, amount, captured_amount, status, transfer_id, expires_at, created_at, updated_at
+`
+
+type FinishHoldParams struct {
+	Status         HoldStatus    `json:"status"`
+	CapturedAmount int64         `json:"capturedAmount"`
+	TransferID     sql.NullInt64 `json:"transferID"`
+	ID             int64         `json:"id"`
+}
+
+func (q *Queries) FinishHold(ctx context.Context, arg FinishHoldParams) (Hold, error) {
+	row := q.db.QueryRowContext(ctx, finishHold,
+		arg.Status,
+		arg.CapturedAmount,
+		arg.TransferID,
+		arg.ID,
+	)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getHold = `-- name: GetHold :one
+SELECT id, account_id, to_account_id, amount, captured_amount, status, transfer_id, expires_at, created_at, updated_at FROM holds
+WHERE id = $1
+`
+
+func (q *Queries) GetHold(ctx context.Context, id int64) (Hold, error) {
+	row := q.db.QueryRowContext(ctx, getHold, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getHoldForUpdate = `-- name: GetHoldForUpdate :one
+SELECT id, account_id, to_account_id, amount, captured_amount, status, transfer_id, expires_at, created_at, updated_at FROM holds
+WHERE id = $1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetHoldForUpdate(ctx context.Context, id int64) (Hold, error) {
+	row := q.db.QueryRowContext(ctx, getHoldForUpdate, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const holdExpired = `-- name: HoldExpired :one
+SELECT expires_at <= now() AS expired FROM holds
+WHERE id = $1
+`
+
+func (q *Queries) HoldExpired(ctx context.Context, id int64) (bool, error) {
+	row := q.db.QueryRowContext(ctx, holdExpired, id)
+	var expired bool
+	err := row.Scan(&expired)
+	return expired, err
+}
+
+const listHolds = `-- name: ListHolds :many
+SELECT id, account_id, to_account_id, amount, captured_amount, status, transfer_id, expires_at, created_at, updated_at FROM holds
+WHERE account_id = $1
+    AND ($2::varchar = '' OR status::varchar = $2)
+ORDER BY id DESC
+LIMIT $4
+OFFSET $3
+`
+
+type ListHoldsParams struct {
+	AccountID   int64  `json:"accountID"`
+	Status      string `json:"status"`
+	OffsetCount int32  `json:"offsetCount"`
+	LimitCount  int32  `json:"limitCount"`
+}
+
+func (q *Queries) ListHolds(ctx context.Context, arg ListHoldsParams) ([]Hold, error) {
+	rows, err := q.db.QueryContext(ctx, listHolds,
+		arg.AccountID,
+		arg.Status,
+		arg.OffsetCount,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Hold{}
+	for rows.Next() {
+		var i Hold
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CapturedAmount,
+			&i.Status,
+			&i.TransferID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
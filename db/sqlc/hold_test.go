@@ -0,0 +1,208 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func placeRandomHold(t *testing.T, amount int64, expiresAt time.Time) (HoldResult, Account, Account) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+	merchant := createRandomAccount(t)
+
+	result, err := store.PlaceHold(context.Background(), PlaceHoldParams{
+		AccountID:   account.ID,
+		ToAccountID: merchant.ID,
+		Amount:      amount,
+		ExpiresAt:   expiresAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusActive, result.Hold.Status)
+	require.Equal(t, amount, result.Hold.Amount)
+	return result, account, merchant
+}
+
+func TestPlaceHold(t *testing.T) {
+	store := NewStore(testDB)
+	result, account, _ := placeRandomHold(t, 60, time.Now().Add(time.Hour))
+
+	require.Equal(t, account.Balance, result.Account.Balance)
+	require.Equal(t, int64(60), result.Account.HeldBalance)
+	require.Equal(t, account.Balance-60, result.Account.AvailableBalance())
+
+	// the held money can't be spent by a transfer
+	other := createRandomAccount(t)
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account.ID,
+		ToAccountID:   other.ID,
+		Amount:        account.Balance - 59,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.PlaceHold(context.Background(), PlaceHoldParams{
+		AccountID:   account.ID,
+		ToAccountID: other.ID,
+		Amount:      account.Balance - 59,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestCaptureHold(t *testing.T) {
+	store := NewStore(testDB)
+	placed, account, merchant := placeRandomHold(t, 60, time.Now().Add(time.Hour))
+
+	result, err := store.CaptureHold(context.Background(), CaptureHoldParams{HoldID: placed.Hold.ID, Amount: 40})
+	require.NoError(t, err)
+
+	require.Equal(t, HoldStatusCaptured, result.Hold.Status)
+	require.Equal(t, int64(40), result.Hold.CapturedAmount)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Hold.TransferID.Int64)
+
+	// only the captured part moved, the rest is available again
+	require.Equal(t, account.Balance-40, result.Transfer.FromAccount.Balance)
+	require.Zero(t, result.Transfer.FromAccount.HeldBalance)
+	require.Equal(t, merchant.Balance+40, result.Transfer.ToAccount.Balance)
+
+	_, err = store.CaptureHold(context.Background(), CaptureHoldParams{HoldID: placed.Hold.ID})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+	_, err = store.ReleaseHold(context.Background(), placed.Hold.ID)
+	require.ErrorIs(t, err, ErrHoldNotActive)
+}
+
+func TestCaptureHoldExceeds(t *testing.T) {
+	store := NewStore(testDB)
+	placed, _, _ := placeRandomHold(t, 60, time.Now().Add(time.Hour))
+
+	_, err := store.CaptureHold(context.Background(), CaptureHoldParams{HoldID: placed.Hold.ID, Amount: 61})
+	require.ErrorIs(t, err, ErrCaptureExceedsHold)
+
+	hold, err := store.GetHold(context.Background(), placed.Hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusActive, hold.Status)
+}
+
+func TestReleaseHold(t *testing.T) {
+	store := NewStore(testDB)
+	placed, account, _ := placeRandomHold(t, 60, time.Now().Add(time.Hour))
+
+	result, err := store.ReleaseHold(context.Background(), placed.Hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusReleased, result.Hold.Status)
+	require.Equal(t, account.Balance, result.Account.Balance)
+	require.Zero(t, result.Account.HeldBalance)
+}
+
+func TestExpireHolds(t *testing.T) {
+	store := NewStore(testDB)
+	expired, account, _ := placeRandomHold(t, 60, time.Now().Add(-time.Minute))
+	active, _, _ := placeRandomHold(t, 60, time.Now().Add(time.Hour))
+
+	_, err := store.CaptureHold(context.Background(), CaptureHoldParams{HoldID: expired.Hold.ID})
+	require.ErrorIs(t, err, ErrHoldExpired)
+
+	// other tests leave expired holds behind too, sweep until none is left
+	for {
+		n, err := store.ExpireHolds(context.Background(), 100)
+		require.NoError(t, err)
+		if n == 0 {
+			break
+		}
+	}
+
+	hold, err := store.GetHold(context.Background(), expired.Hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusExpired, hold.Status)
+
+	updated, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Zero(t, updated.HeldBalance)
+
+	hold, err = store.GetHold(context.Background(), active.Hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusActive, hold.Status)
+}
+
+func TestCloseAccountWithHold(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+	merchant := createRandomAccount(t)
+
+	// an empty account can only hold money through its overdraft limit
+	_, err := store.WithdrawTx(context.Background(), CashTxParams{AccountID: account.ID, Amount: account.Balance})
+	require.NoError(t, err)
+	_, err = testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{ID: account.ID, OverdraftLimit: 100})
+	require.NoError(t, err)
+
+	placed, err := store.PlaceHold(context.Background(), PlaceHoldParams{
+		AccountID:   account.ID,
+		ToAccountID: merchant.ID,
+		Amount:      60,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{AccountID: account.ID, Status: AccountStatusClosed})
+	require.ErrorIs(t, err, ErrAccountNotEmpty)
+
+	_, err = store.ReleaseHold(context.Background(), placed.Hold.ID)
+	require.NoError(t, err)
+
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{AccountID: account.ID, Status: AccountStatusClosed})
+	require.NoError(t, err)
+}
+
+func TestHoldLimits(t *testing.T) {
+	store := NewStore(testDB)
+	currency := createLimitCurrency(t)
+	account := createLimitAccount(t, createRandomUser(t).Username, currency, 1000)
+	merchant := createLimitAccount(t, createRandomUser(t).Username, currency, 0)
+
+	_, err := store.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		Currency:       currency,
+		PerTransaction: 50,
+		Daily:          100,
+	})
+	require.NoError(t, err)
+
+	place := func(amount int64) (HoldResult, error) {
+		return store.PlaceHold(context.Background(), PlaceHoldParams{
+			AccountID:   account.ID,
+			ToAccountID: merchant.ID,
+			Amount:      amount,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		})
+	}
+	transfer := func(amount int64) error {
+		_, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account.ID,
+			ToAccountID:   merchant.ID,
+			Amount:        amount,
+		})
+		return err
+	}
+
+	_, err = place(60)
+	requireLimitError(t, err, LimitPerTransaction, 50)
+	first, err := place(50)
+	require.NoError(t, err)
+	second, err := place(40)
+	require.NoError(t, err)
+
+	// money on hold counts as sent, for holds and transfers alike
+	_, err = place(20)
+	requireLimitError(t, err, LimitDaily, 10)
+	requireLimitError(t, transfer(20), LimitDaily, 10)
+
+	// the capture is not checked again and doesn't count twice
+	_, err = store.CaptureHold(context.Background(), CaptureHoldParams{HoldID: first.Hold.ID})
+	require.NoError(t, err)
+	requireLimitError(t, transfer(20), LimitDaily, 10)
+
+	_, err = store.ReleaseHold(context.Background(), second.Hold.ID)
+	require.NoError(t, err)
+	require.NoError(t, transfer(50))
+}
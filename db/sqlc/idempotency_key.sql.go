@@ -0,0 +1,68 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: idempotency_key.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+)
+
+const createIdempotencyKey = `-- name: CreateIdempotencyKey :one
+INSERT INTO idempotency_keys (
+    username,
+    key,
+    request_hash,
+    response
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING username, key, request_hash, response, created_at
+`
+
+type CreateIdempotencyKeyParams struct {
+	Username    string          `json:"username"`
+	Key         string          `json:"key"`
+	RequestHash string          `json:"requestHash"`
+	Response    json.RawMessage `json:"response"`
+}
+
+func (q *Queries) CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, createIdempotencyKey,
+		arg.Username,
+		arg.Key,
+		arg.RequestHash,
+		arg.Response,
+	)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Username,
+		&i.Key,
+		&i.RequestHash,
+		&i.Response,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT username, key, request_hash, response, created_at FROM idempotency_keys
+WHERE username = $1 AND key = $2 LIMIT 1
+`
+
+type GetIdempotencyKeyParams struct {
+	Username string `json:"username"`
+	Key      string `json:"key"`
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, getIdempotencyKey, arg.Username, arg.Key)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Username,
+		&i.Key,
+		&i.RequestHash,
+		&i.Response,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -3,15 +3,251 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
 	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccountStatus string
+
+const (
+	AccountStatusActive AccountStatus = "active"
+	AccountStatusFrozen AccountStatus = "frozen"
+	AccountStatusClosed AccountStatus = "closed"
+)
+
+func (e *AccountStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccountStatus(s)
+	case string:
+		*e = AccountStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccountStatus: %T", src)
+	}
+	return nil
+}
+
+type AdjustmentReason string
+
+const (
+	AdjustmentReasonCorrection AdjustmentReason = "correction"
+	AdjustmentReasonGoodwill   AdjustmentReason = "goodwill"
+	AdjustmentReasonChargeback AdjustmentReason = "chargeback"
+	AdjustmentReasonFeeRefund  AdjustmentReason = "fee_refund"
+	AdjustmentReasonWriteOff   AdjustmentReason = "write_off"
+)
+
+func (e *AdjustmentReason) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AdjustmentReason(s)
+	case string:
+		*e = AdjustmentReason(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AdjustmentReason: %T", src)
+	}
+	return nil
+}
+
+type EntryKind string
+
+const (
+	EntryKindTransfer   EntryKind = "transfer"
+	EntryKindDeposit    EntryKind = "deposit"
+	EntryKindWithdrawal EntryKind = "withdrawal"
+	EntryKindFee        EntryKind = "fee"
+	EntryKindAdjustment EntryKind = "adjustment"
 )
 
+func (e *EntryKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = EntryKind(s)
+	case string:
+		*e = EntryKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for EntryKind: %T", src)
+	}
+	return nil
+}
+
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "active"
+	HoldStatusCaptured HoldStatus = "captured"
+	HoldStatusReleased HoldStatus = "released"
+	HoldStatusExpired  HoldStatus = "expired"
+)
+
+func (e *HoldStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = HoldStatus(s)
+	case string:
+		*e = HoldStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for HoldStatus: %T", src)
+	}
+	return nil
+}
+
+type ScheduledRunStatus string
+
+const (
+	ScheduledRunStatusSucceeded ScheduledRunStatus = "succeeded"
+	ScheduledRunStatusFailed    ScheduledRunStatus = "failed"
+)
+
+func (e *ScheduledRunStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduledRunStatus(s)
+	case string:
+		*e = ScheduledRunStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduledRunStatus: %T", src)
+	}
+	return nil
+}
+
+type ScheduledTransferStatus string
+
+const (
+	ScheduledTransferStatusActive    ScheduledTransferStatus = "active"
+	ScheduledTransferStatusCompleted ScheduledTransferStatus = "completed"
+	ScheduledTransferStatusCancelled ScheduledTransferStatus = "cancelled"
+)
+
+func (e *ScheduledTransferStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduledTransferStatus(s)
+	case string:
+		*e = ScheduledTransferStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduledTransferStatus: %T", src)
+	}
+	return nil
+}
+
+type TransferStatus string
+
+const (
+	TransferStatusCompleted         TransferStatus = "completed"
+	TransferStatusPartiallyReversed TransferStatus = "partially_reversed"
+	TransferStatusReversed          TransferStatus = "reversed"
+)
+
+func (e *TransferStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TransferStatus(s)
+	case string:
+		*e = TransferStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TransferStatus: %T", src)
+	}
+	return nil
+}
+
+type UserRole string
+
+const (
+	UserRoleCustomer UserRole = "customer"
+	UserRoleSupport  UserRole = "support"
+	UserRoleAdmin    UserRole = "admin"
+)
+
+func (e *UserRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UserRole(s)
+	case string:
+		*e = UserRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UserRole: %T", src)
+	}
+	return nil
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+func (e *WebhookDeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatus(s)
+	case string:
+		*e = WebhookDeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatus: %T", src)
+	}
+	return nil
+}
+
 type Account struct {
 	ID        int64     `json:"id"`
 	Owner     string    `json:"owner"`
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"createdAt"`
+	// how far below zero the balance may go
+	OverdraftLimit int64 `json:"overdraftLimit"`
+	// money only moves in and out of active accounts
+	Status AccountStatus `json:"status"`
+	// set when the account is closed, closed accounts are never reopened
+	ClosedAt sql.NullTime `json:"closedAt"`
+	// sum of the active holds on the account, taken off its available balance
+	HeldBalance int64 `json:"heldBalance"`
+}
+
+type AuditEvent struct {
+	ID int64 `json:"id"`
+	// username of the authenticated user, empty for anonymous requests and background jobs
+	Actor string `json:"actor"`
+	// http method and route for request events, e.g. account.status for store events
+	Action       string `json:"action"`
+	ResourceType string `json:"resourceType"`
+	ResourceID   string `json:"resourceID"`
+	// ties the store events of a request to its request event
+	RequestID string          `json:"requestID"`
+	ClientIp  string          `json:"clientIp"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+type BalanceAdjustment struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"accountID"`
+	EntryID   int64 `json:"entryID"`
+	// can be positive or negative, must not be zero
+	Amount     int64            `json:"amount"`
+	ReasonCode AdjustmentReason `json:"reasonCode"`
+	Note       string           `json:"note"`
+	// admin who made the adjustment
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Currency struct {
+	// ISO 4217 alphabetic code
+	Code string `json:"code"`
+	// number of minor units digits, 2 for USD cents
+	Exponent int32 `json:"exponent"`
+	// disabled currencies can not be used for new accounts
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type Entry struct {
@@ -20,6 +256,96 @@ type Entry struct {
 	// can be possitive or negative
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"createdAt"`
+	// transfer that created the entry, if any
+	TransferID sql.NullInt64 `json:"transferID"`
+	Kind       EntryKind     `json:"kind"`
+}
+
+type Hold struct {
+	ID          int64 `json:"id"`
+	AccountID   int64 `json:"accountID"`
+	ToAccountID int64 `json:"toAccountID"`
+	Amount      int64 `json:"amount"`
+	// part of amount moved to to_account_id by the capture, the rest went back to the available balance
+	CapturedAmount int64      `json:"capturedAmount"`
+	Status         HoldStatus `json:"status"`
+	// transfer made by the capture
+	TransferID sql.NullInt64 `json:"transferID"`
+	ExpiresAt  time.Time     `json:"expiresAt"`
+	CreatedAt  time.Time     `json:"createdAt"`
+	UpdatedAt  time.Time     `json:"updatedAt"`
+}
+
+type IdempotencyKey struct {
+	Username string `json:"username"`
+	Key      string `json:"key"`
+	// sha256 of the request the key was first used with
+	RequestHash string          `json:"requestHash"`
+	Response    json.RawMessage `json:"response"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+type OutboxEvent struct {
+	ID int64 `json:"id"`
+	// e.g. transfer.completed, account.created, user.created
+	EventType     string          `json:"eventType"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   string          `json:"aggregateID"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int32           `json:"attempts"`
+	LastError     string          `json:"lastError"`
+	NextAttemptAt time.Time       `json:"nextAttemptAt"`
+	// set once every sink took the event, pending until then
+	PublishedAt sql.NullTime `json:"publishedAt"`
+	CreatedAt   time.Time    `json:"createdAt"`
+}
+
+type RevokedToken struct {
+	// id of the revoked token payload
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	// when the token expires anyway, the row can be deleted after that
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64 `json:"id"`
+	FromAccountID int64 `json:"fromAccountID"`
+	ToAccountID   int64 `json:"toAccountID"`
+	Amount        int64 `json:"amount"`
+	// empty for a one-off transfer at start_at, else a cron expression or an RRULE starting at start_at, in utc
+	Schedule string    `json:"schedule"`
+	StartAt  time.Time `json:"startAt"`
+	// null once the schedule has no more runs
+	NextRunAt sql.NullTime            `json:"nextRunAt"`
+	Status    ScheduledTransferStatus `json:"status"`
+	RunCount  int32                   `json:"runCount"`
+	LastRunAt sql.NullTime            `json:"lastRunAt"`
+	CreatedAt time.Time               `json:"createdAt"`
+	UpdatedAt time.Time               `json:"updatedAt"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64              `json:"id"`
+	ScheduledTransferID int64              `json:"scheduledTransferID"`
+	ScheduledFor        time.Time          `json:"scheduledFor"`
+	Status              ScheduledRunStatus `json:"status"`
+	TransferID          sql.NullInt64      `json:"transferID"`
+	Error               string             `json:"error"`
+	CreatedAt           time.Time          `json:"createdAt"`
+}
+
+type Session struct {
+	// id of the refresh token payload
+	ID           uuid.UUID `json:"id"`
+	Username     string    `json:"username"`
+	RefreshToken string    `json:"refreshToken"`
+	UserAgent    string    `json:"userAgent"`
+	ClientIp     string    `json:"clientIp"`
+	IsBlocked    bool      `json:"isBlocked"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	CreatedAt    time.Time `json:"createdAt"`
 }
 
 type Transfer struct {
@@ -29,6 +355,30 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"createdAt"`
+	// amount credited to the destination account, in its currency
+	ToAmount int64 `json:"toAmount"`
+	// price of one unit of the source currency in the destination currency
+	ExchangeRate string         `json:"exchangeRate"`
+	Status       TransferStatus `json:"status"`
+	// part of amount sent back by reversals, in the source currency
+	RefundedAmount int64 `json:"refundedAmount"`
+	// transfer this one reverses, it moves money the other way
+	ReversalOf sql.NullInt64 `json:"reversalOf"`
+}
+
+// rows without account and owner are the defaults of a currency, an account row replaces them for that account, an owner row caps all accounts of the user in the currency
+type TransferLimit struct {
+	ID        int64          `json:"id"`
+	Currency  string         `json:"currency"`
+	AccountID sql.NullInt64  `json:"accountID"`
+	Owner     sql.NullString `json:"owner"`
+	// 0 means no limit, like daily and monthly
+	PerTransaction int64 `json:"perTransaction"`
+	// total of outgoing transfers per utc calendar day
+	Daily int64 `json:"daily"`
+	// total of outgoing transfers per utc calendar month
+	Monthly   int64     `json:"monthly"`
+	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 type User struct {
@@ -38,4 +388,50 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"passwordChangedAt"`
 	CreatedAt         time.Time `json:"createdAt"`
+	// carried in the access token, admin and support routes check it
+	Role UserRole `json:"role"`
+}
+
+type UserRevocation struct {
+	Username string `json:"username"`
+	// tokens of the user issued at or before this time are rejected
+	RevokedBefore time.Time `json:"revokedBefore"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+type WebhookDelivery struct {
+	ID             int64 `json:"id"`
+	SubscriptionID int64 `json:"subscriptionID"`
+	// outbox event being delivered, one delivery per subscription and event
+	EventID   int64                 `json:"eventID"`
+	EventType string                `json:"eventType"`
+	Payload   json.RawMessage       `json:"payload"`
+	Status    WebhookDeliveryStatus `json:"status"`
+	Attempts  int32                 `json:"attempts"`
+	// http status of the last attempt, 0 when no response came back
+	LastStatusCode int32        `json:"lastStatusCode"`
+	LastError      string       `json:"lastError"`
+	NextAttemptAt  time.Time    `json:"nextAttemptAt"`
+	DeliveredAt    sql.NullTime `json:"deliveredAt"`
+	CreatedAt      time.Time    `json:"createdAt"`
+}
+
+type WebhookDeliveryAttempt struct {
+	ID         int64     `json:"id"`
+	DeliveryID int64     `json:"deliveryID"`
+	StatusCode int32     `json:"statusCode"`
+	Error      string    `json:"error"`
+	DurationMs int64     `json:"durationMs"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type WebhookSubscription struct {
+	ID        int64  `json:"id"`
+	AccountID int64  `json:"accountID"`
+	Url       string `json:"url"`
+	EventType string `json:"eventType"`
+	// hmac key the deliveries are signed with
+	Secret    string    `json:"secret"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
 }
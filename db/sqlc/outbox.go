@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// types of the events written to the outbox
+const (
+	EventTransferCompleted = "transfer.completed"
+	EventAccountCreated    = "account.created"
+	EventUserCreated       = "user.created"
+)
+
+// UserCreatedEvent is the payload of user.created, it leaves out the password hash.
+type UserCreatedEvent struct {
+	Username  string    `json:"username"`
+	FullName  string    `json:"fullName"`
+	Email     string    `json:"email"`
+	Role      UserRole  `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// writeEvent adds an event to the outbox in the transaction of the change, so the event
+// exists exactly when the change is committed.
+func writeEvent(ctx context.Context, q *Queries, eventType, aggregateType, aggregateID string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+	})
+	return err
+}
+
+// CreateAccountTx creates an account and its account.created event.
+func (store *SQLStore) CreateAccountTx(ctx context.Context, args CreateAccountParams) (Account, error) {
+	var account Account
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		account, err = q.CreateAccount(ctx, args)
+		if err != nil {
+			return err
+		}
+		return writeEvent(ctx, q, EventAccountCreated, "accounts", strconv.FormatInt(account.ID, 10), account)
+	})
+	if err != nil {
+		return Account{}, err
+	}
+	return account, nil
+}
+
+// CreateUserTx creates a user and its user.created event.
+func (store *SQLStore) CreateUserTx(ctx context.Context, args CreateUserParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.CreateUser(ctx, args)
+		if err != nil {
+			return err
+		}
+		return writeEvent(ctx, q, EventUserCreated, "users", user.Username, UserCreatedEvent{
+			Username:  user.Username,
+			FullName:  user.FullName,
+			Email:     user.Email,
+			Role:      user.Role,
+			CreatedAt: user.CreatedAt,
+		})
+	})
+	if err != nil {
+		return User{}, err
+	}
+	return user, nil
+}
+
+type ProcessOutboxParams struct {
+	Limit int32
+	// Lease is how long claimed events are kept from other dispatchers, it has to cover
+	// publishing the whole batch. Events not recorded by then are published again.
+	Lease time.Duration
+	// Publish hands an event to the sinks, an error leaves the event pending
+	Publish func(ctx context.Context, event OutboxEvent) error
+	// RetryAt tells when an event that failed to publish is tried again
+	RetryAt func(event OutboxEvent) time.Time
+}
+
+type ProcessOutboxResult struct {
+	Published int `json:"published"`
+	Failed    int `json:"failed"`
+}
+
+// ProcessOutbox publishes a batch of due outbox events. The batch is claimed by pushing
+// next_attempt_at past the lease, other dispatchers skip the events instead of waiting,
+// and the claim is committed before any sink is called so no transaction stays open
+// while they work. An event is only marked published after Publish returned, so a crash
+// in between publishes it again.
+func (store *SQLStore) ProcessOutbox(ctx context.Context, args ProcessOutboxParams) (ProcessOutboxResult, error) {
+	var result ProcessOutboxResult
+
+	events, err := store.ClaimOutboxEvents(ctx, ClaimOutboxEventsParams{
+		LeaseSeconds: args.Lease.Seconds(),
+		LimitCount:   args.Limit,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	for _, event := range events {
+		if publishErr := args.Publish(ctx, event); publishErr != nil {
+			err = store.MarkOutboxEventFailed(ctx, MarkOutboxEventFailedParams{
+				ID:            event.ID,
+				LastError:     publishErr.Error(),
+				NextAttemptAt: args.RetryAt(event),
+			})
+			if err != nil {
+				return result, err
+			}
+			result.Failed++
+			continue
+		}
+
+		if err = store.MarkOutboxEventPublished(ctx, event.ID); err != nil {
+			// the events left are published again once their lease runs out
+			return result, err
+		}
+		result.Published++
+	}
+	return result, nil
+}
@@ -0,0 +1,180 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: outbox.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const claimOutboxEvents = `-- name: ClaimOutboxEvents :many
+UPDATE outbox
+SET next_attempt_at = now() + make_interval(secs => $1::float8)
+WHERE id IN (
+    SELECT id FROM outbox
+    WHERE published_at IS NULL AND next_attempt_at <= now()
+    ORDER BY id
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, event_type, aggregate_type, aggregate_id, payload, attempts, last_error, next_attempt_at, published_at, created_at
+`
+
+type ClaimOutboxEventsParams struct {
+	LeaseSeconds float64 `json:"leaseSeconds"`
+	LimitCount   int32   `json:"limitCount"`
+}
+
+func (q *Queries) ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]OutboxEvent, error) {
+	rows, err := q.db.QueryContext(ctx, claimOutboxEvents, arg.LeaseSeconds, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OutboxEvent{}
+	for rows.Next() {
+		var i OutboxEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.Payload,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.PublishedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createOutboxEvent = `-- name: CreateOutboxEvent :one
+INSERT INTO outbox (
+    event_type,
+    aggregate_type,
+    aggregate_id,
+    payload
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING id, event_type, aggregate_type, aggregate_id, payload, attempts, last_error, next_attempt_at, published_at, created_at
+`
+
+type CreateOutboxEventParams struct {
+	EventType     string          `json:"eventType"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   string          `json:"aggregateID"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+func (q *Queries) CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (OutboxEvent, error) {
+	row := q.db.QueryRowContext(ctx, createOutboxEvent,
+		arg.EventType,
+		arg.AggregateType,
+		arg.AggregateID,
+		arg.Payload,
+	)
+	var i OutboxEvent
+	err := row.Scan(
+		&i.ID,
+		&i.EventType,
+		&i.AggregateType,
+		&i.AggregateID,
+		&i.Payload,
+		&i.Attempts,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.PublishedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listOutboxEvents = `-- name: ListOutboxEvents :many
+SELECT id, event_type, aggregate_type, aggregate_id, payload, attempts, last_error, next_attempt_at, published_at, created_at FROM outbox
+WHERE aggregate_type = $1 AND aggregate_id = $2
+ORDER BY id
+`
+
+type ListOutboxEventsParams struct {
+	AggregateType string `json:"aggregateType"`
+	AggregateID   string `json:"aggregateID"`
+}
+
+func (q *Queries) ListOutboxEvents(ctx context.Context, arg ListOutboxEventsParams) ([]OutboxEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listOutboxEvents, arg.AggregateType, arg.AggregateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OutboxEvent{}
+	for rows.Next() {
+		var i OutboxEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.Payload,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.PublishedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxEventFailed = `-- name: MarkOutboxEventFailed :exec
+UPDATE outbox
+SET attempts = attempts + 1,
+    last_error = $1,
+    next_attempt_at = $2
+WHERE id = $3
+`
+
+type MarkOutboxEventFailedParams struct {
+	LastError     string    `json:"lastError"`
+	NextAttemptAt time.Time `json:"nextAttemptAt"`
+	ID            int64     `json:"id"`
+}
+
+func (q *Queries) MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markOutboxEventFailed, arg.LastError, arg.NextAttemptAt, arg.ID)
+	return err
+}
+
+const markOutboxEventPublished = `-- name: MarkOutboxEventPublished :exec
+UPDATE outbox
+SET published_at = now(),
+    attempts = attempts + 1,
+    last_error = ''
+WHERE id = $1
+`
+
+func (q *Queries) MarkOutboxEventPublished(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markOutboxEventPublished, id)
+	return err
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxOutboxEvent(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	events, err := store.ListOutboxEvents(context.Background(), ListOutboxEventsParams{
+		AggregateType: "transfers",
+		AggregateID:   strconv.FormatInt(result.Transfer.ID, 10),
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, EventTransferCompleted, events[0].EventType)
+	require.False(t, events[0].PublishedAt.Valid)
+
+	var transfer Transfer
+	require.NoError(t, json.Unmarshal(events[0].Payload, &transfer))
+	require.Equal(t, result.Transfer.ID, transfer.ID)
+	require.Equal(t, int64(10), transfer.Amount)
+}
+
+func TestCreateUserTxOutboxEvent(t *testing.T) {
+	store := NewStore(testDB)
+
+	user, err := store.CreateUserTx(context.Background(), CreateUserParams{
+		Username:       util.RandomOwner(),
+		HashedPassword: "secret-hash",
+		FullName:       util.RandomOwner(),
+		Email:          util.RandomEmail(),
+	})
+	require.NoError(t, err)
+
+	events, err := store.ListOutboxEvents(context.Background(), ListOutboxEventsParams{
+		AggregateType: "users",
+		AggregateID:   user.Username,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, EventUserCreated, events[0].EventType)
+	require.NotContains(t, string(events[0].Payload), "secret-hash")
+
+	account, err := store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Currency: util.USD,
+	})
+	require.NoError(t, err)
+
+	events, err = store.ListOutboxEvents(context.Background(), ListOutboxEventsParams{
+		AggregateType: "accounts",
+		AggregateID:   strconv.FormatInt(account.ID, 10),
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, EventAccountCreated, events[0].EventType)
+
+	// a failed user insert leaves no event behind
+	_, err = store.CreateUserTx(context.Background(), CreateUserParams{
+		Username:       user.Username,
+		HashedPassword: "secret-hash",
+		FullName:       user.FullName,
+		Email:          util.RandomEmail(),
+	})
+	require.Error(t, err)
+	events, err = store.ListOutboxEvents(context.Background(), ListOutboxEventsParams{
+		AggregateType: "users",
+		AggregateID:   user.Username,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+}
+
+func TestProcessOutbox(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	// publish whatever earlier tests left pending
+	_, err := store.ProcessOutbox(context.Background(), ProcessOutboxParams{
+		Limit:   1000,
+		Lease:   time.Minute,
+		Publish: func(ctx context.Context, event OutboxEvent) error { return nil },
+		RetryAt: func(event OutboxEvent) time.Time { return time.Now() },
+	})
+	require.NoError(t, err)
+
+	_, err = store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    account.Owner,
+		Currency: util.EUR,
+	})
+	require.NoError(t, err)
+
+	fail := ProcessOutboxParams{
+		Limit: 10,
+		Lease: time.Minute,
+		Publish: func(ctx context.Context, event OutboxEvent) error {
+			// the claim is committed before publishing, the event is leased but not locked
+			events, err := testQueries.ListOutboxEvents(ctx, ListOutboxEventsParams{
+				AggregateType: event.AggregateType,
+				AggregateID:   event.AggregateID,
+			})
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			require.True(t, events[0].NextAttemptAt.After(time.Now()))
+			return errors.New("sink is down")
+		},
+		RetryAt: func(event OutboxEvent) time.Time { return time.Now().Add(time.Hour) },
+	}
+	result, err := store.ProcessOutbox(context.Background(), fail)
+	require.NoError(t, err)
+	require.Equal(t, ProcessOutboxResult{Failed: 1}, result)
+
+	// not due before its next attempt
+	result, err = store.ProcessOutbox(context.Background(), fail)
+	require.NoError(t, err)
+	require.Equal(t, ProcessOutboxResult{}, result)
+}
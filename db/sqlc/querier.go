@@ -4,24 +4,102 @@ package db
 
 import (
 	"context"
+
+	"github.com/google/uuid"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	AddAccountHeldBalance(ctx context.Context, arg AddAccountHeldBalanceParams) (Account, error)
+	AddTransferRefund(ctx context.Context, arg AddTransferRefundParams) (Transfer, error)
+	AdvanceScheduledTransfer(ctx context.Context, arg AdvanceScheduledTransferParams) (ScheduledTransfer, error)
+	BlockSession(ctx context.Context, id uuid.UUID) (Session, error)
+	BlockUserSessions(ctx context.Context, username string) (int64, error)
+	CancelScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	ClaimDueScheduledTransfer(ctx context.Context) (ScheduledTransfer, error)
+	ClaimExpiredHolds(ctx context.Context, limit int32) ([]Hold, error)
+	ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]OutboxEvent, error)
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error)
+	CreateBalanceAdjustment(ctx context.Context, arg CreateBalanceAdjustmentParams) (BalanceAdjustment, error)
+	CreateCashAccount(ctx context.Context, currency string) (Account, error)
+	CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (Currency, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (OutboxEvent, error)
+	CreateRevokedToken(ctx context.Context, arg CreateRevokedTokenParams) error
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteAccount(ctx context.Context, id int64) error
+	CreateWebhookDeliveryAttempt(ctx context.Context, arg CreateWebhookDeliveryAttemptParams) (WebhookDeliveryAttempt, error)
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error)
+	DeactivateWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error)
+	DeleteExpiredRevokedTokens(ctx context.Context) (int64, error)
+	DeleteExpiredUserRevocations(ctx context.Context) (int64, error)
+	DeleteTransferLimit(ctx context.Context, id int64) (TransferLimit, error)
+	EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) (int64, error)
+	FinishHold(ctx context.Context, arg FinishHoldParams) (Hold, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountBalanceAt(ctx context.Context, arg GetAccountBalanceAtParams) (int64, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountTransferLimit(ctx context.Context, arg GetAccountTransferLimitParams) (TransferLimit, error)
+	GetCashAccount(ctx context.Context, currency string) (Account, error)
+	GetCurrency(ctx context.Context, code string) (Currency, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserTransferLimit(ctx context.Context, arg GetUserTransferLimitParams) (TransferLimit, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error)
+	HoldExpired(ctx context.Context, id int64) (bool, error)
+	IsTokenRevoked(ctx context.Context, arg IsTokenRevokedParams) (bool, error)
+	ListAccountBalanceMismatches(ctx context.Context) ([]ListAccountBalanceMismatchesRow, error)
+	ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListAccountsByIDs(ctx context.Context, ids []int64) ([]Account, error)
+	ListAllAccounts(ctx context.Context, arg ListAllAccountsParams) ([]Account, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
+	ListBalanceAdjustments(ctx context.Context, arg ListBalanceAdjustmentsParams) ([]BalanceAdjustment, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListEntryLegs(ctx context.Context, id int64) ([]Entry, error)
+	ListHolds(ctx context.Context, arg ListHoldsParams) ([]Hold, error)
+	ListOutboxEvents(ctx context.Context, arg ListOutboxEventsParams) ([]OutboxEvent, error)
+	ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error)
+	ListScheduledTransfers(ctx context.Context, fromAccountID int64) ([]ScheduledTransfer, error)
+	ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]Entry, error)
+	ListTransferEntries(ctx context.Context, transferID int64) ([]Entry, error)
+	ListTransferLimits(ctx context.Context, currency string) ([]TransferLimit, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
-	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	ListUnbalancedTransfers(ctx context.Context) ([]ListUnbalancedTransfersRow, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookDeliveryAttempts(ctx context.Context, deliveryID int64) ([]WebhookDeliveryAttempt, error)
+	ListWebhookSubscriptions(ctx context.Context, accountID int64) ([]WebhookSubscription, error)
+	LockUser(ctx context.Context, username string) (string, error)
+	MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error
+	MarkOutboxEventPublished(ctx context.Context, id int64) error
+	ReplayWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	SumAccountTransfersSince(ctx context.Context, arg SumAccountTransfersSinceParams) (int64, error)
+	SumUserHeldBalance(ctx context.Context, arg SumUserHeldBalanceParams) (int64, error)
+	SumUserTransfersSince(ctx context.Context, arg SumUserTransfersSinceParams) (int64, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdateCurrencyEnabled(ctx context.Context, arg UpdateCurrencyEnabledParams) (Currency, error)
+	UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfer, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDeliveryParams) error
+	UpsertTransferLimit(ctx context.Context, arg UpsertTransferLimitParams) (TransferLimit, error)
+	UpsertUserRevocation(ctx context.Context, arg UpsertUserRevocationParams) error
 }
 
 var _ Querier = (*Queries)(nil)
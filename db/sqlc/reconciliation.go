@@ -0,0 +1,33 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type ReconciliationReport struct {
+	CheckedAt           time.Time                         `json:"checkedAt"`
+	Balanced            bool                              `json:"balanced"`
+	AccountMismatches   []ListAccountBalanceMismatchesRow `json:"accountMismatches"`
+	UnbalancedTransfers []ListUnbalancedTransfersRow      `json:"unbalancedTransfers"`
+}
+
+// Reconcile compares every account balance with the sum of its entries and
+// checks that the entries of every transfer add up to zero.
+func (store *SQLStore) Reconcile(ctx context.Context) (ReconciliationReport, error) {
+	report := ReconciliationReport{CheckedAt: time.Now()}
+
+	var err error
+	report.AccountMismatches, err = store.ListAccountBalanceMismatches(ctx)
+	if err != nil {
+		return ReconciliationReport{}, err
+	}
+
+	report.UnbalancedTransfers, err = store.ListUnbalancedTransfers(ctx)
+	if err != nil {
+		return ReconciliationReport{}, err
+	}
+
+	report.Balanced = len(report.AccountMismatches) == 0 && len(report.UnbalancedTransfers) == 0
+	return report, nil
+}
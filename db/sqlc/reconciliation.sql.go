@@ -0,0 +1,96 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: reconciliation.sql
+
+package db
+
+import (
+	"context"
+)
+
+const listAccountBalanceMismatches = `-- name: ListAccountBalanceMismatches :many
+SELECT a.id AS account_id, a.balance, COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+GROUP BY a.id
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+ORDER BY a.id
+`
+
+type ListAccountBalanceMismatchesRow struct {
+	AccountID    int64 `json:"accountID"`
+	Balance      int64 `json:"balance"`
+	EntriesTotal int64 `json:"entriesTotal"`
+}
+
+func (q *Queries) ListAccountBalanceMismatches(ctx context.Context) ([]ListAccountBalanceMismatchesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountBalanceMismatches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAccountBalanceMismatchesRow{}
+	for rows.Next() {
+		var i ListAccountBalanceMismatchesRow
+		if err := rows.Scan(&i.AccountID, &i.Balance, &i.EntriesTotal); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnbalancedTransfers = `-- name: ListUnbalancedTransfers :many
+SELECT
+    t.id AS transfer_id,
+    COALESCE(a.currency, '')::varchar AS currency,
+    COUNT(e.id) AS entries_count,
+    COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM transfers t
+LEFT JOIN entries e ON e.transfer_id = t.id
+LEFT JOIN accounts a ON a.id = e.account_id
+GROUP BY t.id, a.currency
+HAVING COUNT(e.id) = 0 OR COALESCE(SUM(e.amount), 0) <> 0
+ORDER BY t.id
+`
+
+type ListUnbalancedTransfersRow struct {
+	TransferID   int64  `json:"transferID"`
+	Currency     string `json:"currency"`
+	EntriesCount int64  `json:"entriesCount"`
+	EntriesTotal int64  `json:"entriesTotal"`
+}
+
+// cross currency transfers are balanced per currency through the system accounts.
+func (q *Queries) ListUnbalancedTransfers(ctx context.Context) ([]ListUnbalancedTransfersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUnbalancedTransfers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUnbalancedTransfersRow{}
+	for rows.Next() {
+		var i ListUnbalancedTransfersRow
+		if err := rows.Scan(
+			&i.TransferID,
+			&i.Currency,
+			&i.EntriesCount,
+			&i.EntriesTotal,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
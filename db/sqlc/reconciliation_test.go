@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcile(t *testing.T) {
+	store := NewStore(testDB)
+
+	// random accounts start with a balance that has no entries behind it
+	drifted := createRandomAccount(t)
+	for drifted.Balance == 0 {
+		drifted = createRandomAccount(t)
+	}
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	report, err := store.Reconcile(context.Background())
+	require.NoError(t, err)
+	require.False(t, report.Balanced)
+	require.NotZero(t, report.CheckedAt)
+
+	var found bool
+	for _, mismatch := range report.AccountMismatches {
+		if mismatch.AccountID == drifted.ID {
+			found = true
+			require.Equal(t, drifted.Balance, mismatch.Balance)
+			require.Zero(t, mismatch.EntriesTotal)
+		}
+	}
+	require.True(t, found)
+
+	for _, transfer := range report.UnbalancedTransfers {
+		require.NotEqual(t, result.Transfer.ID, transfer.TransferID)
+	}
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrTransferNotReversible = errors.New("transfer can't be reversed")
+	ErrRefundExceedsTransfer = errors.New("refund exceeds what is left of the transfer")
+	ErrRefundTooSmall        = errors.New("refund is too small to convert into the destination currency")
+)
+
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transferID"`
+	// Amount is how much of the original amount goes back, zero means all that is left
+	Amount int64 `json:"amount"`
+}
+
+type ReverseTransferTxResult struct {
+	Original Transfer         `json:"original"`
+	Reversal TransferTxResult `json:"reversal"`
+}
+
+// ReverseTransferTx sends all or part of a transfer back with a compensating transfer
+// linked to it. The original transfer row is locked first, so concurrent refunds of
+// the same transfer run one after the other and never add up to more than its amount.
+func (store *SQLStore) ReverseTransferTx(ctx context.Context, args ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		original, err := q.GetTransferForUpdate(ctx, args.TransferID)
+		if err != nil {
+			return err
+		}
+		if original.ReversalOf.Valid {
+			return fmt.Errorf("%w: %d is itself a reversal", ErrTransferNotReversible, original.ID)
+		}
+
+		left := original.Amount - original.RefundedAmount
+		if left == 0 {
+			return fmt.Errorf("%w: %d is already reversed", ErrTransferNotReversible, original.ID)
+		}
+		amount := args.Amount
+		if amount == 0 {
+			amount = left
+		}
+		if amount < 0 || amount > left {
+			return fmt.Errorf("%w: %d left", ErrRefundExceedsTransfer, left)
+		}
+
+		// the destination side is worked out on the running total so that the partial
+		// refunds of a transfer add up to exactly its destination amount
+		toAmount := proportion(original.ToAmount, original.RefundedAmount+amount, original.Amount) -
+			proportion(original.ToAmount, original.RefundedAmount, original.Amount)
+		if toAmount <= 0 {
+			return ErrRefundTooSmall
+		}
+
+		rate, err := inverseRate(original.ExchangeRate)
+		if err != nil {
+			return err
+		}
+		result.Reversal, err = transferTx(ctx, q, TransferTxParams{
+			FromAccountID: original.ToAccountID,
+			ToAccountID:   original.FromAccountID,
+			Amount:        toAmount,
+			ToAmount:      amount,
+			ExchangeRate:  rate,
+			ReversalOf:    sql.NullInt64{Int64: original.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Original, err = q.AddTransferRefund(ctx, AddTransferRefundParams{
+			Amount: amount,
+			ID:     original.ID,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, q, AuditActionReversal, "transfers", original.ID, original, result.Original)
+	})
+	if err != nil {
+		return ReverseTransferTxResult{}, err
+	}
+	return result, nil
+}
+
+// proportion is total*part/whole rounded down, without overflowing int64 on the way.
+func proportion(total, part, whole int64) int64 {
+	n := new(big.Int).Mul(big.NewInt(total), big.NewInt(part))
+	return n.Quo(n, big.NewInt(whole)).Int64()
+}
+
+// inverseRate turns the exchange rate of a transfer around for the way back.
+func inverseRate(rate string) (string, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return "", fmt.Errorf("invalid exchange rate %q", rate)
+	}
+	inverse := new(big.Rat).Inv(r).FloatString(10)
+	inverse = strings.TrimRight(strings.TrimRight(inverse, "0"), ".")
+	return inverse, nil
+}
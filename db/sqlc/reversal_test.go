@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createReversibleTransfer(t *testing.T, amount int64) (Transfer, Account, Account) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusCompleted, result.Transfer.Status)
+	return result.Transfer, result.FromAccount, result.ToAccount
+}
+
+func TestReverseTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	transfer, from, to := createReversibleTransfer(t, 100)
+
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: transfer.ID})
+	require.NoError(t, err)
+
+	require.Equal(t, TransferStatusReversed, result.Original.Status)
+	require.Equal(t, int64(100), result.Original.RefundedAmount)
+
+	reversal := result.Reversal
+	require.Equal(t, transfer.ID, reversal.Transfer.ReversalOf.Int64)
+	require.Equal(t, to.ID, reversal.Transfer.FromAccountID)
+	require.Equal(t, from.ID, reversal.Transfer.ToAccountID)
+	require.Equal(t, int64(-100), reversal.FromEntry.Amount)
+	require.Equal(t, int64(100), reversal.ToEntry.Amount)
+	require.Equal(t, from.Balance+100, reversal.ToAccount.Balance)
+	require.Equal(t, to.Balance-100, reversal.FromAccount.Balance)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: transfer.ID})
+	require.ErrorIs(t, err, ErrTransferNotReversible)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: reversal.Transfer.ID})
+	require.ErrorIs(t, err, ErrTransferNotReversible)
+}
+
+func TestPartialReverseTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	transfer, _, _ := createReversibleTransfer(t, 100)
+
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: transfer.ID, Amount: 30})
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusPartiallyReversed, result.Original.Status)
+	require.Equal(t, int64(30), result.Original.RefundedAmount)
+	require.Equal(t, int64(30), result.Reversal.Transfer.Amount)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: transfer.ID, Amount: 71})
+	require.ErrorIs(t, err, ErrRefundExceedsTransfer)
+
+	// no amount refunds what is left
+	result, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: transfer.ID})
+	require.NoError(t, err)
+	require.Equal(t, TransferStatusReversed, result.Original.Status)
+	require.Equal(t, int64(70), result.Reversal.Transfer.Amount)
+}
+
+func TestConcurrentReverseTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	transfer, from, to := createReversibleTransfer(t, 100)
+
+	n := 10
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: transfer.ID, Amount: 30})
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			require.ErrorIs(t, err, ErrRefundExceedsTransfer)
+			continue
+		}
+		succeeded++
+	}
+	require.Equal(t, 3, succeeded)
+
+	original, err := store.GetTransfer(context.Background(), transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(90), original.RefundedAmount)
+	require.Equal(t, TransferStatusPartiallyReversed, original.Status)
+
+	updatedFrom, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, from.Balance+90, updatedFrom.Balance)
+
+	updatedTo, err := store.GetAccount(context.Background(), to.ID)
+	require.NoError(t, err)
+	require.Equal(t, to.Balance-90, updatedTo.Balance)
+}
+
+func TestProportion(t *testing.T) {
+	// three refunds of a third add up to the whole destination amount
+	total := int64(0)
+	refunded := int64(0)
+	for i := 0; i < 3; i++ {
+		total += proportion(100, refunded+1, 3) - proportion(100, refunded, 3)
+		refunded++
+	}
+	require.Equal(t, int64(100), total)
+}
+
+func TestInverseRate(t *testing.T) {
+	rate, err := inverseRate("1")
+	require.NoError(t, err)
+	require.Equal(t, "1", rate)
+
+	rate, err = inverseRate("0.8")
+	require.NoError(t, err)
+	require.Equal(t, "1.25", rate)
+
+	_, err = inverseRate("0")
+	require.Error(t, err)
+}
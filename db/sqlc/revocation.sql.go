@@ -0,0 +1,107 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: revocation.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createRevokedToken = `-- name: CreateRevokedToken :exec
+INSERT INTO revoked_tokens (
+    id,
+    username,
+    expires_at
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (id) DO NOTHING
+`
+
+type CreateRevokedTokenParams struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (q *Queries) CreateRevokedToken(ctx context.Context, arg CreateRevokedTokenParams) error {
+	_, err := q.db.ExecContext(ctx, createRevokedToken, arg.ID, arg.Username, arg.ExpiresAt)
+	return err
+}
+
+const deleteExpiredRevokedTokens = `-- name: DeleteExpiredRevokedTokens :execrows
+DELETE FROM revoked_tokens
+WHERE expires_at < now()
+`
+
+func (q *Queries) DeleteExpiredRevokedTokens(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredRevokedTokens)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredUserRevocations = `-- name: DeleteExpiredUserRevocations :execrows
+DELETE FROM user_revocations
+WHERE expires_at < now()
+`
+
+func (q *Queries) DeleteExpiredUserRevocations(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredUserRevocations)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const isTokenRevoked = `-- name: IsTokenRevoked :one
+SELECT (
+    EXISTS (
+        SELECT 1 FROM revoked_tokens
+        WHERE revoked_tokens.id = $1
+    ) OR EXISTS (
+        SELECT 1 FROM user_revocations
+        WHERE user_revocations.username = $2
+        AND user_revocations.revoked_before >= $3
+    )
+)::boolean AS revoked
+`
+
+type IsTokenRevokedParams struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	IssuedAt time.Time `json:"issuedAt"`
+}
+
+func (q *Queries) IsTokenRevoked(ctx context.Context, arg IsTokenRevokedParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isTokenRevoked, arg.ID, arg.Username, arg.IssuedAt)
+	var revoked bool
+	err := row.Scan(&revoked)
+	return revoked, err
+}
+
+const upsertUserRevocation = `-- name: UpsertUserRevocation :exec
+INSERT INTO user_revocations (
+    username,
+    revoked_before,
+    expires_at
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (username) DO UPDATE
+SET revoked_before = EXCLUDED.revoked_before,
+    expires_at = EXCLUDED.expires_at
+`
+
+type UpsertUserRevocationParams struct {
+	Username      string    `json:"username"`
+	RevokedBefore time.Time `json:"revokedBefore"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+func (q *Queries) UpsertUserRevocation(ctx context.Context, arg UpsertUserRevocationParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserRevocation, arg.Username, arg.RevokedBefore, arg.ExpiresAt)
+	return err
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type RunScheduledTransferTxParams struct {
+	// NextRunAt tells when a scheduled transfer runs after its current run, ok is false
+	// when it has no more runs
+	NextRunAt func(scheduled ScheduledTransfer) (next time.Time, ok bool)
+}
+
+type RunScheduledTransferTxResult struct {
+	ScheduledTransfer ScheduledTransfer    `json:"scheduledTransfer"`
+	Run               ScheduledTransferRun `json:"run"`
+}
+
+// RunScheduledTransferTx runs the scheduled transfer that has been due the longest and
+// returns sql.ErrNoRows when none is due. The transfer, its run and the move to the next
+// run commit together while the row is locked, other schedulers skip the row instead of
+// waiting, so a run never happens twice. A transfer rejected by the accounts, e.g. for
+// insufficient funds or a limit (see IsRejected), is rolled back to a savepoint and
+// recorded as a failed run, the schedule goes on with its next run. Any other error is
+// returned and nothing is recorded, the same run is tried again on the next poll.
+func (store *SQLStore) RunScheduledTransferTx(ctx context.Context, args RunScheduledTransferTxParams) (RunScheduledTransferTxResult, error) {
+	var result RunScheduledTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		scheduled, err := q.ClaimDueScheduledTransfer(ctx)
+		if err != nil {
+			return err
+		}
+
+		run := CreateScheduledTransferRunParams{
+			ScheduledTransferID: scheduled.ID,
+			ScheduledFor:        scheduled.NextRunAt.Time,
+			Status:              ScheduledRunStatusSucceeded,
+		}
+
+		if _, err = q.db.ExecContext(ctx, "SAVEPOINT scheduled_transfer"); err != nil {
+			return err
+		}
+		transfer, transferErr := transferTx(ctx, q, TransferTxParams{
+			FromAccountID: scheduled.FromAccountID,
+			ToAccountID:   scheduled.ToAccountID,
+			Amount:        scheduled.Amount,
+		})
+		if transferErr != nil {
+			if !IsRejected(transferErr) {
+				return transferErr
+			}
+			if _, err = q.db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT scheduled_transfer"); err != nil {
+				return err
+			}
+			run.Status = ScheduledRunStatusFailed
+			run.Error = transferErr.Error()
+		} else {
+			run.TransferID = sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true}
+		}
+
+		result.Run, err = q.CreateScheduledTransferRun(ctx, run)
+		if err != nil {
+			return err
+		}
+
+		next, ok := args.NextRunAt(scheduled)
+		advance := AdvanceScheduledTransferParams{
+			ID:        scheduled.ID,
+			LastRunAt: scheduled.NextRunAt,
+			NextRunAt: sql.NullTime{Time: next, Valid: ok},
+			Status:    ScheduledTransferStatusActive,
+		}
+		if !ok {
+			advance.Status = ScheduledTransferStatusCompleted
+		}
+		result.ScheduledTransfer, err = q.AdvanceScheduledTransfer(ctx, advance)
+		return err
+	})
+	if err != nil {
+		return RunScheduledTransferTxResult{}, err
+	}
+	return result, nil
+}
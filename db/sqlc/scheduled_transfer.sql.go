@@ -0,0 +1,355 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: scheduled_transfer.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const advanceScheduledTransfer = `-- name: AdvanceScheduledTransfer :one
+UPDATE scheduled_transfers
+SET run_count = run_count + 1,
+    last_run_at = $1,
+    next_run_at = $2,
+    status = $3,
+    updated_at = now()
+WHERE id = $4
+RETURNING id, from_account_id, to_account_id, amount, schedule, start_at, next_run_at, status, run_count, last_run_at, created_at, updated_at
+`
+
+type AdvanceScheduledTransferParams struct {
+	LastRunAt sql.NullTime            `json:"lastRunAt"`
+	NextRunAt sql.NullTime            `json:"nextRunAt"`
+	Status    ScheduledTransferStatus `json:"status"`
+	ID        int64                   `json:"id"`
+}
+
+func (q *Queries) AdvanceScheduledTransfer(ctx context.Context, arg AdvanceScheduledTransferParams) (ScheduledTransfer, error) {
+	row := q.db.QueryRowContext(ctx, advanceScheduledTransfer,
+		arg.LastRunAt,
+		arg.NextRunAt,
+		arg.Status,
+		arg.ID,
+	)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Schedule,
+		&i.StartAt,
+		&i.NextRunAt,
+		&i.Status,
+		&i.RunCount,
+		&i.LastRunAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const cancelScheduledTransfer = `-- name: CancelScheduledTransfer :one
+UPDATE scheduled_transfers
+SET status = 'cancelled',
+    next_run_at = NULL,
+    updated_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING id, from_account_id, to_account_id, amount, schedule, start_at, next_run_at, status, run_count, last_run_at, created_at, updated_at
+`
+
+func (q *Queries) CancelScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error) {
+	row := q.db.QueryRowContext(ctx, cancelScheduledTransfer, id)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Schedule,
+		&i.StartAt,
+		&i.NextRunAt,
+		&i.Status,
+		&i.RunCount,
+		&i.LastRunAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const claimDueScheduledTransfer = `-- name: ClaimDueScheduledTransfer :one
+SELECT id, from_account_id, to_account_id, amount, schedule, start_at, next_run_at, status, run_count, last_run_at, created_at, updated_at FROM scheduled_transfers
+WHERE status = 'active' AND next_run_at <= now()
+ORDER BY next_run_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED
+`
+
+func (q *Queries) ClaimDueScheduledTransfer(ctx context.Context) (ScheduledTransfer, error) {
+	row := q.db.QueryRowContext(ctx, claimDueScheduledTransfer)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Schedule,
+		&i.StartAt,
+		&i.NextRunAt,
+		&i.Status,
+		&i.RunCount,
+		&i.LastRunAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createScheduledTransfer = `-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+    from_account_id,
+    to_account_id,
+    amount,
+    schedule,
+    start_at,
+    next_run_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, from_account_id, to_account_id, amount, schedule, start_at, next_run_at, status, run_count, last_run_at, created_at, updated_at
+`
+
+type CreateScheduledTransferParams struct {
+	FromAccountID int64        `json:"fromAccountID"`
+	ToAccountID   int64        `json:"toAccountID"`
+	Amount        int64        `json:"amount"`
+	Schedule      string       `json:"schedule"`
+	StartAt       time.Time    `json:"startAt"`
+	NextRunAt     sql.NullTime `json:"nextRunAt"`
+}
+
+func (q *Queries) CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error) {
+	row := q.db.QueryRowContext(ctx, createScheduledTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Schedule,
+		arg.StartAt,
+		arg.NextRunAt,
+	)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Schedule,
+		&i.StartAt,
+		&i.NextRunAt,
+		&i.Status,
+		&i.RunCount,
+		&i.LastRunAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createScheduledTransferRun = `-- name: CreateScheduledTransferRun :one
+INSERT INTO scheduled_transfer_runs (
+    scheduled_transfer_id,
+    scheduled_for,
+    status,
+    transfer_id,
+    error
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING id, scheduled_transfer_id, scheduled_for, status, transfer_id, error, created_at
+`
+
+type CreateScheduledTransferRunParams struct {
+	ScheduledTransferID int64              `json:"scheduledTransferID"`
+	ScheduledFor        time.Time          `json:"scheduledFor"`
+	Status              ScheduledRunStatus `json:"status"`
+	TransferID          sql.NullInt64      `json:"transferID"`
+	Error               string             `json:"error"`
+}
+
+func (q *Queries) CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error) {
+	row := q.db.QueryRowContext(ctx, createScheduledTransferRun,
+		arg.ScheduledTransferID,
+		arg.ScheduledFor,
+		arg.Status,
+		arg.TransferID,
+		arg.Error,
+	)
+	var i ScheduledTransferRun
+	err := row.Scan(
+		&i.ID,
+		&i.ScheduledTransferID,
+		&i.ScheduledFor,
+		&i.Status,
+		&i.TransferID,
+		&i.Error,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getScheduledTransfer = `-- name: GetScheduledTransfer :one
+SELECT id, from_account_id, to_account_id, amount, schedule, start_at, next_run_at, status, run_count, last_run_at, created_at, updated_at FROM scheduled_transfers
+WHERE id = $1
+`
+
+func (q *Queries) GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error) {
+	row := q.db.QueryRowContext(ctx, getScheduledTransfer, id)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Schedule,
+		&i.StartAt,
+		&i.NextRunAt,
+		&i.Status,
+		&i.RunCount,
+		&i.LastRunAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listScheduledTransferRuns = `-- name: ListScheduledTransferRuns :many
+SELECT id, scheduled_transfer_id, scheduled_for, status, transfer_id, error, created_at FROM scheduled_transfer_runs
+WHERE scheduled_transfer_id = $1
+ORDER BY id DESC
+LIMIT $2
+`
+
+type ListScheduledTransferRunsParams struct {
+	ScheduledTransferID int64 `json:"scheduledTransferID"`
+	Limit               int32 `json:"limit"`
+}
+
+func (q *Queries) ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error) {
+	rows, err := q.db.QueryContext(ctx, listScheduledTransferRuns, arg.ScheduledTransferID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ScheduledTransferRun{}
+	for rows.Next() {
+		var i ScheduledTransferRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.ScheduledTransferID,
+			&i.ScheduledFor,
+			&i.Status,
+			&i.TransferID,
+			&i.Error,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScheduledTransfers = `-- name: ListScheduledTransfers :many
+SELECT id, from_account_id, to_account_id, amount, schedule, start_at, next_run_at, status, run_count, last_run_at, created_at, updated_at FROM scheduled_transfers
+WHERE from_account_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListScheduledTransfers(ctx context.Context, fromAccountID int64) ([]ScheduledTransfer, error) {
+	rows, err := q.db.QueryContext(ctx, listScheduledTransfers, fromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ScheduledTransfer{}
+	for rows.Next() {
+		var i ScheduledTransfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.Schedule,
+			&i.StartAt,
+			&i.NextRunAt,
+			&i.Status,
+			&i.RunCount,
+			&i.LastRunAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateScheduledTransfer = `-- name: UpdateScheduledTransfer :one
+UPDATE scheduled_transfers
+SET amount = $1,
+    schedule = $2,
+    start_at = $3,
+    next_run_at = $4,
+    updated_at = now()
+WHERE id = $5 AND status = 'active'
+RETURNING id, from_account_id, to_account_id, amount, schedule, start_at, next_run_at, status, run_count, last_run_at, created_at, updated_at
+`
+
+type UpdateScheduledTransferParams struct {
+	Amount    int64        `json:"amount"`
+	Schedule  string       `json:"schedule"`
+	StartAt   time.Time    `json:"startAt"`
+	NextRunAt sql.NullTime `json:"nextRunAt"`
+	ID        int64        `json:"id"`
+}
+
+func (q *Queries) UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfer, error) {
+	row := q.db.QueryRowContext(ctx, updateScheduledTransfer,
+		arg.Amount,
+		arg.Schedule,
+		arg.StartAt,
+		arg.NextRunAt,
+		arg.ID,
+	)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Schedule,
+		&i.StartAt,
+		&i.NextRunAt,
+		&i.Status,
+		&i.RunCount,
+		&i.LastRunAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
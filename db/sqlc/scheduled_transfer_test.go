@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createDueScheduledTransfer(t *testing.T, from, to Account, amount int64) ScheduledTransfer {
+	due := time.Now().Add(-time.Minute)
+	scheduled, err := testQueries.CreateScheduledTransfer(context.Background(), CreateScheduledTransferParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        amount,
+		StartAt:       due,
+		NextRunAt:     sql.NullTime{Time: due, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusActive, scheduled.Status)
+	return scheduled
+}
+
+// runsOnce ends a one-off schedule after its run
+func runsOnce(scheduled ScheduledTransfer) (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func TestRunScheduledTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	scheduled := createDueScheduledTransfer(t, account1, account2, 10)
+
+	next := time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond)
+	result, err := store.RunScheduledTransferTx(context.Background(), RunScheduledTransferTxParams{
+		NextRunAt: func(s ScheduledTransfer) (time.Time, bool) {
+			require.Equal(t, scheduled.ID, s.ID)
+			return next, true
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledRunStatusSucceeded, result.Run.Status)
+	require.True(t, result.Run.TransferID.Valid)
+	require.Equal(t, ScheduledTransferStatusActive, result.ScheduledTransfer.Status)
+	require.Equal(t, int32(1), result.ScheduledTransfer.RunCount)
+	require.WithinDuration(t, next, result.ScheduledTransfer.NextRunAt.Time, time.Millisecond)
+
+	updated, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-10, updated.Balance)
+
+	// the next run is in an hour, nothing is due now
+	_, err = store.RunScheduledTransferTx(context.Background(), RunScheduledTransferTxParams{NextRunAt: runsOnce})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	runs, err := store.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduled.ID,
+		Limit:               10,
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+}
+
+func TestRunScheduledTransferTxFailure(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	scheduled := createDueScheduledTransfer(t, account1, account2, account1.Balance+1)
+
+	result, err := store.RunScheduledTransferTx(context.Background(), RunScheduledTransferTxParams{NextRunAt: runsOnce})
+	require.NoError(t, err)
+	require.Equal(t, scheduled.ID, result.ScheduledTransfer.ID)
+	require.Equal(t, ScheduledRunStatusFailed, result.Run.Status)
+	require.Contains(t, result.Run.Error, ErrInsufficientFunds.Error())
+	require.False(t, result.Run.TransferID.Valid)
+	require.Equal(t, ScheduledTransferStatusCompleted, result.ScheduledTransfer.Status)
+	require.False(t, result.ScheduledTransfer.NextRunAt.Valid)
+
+	updated, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updated.Balance)
+
+	// a finished schedule can't be changed anymore
+	_, err = store.CancelScheduledTransfer(context.Background(), scheduled.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRunScheduledTransferTxDatabaseError(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	scheduled := createDueScheduledTransfer(t, account1, account2, 10)
+
+	// another transaction holds the source account, the run gives up waiting for it
+	tx, err := testDB.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	_, err = tx.ExecContext(context.Background(), "SELECT id FROM accounts WHERE id = $1 FOR UPDATE", account1.ID)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = store.RunScheduledTransferTx(ctx, RunScheduledTransferTxParams{NextRunAt: runsOnce})
+	require.Error(t, err)
+	require.NoError(t, tx.Rollback())
+
+	// nothing was recorded, the same run is still due
+	runs, err := store.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduled.ID,
+		Limit:               10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, runs)
+
+	unchanged, err := store.GetScheduledTransfer(context.Background(), scheduled.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusActive, unchanged.Status)
+	require.WithinDuration(t, scheduled.NextRunAt.Time, unchanged.NextRunAt.Time, time.Millisecond)
+	require.Zero(t, unchanged.RunCount)
+
+	result, err := store.RunScheduledTransferTx(context.Background(), RunScheduledTransferTxParams{NextRunAt: runsOnce})
+	require.NoError(t, err)
+	require.Equal(t, scheduled.ID, result.ScheduledTransfer.ID)
+	require.Equal(t, ScheduledRunStatusSucceeded, result.Run.Status)
+	require.WithinDuration(t, scheduled.NextRunAt.Time, result.Run.ScheduledFor, time.Millisecond)
+}
+
+func TestRunScheduledTransferTxConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	scheduled := createDueScheduledTransfer(t, account1, account2, 10)
+
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.RunScheduledTransferTx(context.Background(), RunScheduledTransferTxParams{NextRunAt: runsOnce})
+			errs <- err
+		}()
+	}
+
+	ran := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			ran++
+			continue
+		}
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	}
+	require.Equal(t, 1, ran)
+
+	runs, err := store.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferID: scheduled.ID,
+		Limit:               10,
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+
+	updated, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-10, updated.Balance)
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAndGetSession(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := CreateSessionParams{
+		ID:           uuid.New(),
+		Username:     user.Username,
+		RefreshToken: util.RandomString(32),
+		UserAgent:    "banco-test",
+		ClientIp:     "127.0.0.1",
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+	session, err := testQueries.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, session.ID)
+	require.False(t, session.IsBlocked)
+
+	fetched, err := testQueries.GetSession(context.Background(), arg.ID)
+	require.NoError(t, err)
+	require.Equal(t, session.RefreshToken, fetched.RefreshToken)
+	require.WithinDuration(t, arg.ExpiresAt, fetched.ExpiresAt, time.Second)
+}
+
+func TestBlockUserSessions(t *testing.T) {
+	user := createRandomUser(t)
+
+	for i := 0; i < 2; i++ {
+		_, err := testQueries.CreateSession(context.Background(), CreateSessionParams{
+			ID:           uuid.New(),
+			Username:     user.Username,
+			RefreshToken: util.RandomString(32),
+			ExpiresAt:    time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+	}
+
+	blocked, err := testQueries.BlockUserSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), blocked)
+
+	blocked, err = testQueries.BlockUserSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Zero(t, blocked)
+}
+
+func TestIsTokenRevoked(t *testing.T) {
+	user := createRandomUser(t)
+	tokenID := uuid.New()
+	issuedAt := time.Now().Add(-time.Minute)
+
+	check := func(id uuid.UUID) bool {
+		revoked, err := testQueries.IsTokenRevoked(context.Background(), IsTokenRevokedParams{
+			ID:       id,
+			Username: user.Username,
+			IssuedAt: issuedAt,
+		})
+		require.NoError(t, err)
+		return revoked
+	}
+
+	require.False(t, check(tokenID))
+
+	err := testQueries.CreateRevokedToken(context.Background(), CreateRevokedTokenParams{
+		ID:        tokenID,
+		Username:  user.Username,
+		ExpiresAt: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.True(t, check(tokenID))
+	require.False(t, check(uuid.New()))
+
+	err = testQueries.UpsertUserRevocation(context.Background(), UpsertUserRevocationParams{
+		Username:      user.Username,
+		RevokedBefore: time.Now(),
+		ExpiresAt:     time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.True(t, check(uuid.New()))
+}
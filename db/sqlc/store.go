@@ -3,12 +3,59 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrIdempotencyKeyConflict = errors.New("idempotency key was already used with a different request")
+	ErrInsufficientFunds      = errors.New("insufficient funds")
+	ErrExchangeRateRequired   = errors.New("exchange rate and destination amount are required between different currencies")
+	ErrAccountFrozen          = errors.New("account is frozen")
+	ErrToAmountMismatch       = errors.New("destination amount must equal the amount between accounts of the same currency")
 )
 
+// IsRejected tells whether err comes from the state of the accounts involved rather than
+// from the database: the request was fine but the money can't move, trying it again
+// right away won't change that.
+func IsRejected(err error) bool {
+	return errors.Is(err, ErrInsufficientFunds) ||
+		errors.Is(err, ErrNoCashAccount) ||
+		errors.Is(err, ErrAccountFrozen) ||
+		errors.Is(err, ErrAccountClosed) ||
+		errors.Is(err, ErrTransferLimitExceeded) ||
+		errors.Is(err, ErrTransferNotReversible) ||
+		errors.Is(err, ErrRefundExceedsTransfer) ||
+		errors.Is(err, ErrRefundTooSmall) ||
+		errors.Is(err, ErrCaptureExceedsHold)
+}
+
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, args TransferTxParams) (TransferTxResult, error)
+	IdempotentTransferTx(ctx context.Context, args IdempotentTransferTxParams) (TransferTxResult, error)
+	Reconcile(ctx context.Context) (ReconciliationReport, error)
+	AdjustBalanceTx(ctx context.Context, args AdjustBalanceTxParams) (AdjustBalanceTxResult, error)
+	DepositTx(ctx context.Context, args CashTxParams) (CashTxResult, error)
+	WithdrawTx(ctx context.Context, args CashTxParams) (CashTxResult, error)
+	ChangeAccountStatusTx(ctx context.Context, args ChangeAccountStatusTxParams) (Account, error)
+	CreateAccountTx(ctx context.Context, args CreateAccountParams) (Account, error)
+	CreateUserTx(ctx context.Context, args CreateUserParams) (User, error)
+	CreateCurrencyTx(ctx context.Context, args CreateCurrencyParams) (Currency, error)
+	ProcessOutbox(ctx context.Context, args ProcessOutboxParams) (ProcessOutboxResult, error)
+	DeliverWebhooksTx(ctx context.Context, args DeliverWebhooksTxParams) (DeliverWebhooksTxResult, error)
+	RunScheduledTransferTx(ctx context.Context, args RunScheduledTransferTxParams) (RunScheduledTransferTxResult, error)
+	ReverseTransferTx(ctx context.Context, args ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	BatchTransferTx(ctx context.Context, args BatchTransferTxParams) (BatchTransferTxResult, error)
+	PlaceHold(ctx context.Context, args PlaceHoldParams) (HoldResult, error)
+	CaptureHold(ctx context.Context, args CaptureHoldParams) (CaptureHoldResult, error)
+	ReleaseHold(ctx context.Context, holdID int64) (HoldResult, error)
+	ExpireHolds(ctx context.Context, limit int32) (int, error)
 }
 
 type SQLStore struct {
@@ -32,18 +79,28 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	q := New(tx)
 	err = fn(q)
 	if err != nil {
+		settleAudit(ctx, false)
 		if rbErr := tx.Rollback(); rbErr != nil {
 			return fmt.Errorf("tx err: %v and rb err: %v", err, rbErr)
 		}
 		return err
 	}
-	return tx.Commit()
+	err = tx.Commit()
+	settleAudit(ctx, err == nil)
+	return err
 }
 
+// TransferTxParams moves Amount out of the source account and ToAmount into the destination
+// account. ToAmount and ExchangeRate default to Amount and 1, they must be set when the
+// accounts have different currencies.
 type TransferTxParams struct {
-	FromAccountID int64 `json:"fromAccountID"`
-	ToAccountID   int64 `json:"toAccountID"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64  `json:"fromAccountID"`
+	ToAccountID   int64  `json:"toAccountID"`
+	Amount        int64  `json:"amount"`
+	ToAmount      int64  `json:"toAmount"`
+	ExchangeRate  string `json:"exchangeRate"`
+	// ReversalOf links a reversal to the transfer it sends back
+	ReversalOf sql.NullInt64 `json:"reversalOf"`
 }
 
 type TransferTxResult struct {
@@ -52,6 +109,8 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"toAccount"`
 	FromEntry   Entry    `json:"fromEntry"`
 	ToEntry     Entry    `json:"toEntry"`
+	// cross currency transfers go through the system account of each currency
+	FxEntries []Entry `json:"fxEntries,omitempty"`
 }
 
 func (store *SQLStore) TransferTx(ctx context.Context, args TransferTxParams) (TransferTxResult, error) {
@@ -59,45 +118,233 @@ func (store *SQLStore) TransferTx(ctx context.Context, args TransferTxParams) (T
 
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams(args))
+		result, err = transferTx(ctx, q, args)
+		return err
+	})
+	if err != nil {
+		return TransferTxResult{}, err
+	}
+	return result, nil
+}
+
+type IdempotentTransferTxParams struct {
+	TransferTxParams
+	Username    string `json:"username"`
+	Key         string `json:"key"`
+	RequestHash string `json:"requestHash"`
+}
+
+// IdempotentTransferTx performs a transfer at most once per username and key.
+// The key is stored together with the transfer result in the same transaction,
+// so a replay returns the original result instead of moving money again.
+func (store *SQLStore) IdempotentTransferTx(ctx context.Context, args IdempotentTransferTxParams) (TransferTxResult, error) {
+	result, found, err := store.replayTransfer(ctx, args)
+	if err != nil || found {
+		return result, err
+	}
+
+	err = store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = transferTx(ctx, q, args.TransferTxParams)
 		if err != nil {
 			return err
 		}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: args.FromAccountID,
-			Amount:    -args.Amount,
-		})
+		response, err := json.Marshal(result)
 		if err != nil {
 			return err
 		}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: args.ToAccountID,
-			Amount:    args.Amount,
+		_, err = q.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+			Username:    args.Username,
+			Key:         args.Key,
+			RequestHash: args.RequestHash,
+			Response:    response,
 		})
-		if err != nil {
-			return err
+		return err
+	})
+	if err != nil {
+		// a concurrent request with the same key committed first, our transfer got rolled back
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+			result, _, err = store.replayTransfer(ctx, args)
+			return result, err
+		}
+		return TransferTxResult{}, err
+	}
+	return result, nil
+}
+
+func (store *SQLStore) replayTransfer(ctx context.Context, args IdempotentTransferTxParams) (result TransferTxResult, found bool, err error) {
+	key, err := store.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+		Username: args.Username,
+		Key:      args.Key,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return result, false, nil
+		}
+		return result, false, err
+	}
+
+	if key.RequestHash != args.RequestHash {
+		return result, true, ErrIdempotencyKeyConflict
+	}
+
+	err = json.Unmarshal(key.Response, &result)
+	return result, true, err
+}
+
+func transferTx(ctx context.Context, q *Queries, args TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	fromAccount, err := q.GetAccount(ctx, args.FromAccountID)
+	if err != nil {
+		return result, err
+	}
+	toAccount, err := q.GetAccount(ctx, args.ToAccountID)
+	if err != nil {
+		return result, err
+	}
+
+	crossCurrency := fromAccount.Currency != toAccount.Currency
+	switch {
+	case crossCurrency:
+		if args.ToAmount == 0 || args.ExchangeRate == "" {
+			return result, ErrExchangeRateRequired
 		}
+	case args.ToAmount != 0 && args.ToAmount != args.Amount:
+		return result, fmt.Errorf("%w: %d sent, %d received", ErrToAmountMismatch, args.Amount, args.ToAmount)
+	default:
+		args.ToAmount = args.Amount
+		args.ExchangeRate = "1"
+	}
+
+	lockIDs := []int64{fromAccount.ID, toAccount.ID}
+	var fromSystem, toSystem Account
+	if crossCurrency {
+		if fromSystem, err = systemCashAccount(ctx, q, fromAccount.Currency); err != nil {
+			return result, err
+		}
+		if toSystem, err = systemCashAccount(ctx, q, toAccount.Currency); err != nil {
+			return result, err
+		}
+		lockIDs = append(lockIDs, fromSystem.ID, toSystem.ID)
+	}
+
+	locked, err := lockAccounts(ctx, q, lockIDs...)
+	if err != nil {
+		return result, err
+	}
+	// a reversal only sends back money that already counted against the limits
+	return transferLocked(ctx, q, args, locked, fromSystem, toSystem, !args.ReversalOf.Valid)
+}
+
+// transferLocked makes a transfer between accounts the caller already locked, together
+// with the system accounts of both currencies when they differ. args must carry the
+// destination amount and rate. The transfer limits are only checked with limits set.
+func transferLocked(ctx context.Context, q *Queries, args TransferTxParams, locked map[int64]Account, fromSystem, toSystem Account, limits bool) (TransferTxResult, error) {
+	var result TransferTxResult
+	var err error
+
+	crossCurrency := locked[args.FromAccountID].Currency != locked[args.ToAccountID].Currency
+	if err := checkActive(locked[args.FromAccountID], locked[args.ToAccountID]); err != nil {
+		return result, err
+	}
+	if limits {
+		if err := checkTransferLimits(ctx, q, locked[args.FromAccountID], args.Amount, time.Now().UTC()); err != nil {
+			return result, err
+		}
+	}
+	if locked[args.FromAccountID].AvailableBalance() < args.Amount {
+		return result, ErrInsufficientFunds
+	}
+
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams(args))
+	if err != nil {
+		return result, err
+	}
+
+	transferID := sql.NullInt64{Int64: result.Transfer.ID, Valid: true}
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  args.FromAccountID,
+		Amount:     -args.Amount,
+		TransferID: transferID,
+		Kind:       EntryKindTransfer,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  args.ToAccountID,
+		Amount:     args.ToAmount,
+		TransferID: transferID,
+		Kind:       EntryKindTransfer,
+	})
+	if err != nil {
+		return result, err
+	}
 
+	if !crossCurrency {
 		if args.FromAccountID < args.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, args.FromAccountID, -args.Amount, args.ToAccountID, args.Amount)
-			if err != nil {
-				return err
-			}
+			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, args.FromAccountID, -args.Amount, args.ToAccountID, args.ToAmount)
 		} else {
-			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, args.ToAccountID, args.Amount, args.FromAccountID, -args.Amount)
-			if err != nil {
-				return err
-			}
+			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, args.ToAccountID, args.ToAmount, args.FromAccountID, -args.Amount)
+		}
+		if err != nil {
+			return result, err
 		}
+		return result, recordTransfer(ctx, q, locked, result)
+	}
 
-		return nil
-	})
+	// the source currency system account takes in what left the source account and the
+	// destination currency one pays out what the destination account receives
+	for _, leg := range []CreateEntryParams{
+		{AccountID: fromSystem.ID, Amount: args.Amount, TransferID: transferID, Kind: EntryKindTransfer},
+		{AccountID: toSystem.ID, Amount: -args.ToAmount, TransferID: transferID, Kind: EntryKindTransfer},
+	} {
+		entry, err := q.CreateEntry(ctx, leg)
+		if err != nil {
+			return result, err
+		}
+		result.FxEntries = append(result.FxEntries, entry)
+	}
+
+	// every account is already locked, so the update order doesn't matter here
+	balances := []AddAccountBalanceParams{
+		{Amount: -args.Amount, ID: args.FromAccountID},
+		{Amount: args.ToAmount, ID: args.ToAccountID},
+		{Amount: args.Amount, ID: fromSystem.ID},
+		{Amount: -args.ToAmount, ID: toSystem.ID},
+	}
+	updated := make([]Account, len(balances))
+	for i, balance := range balances {
+		if updated[i], err = q.AddAccountBalance(ctx, balance); err != nil {
+			return result, err
+		}
+	}
+	result.FromAccount, result.ToAccount = updated[0], updated[1]
+	return result, recordTransfer(ctx, q, locked, result)
+}
+
+// recordTransfer writes the audit event and the transfer.completed event of a transfer.
+func recordTransfer(ctx context.Context, q *Queries, before map[int64]Account, result TransferTxResult) error {
+	err := recordAudit(ctx, q, AuditActionTransfer, "transfers", result.Transfer.ID,
+		[]Account{before[result.FromAccount.ID], before[result.ToAccount.ID]},
+		result,
+	)
 	if err != nil {
-		return TransferTxResult{}, err
+		return err
 	}
-	return result, nil
+	return writeEvent(ctx, q, EventTransferCompleted, "transfers", strconv.FormatInt(result.Transfer.ID, 10), result.Transfer)
+}
+
+// AvailableBalance is how much can be taken out of the account right now, the money
+// reserved by active holds can't.
+func (account Account) AvailableBalance() int64 {
+	return account.Balance + account.OverdraftLimit - account.HeldBalance
 }
 
 func addMoney(ctx context.Context, q *Queries, accountID1, amount1, accountID2, amount2 int64) (account1, account2 Account, err error) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/RahilRehan/banco/db/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -57,6 +58,8 @@ func TestTransferTx(t *testing.T) {
 		require.NotEmpty(t, fromEntry)
 		require.Equal(t, account1.ID, fromEntry.AccountID)
 		require.Equal(t, -amount, fromEntry.Amount)
+		require.Equal(t, transfer.ID, fromEntry.TransferID.Int64)
+		require.Equal(t, EntryKindTransfer, fromEntry.Kind)
 		require.NotZero(t, fromEntry.CreatedAt)
 
 		_, err = store.GetEntry(context.Background(), fromEntry.ID)
@@ -67,6 +70,8 @@ func TestTransferTx(t *testing.T) {
 		require.NotEmpty(t, toEntry)
 		require.Equal(t, account2.ID, toEntry.AccountID)
 		require.Equal(t, amount, toEntry.Amount)
+		require.Equal(t, transfer.ID, toEntry.TransferID.Int64)
+		require.Equal(t, EntryKindTransfer, toEntry.Kind)
 		require.NotZero(t, toEntry.CreatedAt)
 
 		// test accounts
@@ -148,3 +153,233 @@ func TestTransferTxDeadLock(t *testing.T) {
 	require.Equal(t, account2.Balance, updatedToAccount.Balance)
 
 }
+
+func TestIdempotentTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	n := 5
+	amount := int64(10)
+	args := IdempotentTransferTxParams{
+		TransferTxParams: TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		},
+		Username:    account1.Owner,
+		Key:         util.RandomString(16),
+		RequestHash: util.RandomString(64),
+	}
+
+	errs := make(chan error)
+	results := make(chan TransferTxResult)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			result, err := store.IdempotentTransferTx(context.Background(), args)
+
+			errs <- err
+			results <- result
+		}()
+	}
+
+	var transferID int64
+	for i := 0; i < n; i++ {
+		err := <-errs
+		require.NoError(t, err)
+
+		result := <-results
+		require.NotZero(t, result.Transfer.ID)
+		if transferID == 0 {
+			transferID = result.Transfer.ID
+		}
+		require.Equal(t, transferID, result.Transfer.ID)
+	}
+
+	updatedFromAccount, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+
+	updatedToAccount, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+
+	require.Equal(t, account1.Balance-amount, updatedFromAccount.Balance)
+	require.Equal(t, account2.Balance+amount, updatedToAccount.Balance)
+
+	args.RequestHash = util.RandomString(64)
+	_, err = store.IdempotentTransferTx(context.Background(), args)
+	require.ErrorIs(t, err, ErrIdempotencyKeyConflict)
+}
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + 1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	overdraftLimit := int64(50)
+	_, err = store.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: overdraftLimit,
+	})
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance + overdraftLimit,
+	})
+	require.NoError(t, err)
+	require.Equal(t, -overdraftLimit, result.FromAccount.Balance)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        1,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
+
+func TestTransferTxFrozenAccount(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	_, err := store.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:     account2.ID,
+		Status: AccountStatusFrozen,
+	})
+	require.NoError(t, err)
+
+	for _, args := range []TransferTxParams{
+		{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10},
+		{FromAccountID: account2.ID, ToAccountID: account1.ID, Amount: 10},
+	} {
+		_, err = store.TransferTx(context.Background(), args)
+		require.ErrorIs(t, err, ErrAccountFrozen)
+	}
+
+	updated, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updated.Balance)
+}
+
+func TestTransferTxConcurrentInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	n := 5
+	amount := account1.Balance/int64(n-1) + 1
+
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        amount,
+			})
+			errs <- err
+		}()
+	}
+
+	var failed int
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			require.ErrorIs(t, err, ErrInsufficientFunds)
+			failed++
+		}
+	}
+	require.NotZero(t, failed)
+
+	updatedAccount, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, updatedAccount.Balance, int64(0))
+}
+
+func TestTransferTxSameCurrencyToAmount(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	// within one currency the destination can't receive more or less than was sent
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		ToAmount:      1000,
+		ExchangeRate:  "100",
+	})
+	require.ErrorIs(t, err, ErrToAmountMismatch)
+
+	updated, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, updated.Balance)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		ToAmount:      10,
+		ExchangeRate:  "100",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(10), result.Transfer.ToAmount)
+	require.Equal(t, "1", result.Transfer.ExchangeRate)
+	require.Equal(t, account2.Balance+10, result.ToAccount.Balance)
+}
+
+func TestTransferTxCrossCurrency(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, util.EUR)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.ErrorIs(t, err, ErrExchangeRateRequired)
+
+	usdCash, err := store.GetCashAccount(context.Background(), util.USD)
+	require.NoError(t, err)
+	eurCash, err := store.GetCashAccount(context.Background(), util.EUR)
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+		ToAmount:      92,
+		ExchangeRate:  "0.92",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(100), result.Transfer.Amount)
+	require.Equal(t, int64(92), result.Transfer.ToAmount)
+	require.Equal(t, account1.Balance-100, result.FromAccount.Balance)
+	require.Equal(t, int64(92), result.ToAccount.Balance)
+	require.Equal(t, int64(-100), result.FromEntry.Amount)
+	require.Equal(t, int64(92), result.ToEntry.Amount)
+
+	require.Len(t, result.FxEntries, 2)
+	require.Equal(t, usdCash.ID, result.FxEntries[0].AccountID)
+	require.Equal(t, int64(100), result.FxEntries[0].Amount)
+	require.Equal(t, eurCash.ID, result.FxEntries[1].AccountID)
+	require.Equal(t, int64(-92), result.FxEntries[1].Amount)
+
+	report, err := store.Reconcile(context.Background())
+	require.NoError(t, err)
+	for _, transfer := range report.UnbalancedTransfers {
+		require.NotEqual(t, result.Transfer.ID, transfer.TransferID)
+	}
+}
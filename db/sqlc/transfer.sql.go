@@ -5,26 +5,75 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
+const addTransferRefund = `-- name: AddTransferRefund :one
+UPDATE transfers
+SET refunded_amount = refunded_amount + $1,
+    status = CASE
+        WHEN refunded_amount + $1 = amount THEN 'reversed'::transfer_status
+        ELSE 'partially_reversed'::transfer_status
+    END
+WHERE id = $2
+RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, status, refunded_amount, reversal_of
+`
+
+type AddTransferRefundParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddTransferRefund(ctx context.Context, arg AddTransferRefundParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, addTransferRefund, arg.Amount, arg.ID)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.Status,
+		&i.RefundedAmount,
+		&i.ReversalOf,
+	)
+	return i, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
     from_account_id,
     to_account_id,
-    amount
+    amount,
+    to_amount,
+    exchange_rate,
+    reversal_of
 ) VALUES (
-    $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, status, refunded_amount, reversal_of
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"fromAccountID"`
-	ToAccountID   int64 `json:"toAccountID"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64         `json:"fromAccountID"`
+	ToAccountID   int64         `json:"toAccountID"`
+	Amount        int64         `json:"amount"`
+	ToAmount      int64         `json:"toAmount"`
+	ExchangeRate  string        `json:"exchangeRate"`
+	ReversalOf    sql.NullInt64 `json:"reversalOf"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ToAmount,
+		arg.ExchangeRate,
+		arg.ReversalOf,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -32,12 +81,17 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.Status,
+		&i.RefundedAmount,
+		&i.ReversalOf,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, status, refunded_amount, reversal_of FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -50,12 +104,113 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.Status,
+		&i.RefundedAmount,
+		&i.ReversalOf,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, status, refunded_amount, reversal_of FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.Status,
+		&i.RefundedAmount,
+		&i.ReversalOf,
 	)
 	return i, err
 }
 
+const listAccountTransfers = `-- name: ListAccountTransfers :many
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, status, refunded_amount, reversal_of FROM transfers
+WHERE
+    (
+        ($1::bool AND from_account_id = $2) OR
+        ($3::bool AND to_account_id = $2)
+    ) AND
+    created_at >= $4 AND
+    created_at < $5 AND
+    amount >= $6 AND
+    amount <= $7 AND
+    id < $8
+ORDER BY id DESC
+LIMIT $9
+`
+
+type ListAccountTransfersParams struct {
+	Outgoing  bool      `json:"outgoing"`
+	AccountID int64     `json:"accountID"`
+	Incoming  bool      `json:"incoming"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	MinAmount int64     `json:"minAmount"`
+	MaxAmount int64     `json:"maxAmount"`
+	Cursor    int64     `json:"cursor"`
+	PageSize  int32     `json:"pageSize"`
+}
+
+func (q *Queries) ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountTransfers,
+		arg.Outgoing,
+		arg.AccountID,
+		arg.Incoming,
+		arg.StartTime,
+		arg.EndTime,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.Cursor,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.Status,
+			&i.RefundedAmount,
+			&i.ReversalOf,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, status, refunded_amount, reversal_of FROM transfers
 WHERE
     from_account_id = $1 OR
     to_account_id = $2
@@ -91,6 +246,11 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.Status,
+			&i.RefundedAmount,
+			&i.ReversalOf,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrTransferLimitExceeded = errors.New("transfer limit exceeded")
+
+// names of the limits a transfer can hit
+const (
+	LimitPerTransaction     = "per_transaction"
+	LimitDaily              = "daily"
+	LimitMonthly            = "monthly"
+	LimitUserPerTransaction = "user_per_transaction"
+	LimitUserDaily          = "user_daily"
+	LimitUserMonthly        = "user_monthly"
+)
+
+// TransferLimitError tells which limit refused a transfer and how much could still be
+// sent under it. It matches ErrTransferLimitExceeded with errors.Is.
+type TransferLimitError struct {
+	Limit    string `json:"limit"`
+	Currency string `json:"currency"`
+	Max      int64  `json:"max"`
+	Used     int64  `json:"used"`
+	Headroom int64  `json:"headroom"`
+}
+
+func (e *TransferLimitError) Error() string {
+	return fmt.Sprintf("%s: %s limit is %d %s, %d %s left", ErrTransferLimitExceeded, e.Limit, e.Max, e.Currency, e.Headroom, e.Currency)
+}
+
+func (e *TransferLimitError) Is(target error) bool {
+	return target == ErrTransferLimitExceeded
+}
+
+// checkTransferLimits refuses a transfer of amount out of account when it would go over
+// the limits of the account or of its owner. The owner limits count every account of the
+// user in the currency, so closing an account and opening a new one does not reset them.
+// Money on hold counts as already sent in every period: limits are checked when a hold
+// is placed and not again when it is captured, so holds can't be used to go over them.
+// The caller holds the lock of the account, which keeps the account totals stable, the
+// owner row is locked here so no other transfer of the user changes the user totals in
+// between. It is taken after the account locks, like every other transfer does, so the
+// lock order stays the same.
+func checkTransferLimits(ctx context.Context, q *Queries, account Account, amount int64, now time.Time) error {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	limit, err := q.GetAccountTransferLimit(ctx, GetAccountTransferLimitParams{
+		Currency:  account.Currency,
+		AccountID: account.ID,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err == nil {
+		err = checkLimits(limit, amount, "", day, month, func(since time.Time) (int64, error) {
+			sent, err := q.SumAccountTransfersSince(ctx, SumAccountTransfersSinceParams{AccountID: account.ID, Since: since})
+			return sent + account.HeldBalance, err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	limit, err = q.GetUserTransferLimit(ctx, GetUserTransferLimitParams{
+		Owner:    account.Owner,
+		Currency: account.Currency,
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err = q.LockUser(ctx, account.Owner); err != nil {
+		return err
+	}
+	held, err := q.SumUserHeldBalance(ctx, SumUserHeldBalanceParams{Owner: account.Owner, Currency: account.Currency})
+	if err != nil {
+		return err
+	}
+	return checkLimits(limit, amount, "user_", day, month, func(since time.Time) (int64, error) {
+		sent, err := q.SumUserTransfersSince(ctx, SumUserTransfersSinceParams{Owner: account.Owner, Currency: account.Currency, Since: since})
+		return sent + held, err
+	})
+}
+
+// checkLimits checks amount against one transfer_limits row, sent returns what already
+// went out since a point in time. A limit of 0 is not checked.
+func checkLimits(limit TransferLimit, amount int64, prefix string, day, month time.Time, sent func(since time.Time) (int64, error)) error {
+	if limit.PerTransaction > 0 && amount > limit.PerTransaction {
+		return &TransferLimitError{
+			Limit:    prefix + LimitPerTransaction,
+			Currency: limit.Currency,
+			Max:      limit.PerTransaction,
+			Headroom: limit.PerTransaction,
+		}
+	}
+
+	for _, period := range []struct {
+		name  string
+		max   int64
+		since time.Time
+	}{
+		{LimitDaily, limit.Daily, day},
+		{LimitMonthly, limit.Monthly, month},
+	} {
+		if period.max == 0 {
+			continue
+		}
+		used, err := sent(period.since)
+		if err != nil {
+			return err
+		}
+		if used+amount > period.max {
+			headroom := period.max - used
+			if headroom < 0 {
+				headroom = 0
+			}
+			return &TransferLimitError{
+				Limit:    prefix + period.name,
+				Currency: limit.Currency,
+				Max:      period.max,
+				Used:     used,
+				Headroom: headroom,
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: transfer_limit.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const deleteTransferLimit = `-- name: DeleteTransferLimit :one
+DELETE FROM transfer_limits
+WHERE id = $1
+RETURNING id, currency, account_id, owner, per_transaction, daily, monthly, updated_at
+`
+
+func (q *Queries) DeleteTransferLimit(ctx context.Context, id int64) (TransferLimit, error) {
+	row := q.db.QueryRowContext(ctx, deleteTransferLimit, id)
+	var i TransferLimit
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.AccountID,
+		&i.Owner,
+		&i.PerTransaction,
+		&i.Daily,
+		&i.Monthly,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getAccountTransferLimit = `-- name: GetAccountTransferLimit :one
+SELECT id, currency, account_id, owner, per_transaction, daily, monthly, updated_at FROM transfer_limits
+WHERE owner IS NULL AND currency = $1 AND (account_id = $2::bigint OR account_id IS NULL)
+ORDER BY account_id NULLS LAST
+LIMIT 1
+`
+
+type GetAccountTransferLimitParams struct {
+	Currency  string `json:"currency"`
+	AccountID int64  `json:"accountID"`
+}
+
+// the limit of the account itself, or else the default of its currency
+func (q *Queries) GetAccountTransferLimit(ctx context.Context, arg GetAccountTransferLimitParams) (TransferLimit, error) {
+	row := q.db.QueryRowContext(ctx, getAccountTransferLimit, arg.Currency, arg.AccountID)
+	var i TransferLimit
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.AccountID,
+		&i.Owner,
+		&i.PerTransaction,
+		&i.Daily,
+		&i.Monthly,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserTransferLimit = `-- name: GetUserTransferLimit :one
+SELECT id, currency, account_id, owner, per_transaction, daily, monthly, updated_at FROM transfer_limits
+WHERE owner = $1::varchar AND currency = $2
+`
+
+type GetUserTransferLimitParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) GetUserTransferLimit(ctx context.Context, arg GetUserTransferLimitParams) (TransferLimit, error) {
+	row := q.db.QueryRowContext(ctx, getUserTransferLimit, arg.Owner, arg.Currency)
+	var i TransferLimit
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.AccountID,
+		&i.Owner,
+		&i.PerTransaction,
+		&i.Daily,
+		&i.Monthly,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listTransferLimits = `-- name: ListTransferLimits :many
+SELECT id, currency, account_id, owner, per_transaction, daily, monthly, updated_at FROM transfer_limits
+WHERE $1::varchar = '' OR currency = $1
+ORDER BY currency, account_id NULLS FIRST, owner NULLS FIRST
+`
+
+func (q *Queries) ListTransferLimits(ctx context.Context, currency string) ([]TransferLimit, error) {
+	rows, err := q.db.QueryContext(ctx, listTransferLimits, currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TransferLimit{}
+	for rows.Next() {
+		var i TransferLimit
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.AccountID,
+			&i.Owner,
+			&i.PerTransaction,
+			&i.Daily,
+			&i.Monthly,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUser = `-- name: LockUser :one
+SELECT username AS locked_username FROM users
+WHERE username = $1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) LockUser(ctx context.Context, username string) (string, error) {
+	row := q.db.QueryRowContext(ctx, lockUser, username)
+	var locked_username string
+	err := row.Scan(&locked_username)
+	return locked_username, err
+}
+
+const sumAccountTransfersSince = `-- name: SumAccountTransfersSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint FROM transfers
+WHERE from_account_id = $1 AND created_at >= $2 AND reversal_of IS NULL
+`
+
+type SumAccountTransfersSinceParams struct {
+	AccountID int64     `json:"accountID"`
+	Since     time.Time `json:"since"`
+}
+
+func (q *Queries) SumAccountTransfersSince(ctx context.Context, arg SumAccountTransfersSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumAccountTransfersSince, arg.AccountID, arg.Since)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const sumUserHeldBalance = `-- name: SumUserHeldBalance :one
+SELECT COALESCE(SUM(held_balance), 0)::bigint FROM accounts
+WHERE owner = $1 AND currency = $2
+`
+
+type SumUserHeldBalanceParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) SumUserHeldBalance(ctx context.Context, arg SumUserHeldBalanceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumUserHeldBalance, arg.Owner, arg.Currency)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const sumUserTransfersSince = `-- name: SumUserTransfersSince :one
+SELECT COALESCE(SUM(t.amount), 0)::bigint FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+WHERE a.owner = $1 AND a.currency = $2 AND t.created_at >= $3 AND t.reversal_of IS NULL
+`
+
+type SumUserTransfersSinceParams struct {
+	Owner    string    `json:"owner"`
+	Currency string    `json:"currency"`
+	Since    time.Time `json:"since"`
+}
+
+func (q *Queries) SumUserTransfersSince(ctx context.Context, arg SumUserTransfersSinceParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, sumUserTransfersSince, arg.Owner, arg.Currency, arg.Since)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const upsertTransferLimit = `-- name: UpsertTransferLimit :one
+INSERT INTO transfer_limits (
+    currency,
+    account_id,
+    owner,
+    per_transaction,
+    daily,
+    monthly
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (currency, (COALESCE(account_id, 0)), (COALESCE(owner, ''))) DO UPDATE
+SET per_transaction = EXCLUDED.per_transaction,
+    daily = EXCLUDED.daily,
+    monthly = EXCLUDED.monthly,
+    updated_at = now()
+RETURNING id, currency, account_id, owner, per_transaction, daily, monthly, updated_at
+`
+
+type UpsertTransferLimitParams struct {
+	Currency       string         `json:"currency"`
+	AccountID      sql.NullInt64  `json:"accountID"`
+	Owner          sql.NullString `json:"owner"`
+	PerTransaction int64          `json:"perTransaction"`
+	Daily          int64          `json:"daily"`
+	Monthly        int64          `json:"monthly"`
+}
+
+func (q *Queries) UpsertTransferLimit(ctx context.Context, arg UpsertTransferLimitParams) (TransferLimit, error) {
+	row := q.db.QueryRowContext(ctx, upsertTransferLimit,
+		arg.Currency,
+		arg.AccountID,
+		arg.Owner,
+		arg.PerTransaction,
+		arg.Daily,
+		arg.Monthly,
+	)
+	var i TransferLimit
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.AccountID,
+		&i.Owner,
+		&i.PerTransaction,
+		&i.Daily,
+		&i.Monthly,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,202 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/stretchr/testify/require"
+)
+
+// createLimitCurrency registers a currency of its own, so its default limits don't touch
+// the accounts of other tests.
+func createLimitCurrency(t *testing.T) string {
+	currency, err := testQueries.CreateCurrency(context.Background(), CreateCurrencyParams{
+		Code:     strings.ToUpper(util.RandomString(3)),
+		Exponent: 2,
+	})
+	require.NoError(t, err)
+	return currency.Code
+}
+
+func createLimitAccount(t *testing.T, owner, currency string, balance int64) Account {
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    owner,
+		Currency: currency,
+		Balance:  balance,
+	})
+	require.NoError(t, err)
+	return account
+}
+
+func requireLimitError(t *testing.T, err error, limit string, headroom int64) {
+	var limitErr *TransferLimitError
+	require.True(t, errors.As(err, &limitErr), "expected a limit error, got %v", err)
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+	require.Equal(t, limit, limitErr.Limit)
+	require.Equal(t, headroom, limitErr.Headroom)
+}
+
+func TestTransferTxLimits(t *testing.T) {
+	store := NewStore(testDB)
+	currency := createLimitCurrency(t)
+	account1 := createLimitAccount(t, createRandomUser(t).Username, currency, 1000)
+	account2 := createLimitAccount(t, createRandomUser(t).Username, currency, 0)
+
+	_, err := store.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		Currency:       currency,
+		PerTransaction: 50,
+		Daily:          100,
+	})
+	require.NoError(t, err)
+
+	transfer := func(amount int64) error {
+		_, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+		})
+		return err
+	}
+
+	requireLimitError(t, transfer(60), LimitPerTransaction, 50)
+	require.NoError(t, transfer(50))
+	require.NoError(t, transfer(40))
+	requireLimitError(t, transfer(20), LimitDaily, 10)
+
+	// the incoming side is not limited
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account2.ID,
+		ToAccountID:   account1.ID,
+		Amount:        50,
+	})
+	require.NoError(t, err)
+
+	// an account limit replaces the currency default
+	limit, err := store.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		Currency:  currency,
+		AccountID: sql.NullInt64{Int64: account1.ID, Valid: true},
+		Daily:     200,
+	})
+	require.NoError(t, err)
+	require.NoError(t, transfer(60))
+	requireLimitError(t, transfer(60), LimitDaily, 50)
+
+	// upserting the same scope updates the row
+	updated, err := store.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		Currency:  currency,
+		AccountID: sql.NullInt64{Int64: account1.ID, Valid: true},
+		Monthly:   160,
+	})
+	require.NoError(t, err)
+	require.Equal(t, limit.ID, updated.ID)
+	requireLimitError(t, transfer(20), LimitMonthly, 10)
+}
+
+func TestTransferTxUserLimit(t *testing.T) {
+	store := NewStore(testDB)
+	currency := createLimitCurrency(t)
+	user := createRandomUser(t)
+	account1 := createLimitAccount(t, user.Username, currency, 60)
+	other := createLimitAccount(t, createRandomUser(t).Username, currency, 0)
+
+	_, err := store.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		Currency: currency,
+		Owner:    sql.NullString{String: user.Username, Valid: true},
+		Daily:    100,
+	})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: account1.ID, ToAccountID: other.ID, Amount: 60})
+	require.NoError(t, err)
+
+	// a new account of the same user still counts what the closed one sent
+	_, err = store.ChangeAccountStatusTx(context.Background(), ChangeAccountStatusTxParams{AccountID: account1.ID, Status: AccountStatusClosed})
+	require.NoError(t, err)
+	account2 := createLimitAccount(t, user.Username, currency, 100)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: account2.ID, ToAccountID: other.ID, Amount: 50})
+	requireLimitError(t, err, LimitUserDaily, 40)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: account2.ID, ToAccountID: other.ID, Amount: 40})
+	require.NoError(t, err)
+}
+
+func TestTransferTxLimitsSkipReversals(t *testing.T) {
+	store := NewStore(testDB)
+	currency := createLimitCurrency(t)
+	user := createRandomUser(t)
+	customer := createLimitAccount(t, createRandomUser(t).Username, currency, 100)
+	merchant := createLimitAccount(t, user.Username, currency, 100)
+	supplier := createLimitAccount(t, createRandomUser(t).Username, currency, 0)
+
+	_, err := store.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		Currency:  currency,
+		AccountID: sql.NullInt64{Int64: merchant.ID, Valid: true},
+		Daily:     100,
+	})
+	require.NoError(t, err)
+	_, err = store.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		Currency: currency,
+		Owner:    sql.NullString{String: user.Username, Valid: true},
+		Daily:    100,
+	})
+	require.NoError(t, err)
+
+	first, err := store.TransferTx(context.Background(), TransferTxParams{FromAccountID: customer.ID, ToAccountID: merchant.ID, Amount: 60})
+	require.NoError(t, err)
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: customer.ID, ToAccountID: merchant.ID, Amount: 40})
+	require.NoError(t, err)
+
+	// the refund sent back doesn't use up the daily limits of the merchant
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: first.Transfer.ID})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: merchant.ID, ToAccountID: supplier.ID, Amount: 50})
+	require.NoError(t, err)
+}
+
+func TestTransferTxLimitsConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+	currency := createLimitCurrency(t)
+	account1 := createLimitAccount(t, createRandomUser(t).Username, currency, 1000)
+	account2 := createLimitAccount(t, createRandomUser(t).Username, currency, 0)
+
+	_, err := store.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		Currency:  currency,
+		AccountID: sql.NullInt64{Int64: account1.ID, Valid: true},
+		Daily:     100,
+	})
+	require.NoError(t, err)
+
+	n := 10
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        20,
+			})
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, ErrTransferLimitExceeded)
+	}
+	require.Equal(t, 5, succeeded)
+
+	updated, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(900), updated.Balance)
+}
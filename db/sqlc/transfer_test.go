@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -10,10 +11,13 @@ import (
 )
 
 func createRandomTransfer(t *testing.T, acc1 Account, acc2 Account) Transfer {
+	amount := util.RandomMoney()
 	params := CreateTransferParams{
 		FromAccountID: acc1.ID,
 		ToAccountID:   acc2.ID,
-		Amount:        util.RandomMoney(),
+		Amount:        amount,
+		ToAmount:      amount,
+		ExchangeRate:  "1",
 	}
 
 	transfer, err := testQueries.CreateTransfer(context.Background(), params)
@@ -22,6 +26,7 @@ func createRandomTransfer(t *testing.T, acc1 Account, acc2 Account) Transfer {
 	require.NotEmpty(t, transfer)
 
 	require.Equal(t, params.Amount, transfer.Amount)
+	require.Equal(t, params.ToAmount, transfer.ToAmount)
 	require.Equal(t, params.ToAccountID, transfer.ToAccountID)
 	require.Equal(t, params.FromAccountID, transfer.FromAccountID)
 
@@ -77,3 +82,53 @@ func TestListTransfers(t *testing.T) {
 		require.NotEmpty(t, transfer)
 	}
 }
+
+func TestListAccountTransfers(t *testing.T) {
+	acc1 := createRandomAccount(t)
+	acc2 := createRandomAccount(t)
+
+	for i := 0; i < 5; i++ {
+		createRandomTransfer(t, acc1, acc2)
+		createRandomTransfer(t, acc2, acc1)
+	}
+
+	params := ListAccountTransfersParams{
+		AccountID: acc1.ID,
+		Outgoing:  true,
+		Incoming:  true,
+		StartTime: time.Now().Add(-time.Minute),
+		EndTime:   time.Now().Add(time.Minute),
+		MinAmount: 0,
+		MaxAmount: math.MaxInt64,
+		Cursor:    math.MaxInt64,
+		PageSize:  20,
+	}
+
+	transfers, err := testQueries.ListAccountTransfers(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, transfers, 10)
+
+	for i := 1; i < len(transfers); i++ {
+		require.Greater(t, transfers[i-1].ID, transfers[i].ID)
+	}
+
+	params.Incoming = false
+	transfers, err = testQueries.ListAccountTransfers(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, transfers, 5)
+	for _, transfer := range transfers {
+		require.Equal(t, acc1.ID, transfer.FromAccountID)
+	}
+
+	params.Incoming = true
+	params.PageSize = 3
+	page1, err := testQueries.ListAccountTransfers(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, page1, 3)
+
+	params.Cursor = page1[len(page1)-1].ID
+	page2, err := testQueries.ListAccountTransfers(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, page2, 3)
+	require.Less(t, page2[0].ID, params.Cursor)
+}
@@ -27,6 +27,7 @@ func createRandomUser(t *testing.T) User {
 	require.Equal(t, arg.HashedPassword, user.HashedPassword)
 	require.Equal(t, arg.FullName, user.FullName)
 	require.Equal(t, arg.Email, user.Email)
+	require.Equal(t, UserRoleCustomer, user.Role)
 
 	require.NotZero(t, user.CreatedAt)
 	require.True(t, user.PasswordChangedAt.IsZero())
@@ -52,3 +53,15 @@ func TestGetUser(t *testing.T) {
 	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, time.Second)
 	require.WithinDuration(t, user1.PasswordChangedAt, user2.PasswordChangedAt, time.Second)
 }
+
+func TestUpdateUserRole(t *testing.T) {
+	user1 := createRandomUser(t)
+
+	user2, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: user1.Username,
+		Role:     UserRoleSupport,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user1.Username, user2.Username)
+	require.Equal(t, UserRoleSupport, user2.Role)
+}
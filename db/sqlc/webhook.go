@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// WebhookAttempt is the outcome of one POST of a delivery. StatusCode is 0 when no
+// response came back.
+type WebhookAttempt struct {
+	StatusCode int32
+	Err        error
+	Duration   time.Duration
+}
+
+type DeliverWebhooksTxParams struct {
+	Limit int32
+	// Lease is how long claimed deliveries are kept from other workers, it has to cover
+	// sending the whole batch. Deliveries not recorded by then are sent again.
+	Lease time.Duration
+	// MaxAttempts is how often a delivery is tried before it is marked failed
+	MaxAttempts int32
+	// Deliver POSTs a delivery to the url of its subscription
+	Deliver func(ctx context.Context, delivery ClaimWebhookDeliveriesRow) WebhookAttempt
+	// RetryAt tells when a delivery that failed is tried again
+	RetryAt func(delivery ClaimWebhookDeliveriesRow) time.Time
+}
+
+type DeliverWebhooksTxResult struct {
+	Succeeded int `json:"succeeded"`
+	Retried   int `json:"retried"`
+	Failed    int `json:"failed"`
+}
+
+// DeliverWebhooksTx sends a batch of due webhook deliveries and records every attempt.
+// The batch is claimed by pushing next_attempt_at past the lease in a statement of its
+// own, so no transaction or row lock is held while the receivers answer and several
+// workers never send the same delivery at once. Each outcome is then written in its
+// own short transaction.
+func (store *SQLStore) DeliverWebhooksTx(ctx context.Context, args DeliverWebhooksTxParams) (DeliverWebhooksTxResult, error) {
+	var result DeliverWebhooksTxResult
+
+	deliveries, err := store.ClaimWebhookDeliveries(ctx, ClaimWebhookDeliveriesParams{
+		LeaseSeconds: args.Lease.Seconds(),
+		LimitCount:   args.Limit,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	for _, delivery := range deliveries {
+		attempt := args.Deliver(ctx, delivery)
+
+		var lastError string
+		if attempt.Err != nil {
+			lastError = attempt.Err.Error()
+		}
+		update := UpdateWebhookDeliveryParams{
+			ID:             delivery.ID,
+			Status:         WebhookDeliveryStatusSucceeded,
+			LastStatusCode: attempt.StatusCode,
+			LastError:      lastError,
+			NextAttemptAt:  delivery.NextAttemptAt,
+		}
+		outcome := &result.Succeeded
+		switch {
+		case attempt.Err == nil:
+		case delivery.Attempts+1 >= args.MaxAttempts:
+			outcome = &result.Failed
+			update.Status = WebhookDeliveryStatusFailed
+		default:
+			outcome = &result.Retried
+			update.Status = WebhookDeliveryStatusPending
+			update.NextAttemptAt = args.RetryAt(delivery)
+		}
+
+		err = store.execTx(ctx, func(q *Queries) error {
+			_, err := q.CreateWebhookDeliveryAttempt(ctx, CreateWebhookDeliveryAttemptParams{
+				DeliveryID: delivery.ID,
+				StatusCode: attempt.StatusCode,
+				Error:      lastError,
+				DurationMs: attempt.Duration.Milliseconds(),
+			})
+			if err != nil {
+				return err
+			}
+			return q.UpdateWebhookDelivery(ctx, update)
+		})
+		if err != nil {
+			// the deliveries left are sent again once their lease runs out
+			return result, err
+		}
+		*outcome++
+	}
+	return result, nil
+}
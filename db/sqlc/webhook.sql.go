@@ -0,0 +1,453 @@
+// Code generated by sqlc. DO NOT EDIT.
+// source: webhook.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries d
+SET next_attempt_at = now() + make_interval(secs => $1::float8)
+FROM webhook_subscriptions s
+WHERE s.id = d.subscription_id AND d.id IN (
+    SELECT due.id FROM webhook_deliveries due
+    JOIN webhook_subscriptions sub ON sub.id = due.subscription_id
+    WHERE due.status = 'pending' AND due.next_attempt_at <= now() AND sub.active
+    ORDER BY due.id
+    LIMIT $2
+    FOR UPDATE OF due SKIP LOCKED
+)
+RETURNING d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.last_status_code, d.last_error, d.next_attempt_at, d.delivered_at, d.created_at, s.url, s.secret
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	LeaseSeconds float64 `json:"leaseSeconds"`
+	LimitCount   int32   `json:"limitCount"`
+}
+
+type ClaimWebhookDeliveriesRow struct {
+	ID             int64                 `json:"id"`
+	SubscriptionID int64                 `json:"subscriptionID"`
+	EventID        int64                 `json:"eventID"`
+	EventType      string                `json:"eventType"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int32                 `json:"attempts"`
+	LastStatusCode int32                 `json:"lastStatusCode"`
+	LastError      string                `json:"lastError"`
+	NextAttemptAt  time.Time             `json:"nextAttemptAt"`
+	DeliveredAt    sql.NullTime          `json:"deliveredAt"`
+	CreatedAt      time.Time             `json:"createdAt"`
+	Url            string                `json:"url"`
+	Secret         string                `json:"secret"`
+}
+
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]ClaimWebhookDeliveriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, claimWebhookDeliveries, arg.LeaseSeconds, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ClaimWebhookDeliveriesRow{}
+	for rows.Next() {
+		var i ClaimWebhookDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.LastStatusCode,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.DeliveredAt,
+			&i.CreatedAt,
+			&i.Url,
+			&i.Secret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createWebhookDeliveryAttempt = `-- name: CreateWebhookDeliveryAttempt :one
+INSERT INTO webhook_delivery_attempts (
+    delivery_id,
+    status_code,
+    error,
+    duration_ms
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING id, delivery_id, status_code, error, duration_ms, created_at
+`
+
+type CreateWebhookDeliveryAttemptParams struct {
+	DeliveryID int64  `json:"deliveryID"`
+	StatusCode int32  `json:"statusCode"`
+	Error      string `json:"error"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+func (q *Queries) CreateWebhookDeliveryAttempt(ctx context.Context, arg CreateWebhookDeliveryAttemptParams) (WebhookDeliveryAttempt, error) {
+	row := q.db.QueryRowContext(ctx, createWebhookDeliveryAttempt,
+		arg.DeliveryID,
+		arg.StatusCode,
+		arg.Error,
+		arg.DurationMs,
+	)
+	var i WebhookDeliveryAttempt
+	err := row.Scan(
+		&i.ID,
+		&i.DeliveryID,
+		&i.StatusCode,
+		&i.Error,
+		&i.DurationMs,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWebhookSubscription = `-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+    account_id,
+    url,
+    event_type,
+    secret
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING id, account_id, url, event_type, secret, active, created_at
+`
+
+type CreateWebhookSubscriptionParams struct {
+	AccountID int64  `json:"accountID"`
+	Url       string `json:"url"`
+	EventType string `json:"eventType"`
+	Secret    string `json:"secret"`
+}
+
+func (q *Queries) CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) (WebhookSubscription, error) {
+	row := q.db.QueryRowContext(ctx, createWebhookSubscription,
+		arg.AccountID,
+		arg.Url,
+		arg.EventType,
+		arg.Secret,
+	)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Url,
+		&i.EventType,
+		&i.Secret,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deactivateWebhookSubscription = `-- name: DeactivateWebhookSubscription :one
+UPDATE webhook_subscriptions
+SET active = false
+WHERE id = $1
+RETURNING id, account_id, url, event_type, secret, active, created_at
+`
+
+func (q *Queries) DeactivateWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error) {
+	row := q.db.QueryRowContext(ctx, deactivateWebhookSubscription, id)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Url,
+		&i.EventType,
+		&i.Secret,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const enqueueWebhookDeliveries = `-- name: EnqueueWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (
+    subscription_id,
+    event_id,
+    event_type,
+    payload
+)
+SELECT id, $1::bigint, $2::varchar, $3::jsonb
+FROM webhook_subscriptions
+WHERE account_id = ANY($4::bigint[]) AND event_type = $2::varchar AND active
+ON CONFLICT DO NOTHING
+`
+
+type EnqueueWebhookDeliveriesParams struct {
+	EventID    int64           `json:"eventID"`
+	EventType  string          `json:"eventType"`
+	Payload    json.RawMessage `json:"payload"`
+	AccountIds []int64         `json:"accountIds"`
+}
+
+func (q *Queries) EnqueueWebhookDeliveries(ctx context.Context, arg EnqueueWebhookDeliveriesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, enqueueWebhookDeliveries,
+		arg.EventID,
+		arg.EventType,
+		arg.Payload,
+		pq.Array(arg.AccountIds),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getWebhookDelivery = `-- name: GetWebhookDelivery :one
+SELECT id, subscription_id, event_id, event_type, payload, status, attempts, last_status_code, last_error, next_attempt_at, delivered_at, created_at FROM webhook_deliveries
+WHERE id = $1
+`
+
+func (q *Queries) GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookDelivery, id)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.LastStatusCode,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.DeliveredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getWebhookSubscription = `-- name: GetWebhookSubscription :one
+SELECT id, account_id, url, event_type, secret, active, created_at FROM webhook_subscriptions
+WHERE id = $1
+`
+
+func (q *Queries) GetWebhookSubscription(ctx context.Context, id int64) (WebhookSubscription, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookSubscription, id)
+	var i WebhookSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Url,
+		&i.EventType,
+		&i.Secret,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT id, subscription_id, event_id, event_type, payload, status, attempts, last_status_code, last_error, next_attempt_at, delivered_at, created_at FROM webhook_deliveries
+WHERE
+    subscription_id = $1 AND
+    ($2::varchar = '' OR status::varchar = $2)
+ORDER BY id DESC
+LIMIT $4
+OFFSET $3
+`
+
+type ListWebhookDeliveriesParams struct {
+	SubscriptionID int64  `json:"subscriptionID"`
+	Status         string `json:"status"`
+	OffsetCount    int32  `json:"offsetCount"`
+	LimitCount     int32  `json:"limitCount"`
+}
+
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookDeliveries,
+		arg.SubscriptionID,
+		arg.Status,
+		arg.OffsetCount,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WebhookDelivery{}
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.SubscriptionID,
+			&i.EventID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.LastStatusCode,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.DeliveredAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookDeliveryAttempts = `-- name: ListWebhookDeliveryAttempts :many
+SELECT id, delivery_id, status_code, error, duration_ms, created_at FROM webhook_delivery_attempts
+WHERE delivery_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListWebhookDeliveryAttempts(ctx context.Context, deliveryID int64) ([]WebhookDeliveryAttempt, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookDeliveryAttempts, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WebhookDeliveryAttempt{}
+	for rows.Next() {
+		var i WebhookDeliveryAttempt
+		if err := rows.Scan(
+			&i.ID,
+			&i.DeliveryID,
+			&i.StatusCode,
+			&i.Error,
+			&i.DurationMs,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookSubscriptions = `-- name: ListWebhookSubscriptions :many
+SELECT id, account_id, url, event_type, secret, active, created_at FROM webhook_subscriptions
+WHERE account_id = $1 AND active
+ORDER BY id
+`
+
+func (q *Queries) ListWebhookSubscriptions(ctx context.Context, accountID int64) ([]WebhookSubscription, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookSubscriptions, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WebhookSubscription{}
+	for rows.Next() {
+		var i WebhookSubscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Url,
+			&i.EventType,
+			&i.Secret,
+			&i.Active,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const replayWebhookDelivery = `-- name: ReplayWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = 'pending',
+    attempts = 0,
+    next_attempt_at = now()
+WHERE id = $1 AND status = 'failed'
+RETURNING id, subscription_id, event_id, event_type, payload, status, attempts, last_status_code, last_error, next_attempt_at, delivered_at, created_at
+`
+
+func (q *Queries) ReplayWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, replayWebhookDelivery, id)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.EventID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.LastStatusCode,
+		&i.LastError,
+		&i.NextAttemptAt,
+		&i.DeliveredAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateWebhookDelivery = `-- name: UpdateWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = $1,
+    attempts = attempts + 1,
+    last_status_code = $2,
+    last_error = $3,
+    next_attempt_at = $4,
+    delivered_at = CASE WHEN $1 = 'succeeded'::webhook_delivery_status THEN now() ELSE delivered_at END
+WHERE id = $5
+`
+
+type UpdateWebhookDeliveryParams struct {
+	Status         WebhookDeliveryStatus `json:"status"`
+	LastStatusCode int32                 `json:"lastStatusCode"`
+	LastError      string                `json:"lastError"`
+	NextAttemptAt  time.Time             `json:"nextAttemptAt"`
+	ID             int64                 `json:"id"`
+}
+
+func (q *Queries) UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDeliveryParams) error {
+	_, err := q.db.ExecContext(ctx, updateWebhookDelivery,
+		arg.Status,
+		arg.LastStatusCode,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.ID,
+	)
+	return err
+}
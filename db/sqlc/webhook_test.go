@@ -0,0 +1,171 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhookSubscription(t *testing.T, account Account, eventType string) WebhookSubscription {
+	subscription, err := testQueries.CreateWebhookSubscription(context.Background(), CreateWebhookSubscriptionParams{
+		AccountID: account.ID,
+		Url:       "https://example.com/hooks",
+		EventType: eventType,
+		Secret:    "whsec_test",
+	})
+	require.NoError(t, err)
+	require.True(t, subscription.Active)
+	return subscription
+}
+
+func TestEnqueueWebhookDeliveries(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	subscription := createRandomWebhookSubscription(t, account1, EventTransferCompleted)
+	createRandomWebhookSubscription(t, account1, EventAccountCreated)
+
+	event, err := testQueries.CreateOutboxEvent(context.Background(), CreateOutboxEventParams{
+		EventType:     EventTransferCompleted,
+		AggregateType: "transfers",
+		AggregateID:   "1",
+		Payload:       []byte(`{"id":1}`),
+	})
+	require.NoError(t, err)
+
+	args := EnqueueWebhookDeliveriesParams{
+		EventID:    event.ID,
+		EventType:  event.EventType,
+		Payload:    event.Payload,
+		AccountIds: []int64{account1.ID, account2.ID},
+	}
+	n, err := testQueries.EnqueueWebhookDeliveries(context.Background(), args)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	// publishing the event again does not deliver it twice
+	n, err = testQueries.EnqueueWebhookDeliveries(context.Background(), args)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	deliveries, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		SubscriptionID: subscription.ID,
+		Status:         string(WebhookDeliveryStatusPending),
+		LimitCount:     10,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, event.ID, deliveries[0].EventID)
+
+	// deactivated subscriptions get nothing
+	_, err = testQueries.DeactivateWebhookSubscription(context.Background(), subscription.ID)
+	require.NoError(t, err)
+	list, err := testQueries.ListWebhookSubscriptions(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, EventAccountCreated, list[0].EventType)
+}
+
+func TestDeliverWebhooksTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+	subscription := createRandomWebhookSubscription(t, account, EventTransferCompleted)
+
+	event, err := testQueries.CreateOutboxEvent(context.Background(), CreateOutboxEventParams{
+		EventType:     EventTransferCompleted,
+		AggregateType: "transfers",
+		AggregateID:   "1",
+		Payload:       []byte(`{"id":1}`),
+	})
+	require.NoError(t, err)
+	_, err = testQueries.EnqueueWebhookDeliveries(context.Background(), EnqueueWebhookDeliveriesParams{
+		EventID:    event.ID,
+		EventType:  event.EventType,
+		Payload:    event.Payload,
+		AccountIds: []int64{account.ID},
+	})
+	require.NoError(t, err)
+
+	// deliveries of inactive subscriptions are not claimed, this is the only one due
+	deliver := func(status int32, deliverErr error) func(ctx context.Context, delivery ClaimWebhookDeliveriesRow) WebhookAttempt {
+		return func(ctx context.Context, delivery ClaimWebhookDeliveriesRow) WebhookAttempt {
+			require.Equal(t, subscription.ID, delivery.SubscriptionID)
+			require.Equal(t, "https://example.com/hooks", delivery.Url)
+			require.Equal(t, "whsec_test", delivery.Secret)
+
+			// the claim is committed before sending, the delivery is leased but not locked
+			leased, err := testQueries.GetWebhookDelivery(ctx, delivery.ID)
+			require.NoError(t, err)
+			require.True(t, leased.NextAttemptAt.After(time.Now()))
+			return WebhookAttempt{StatusCode: status, Err: deliverErr, Duration: time.Millisecond}
+		}
+	}
+	retryAt := func(delivery ClaimWebhookDeliveriesRow) time.Time {
+		// due again right away so the test can take it once more
+		return time.Now().Add(-time.Second)
+	}
+
+	_, err = store.DeliverWebhooksTx(context.Background(), DeliverWebhooksTxParams{
+		Limit:       100,
+		Lease:       time.Minute,
+		MaxAttempts: 2,
+		Deliver:     deliver(http.StatusBadGateway, errors.New("unexpected status 502")),
+		RetryAt:     retryAt,
+	})
+	require.NoError(t, err)
+
+	deliveries, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{SubscriptionID: subscription.ID, LimitCount: 10})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, WebhookDeliveryStatusPending, deliveries[0].Status)
+	require.Equal(t, int32(1), deliveries[0].Attempts)
+	require.Equal(t, int32(http.StatusBadGateway), deliveries[0].LastStatusCode)
+
+	// the second failure uses up the attempts
+	_, err = store.DeliverWebhooksTx(context.Background(), DeliverWebhooksTxParams{
+		Limit:       100,
+		Lease:       time.Minute,
+		MaxAttempts: 2,
+		Deliver:     deliver(0, errors.New("connection refused")),
+		RetryAt:     retryAt,
+	})
+	require.NoError(t, err)
+
+	delivery, err := testQueries.GetWebhookDelivery(context.Background(), deliveries[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, WebhookDeliveryStatusFailed, delivery.Status)
+	require.Equal(t, "connection refused", delivery.LastError)
+
+	attempts, err := testQueries.ListWebhookDeliveryAttempts(context.Background(), delivery.ID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	require.Equal(t, int32(http.StatusBadGateway), attempts[0].StatusCode)
+	require.Equal(t, int32(0), attempts[1].StatusCode)
+
+	// a replay starts over and the next attempt succeeds
+	delivery, err = testQueries.ReplayWebhookDelivery(context.Background(), delivery.ID)
+	require.NoError(t, err)
+	require.Equal(t, WebhookDeliveryStatusPending, delivery.Status)
+	require.Zero(t, delivery.Attempts)
+
+	_, err = store.DeliverWebhooksTx(context.Background(), DeliverWebhooksTxParams{
+		Limit:       100,
+		Lease:       time.Minute,
+		MaxAttempts: 2,
+		Deliver:     deliver(http.StatusOK, nil),
+		RetryAt:     retryAt,
+	})
+	require.NoError(t, err)
+
+	delivery, err = testQueries.GetWebhookDelivery(context.Background(), delivery.ID)
+	require.NoError(t, err)
+	require.Equal(t, WebhookDeliveryStatusSucceeded, delivery.Status)
+	require.True(t, delivery.DeliveredAt.Valid)
+
+	// only failed deliveries can be replayed
+	_, err = testQueries.ReplayWebhookDelivery(context.Background(), delivery.ID)
+	require.Error(t, err)
+}
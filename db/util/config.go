@@ -7,17 +7,34 @@ import (
 )
 
 type Config struct {
-	DB_USER               string        `mapstructure:"DB_USER"`
-	DB_NAME               string        `mapstructure:"DB_NAME"`
-	DB_PORT               string        `mapstructure:"DB_PORT"`
-	DB_HOST               string        `mapstructure:"DB_HOST"`
-	DB_PASSWORD           string        `mapstructure:"DB_PASSWORD"`
-	MIGRATIONS_PATH       string        `mapstructure:"MIGRATIONS_PATH"`
-	DRIVER_NAME           string        `mapstructure:"DRIVER_NAME"`
-	SSL_MODE              string        `mapstructure:"SSL_MODE"`
-	TIMEOUT               string        `mapstructure:"TIMEOUT"`
-	SERVER_ADDRESS        string        `mapstructure:"SERVER_ADDRESS"`
-	ACCESS_TOKEN_DURATION time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	DB_USER                 string        `mapstructure:"DB_USER"`
+	DB_NAME                 string        `mapstructure:"DB_NAME"`
+	DB_PORT                 string        `mapstructure:"DB_PORT"`
+	DB_HOST                 string        `mapstructure:"DB_HOST"`
+	DB_PASSWORD             string        `mapstructure:"DB_PASSWORD"`
+	MIGRATIONS_PATH         string        `mapstructure:"MIGRATIONS_PATH"`
+	DRIVER_NAME             string        `mapstructure:"DRIVER_NAME"`
+	SSL_MODE                string        `mapstructure:"SSL_MODE"`
+	TIMEOUT                 string        `mapstructure:"TIMEOUT"`
+	SERVER_ADDRESS          string        `mapstructure:"SERVER_ADDRESS"`
+	TOKEN_TYPE              string        `mapstructure:"TOKEN_TYPE"`
+	TOKEN_SYMMETRIC_KEY     string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TOKEN_PRIVATE_KEY       string        `mapstructure:"TOKEN_PRIVATE_KEY"`
+	TOKEN_KEYS              []string      `mapstructure:"TOKEN_KEYS"`
+	TOKEN_ACTIVE_KEY_ID     string        `mapstructure:"TOKEN_ACTIVE_KEY_ID"`
+	TOKEN_KEYS_DIR          string        `mapstructure:"TOKEN_KEYS_DIR"`
+	ACCESS_TOKEN_DURATION   time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	REFRESH_TOKEN_DURATION  time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	REVOCATION_STORE        string        `mapstructure:"REVOCATION_STORE"`
+	FX_PROVIDER             string        `mapstructure:"FX_PROVIDER"`
+	FX_RATES_FILE           string        `mapstructure:"FX_RATES_FILE"`
+	FX_HTTP_URL             string        `mapstructure:"FX_HTTP_URL"`
+	OUTBOX_SINKS            []string      `mapstructure:"OUTBOX_SINKS"`
+	OUTBOX_WEBHOOK_URL      string        `mapstructure:"OUTBOX_WEBHOOK_URL"`
+	OUTBOX_POLL_INTERVAL    time.Duration `mapstructure:"OUTBOX_POLL_INTERVAL"`
+	WEBHOOK_POLL_INTERVAL   time.Duration `mapstructure:"WEBHOOK_POLL_INTERVAL"`
+	SCHEDULER_POLL_INTERVAL time.Duration `mapstructure:"SCHEDULER_POLL_INTERVAL"`
+	HOLD_SWEEP_INTERVAL     time.Duration `mapstructure:"HOLD_SWEEP_INTERVAL"`
 }
 
 func LoadConfig(path string) (cfg *Config, err error) {
@@ -26,6 +43,12 @@ func LoadConfig(path string) (cfg *Config, err error) {
 	viper.SetConfigType("env")
 
 	viper.AutomaticEnv()
+	// secrets only come from the environment, they are not listed in app.env
+	for _, key := range []string{"TOKEN_SYMMETRIC_KEY", "TOKEN_PRIVATE_KEY"} {
+		if err = viper.BindEnv(key); err != nil {
+			return
+		}
+	}
 	err = viper.ReadInConfig()
 	if err != nil {
 		return
@@ -1,15 +1,9 @@
 package util
 
+// currencies seeded by the currencies migration, the registry in the currencies
+// table is the source of truth for what is supported
 const (
 	USD = "USD"
 	EUR = "EUR"
 	CAD = "CAD"
 )
-
-func IsSupportedCurrency(currency string) bool {
-	switch currency {
-	case USD, EUR, CAD:
-		return true
-	}
-	return false
-}
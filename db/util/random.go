@@ -37,7 +37,7 @@ func RandomMoney() int64 {
 }
 
 func RandomCurrency() string {
-	currencies := []string{"USD", "RS", "CAD", "EUR"}
+	currencies := []string{USD, CAD, EUR}
 	n := len(currencies)
 	return currencies[rand.Intn(n)]
 }
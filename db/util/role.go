@@ -0,0 +1,8 @@
+package util
+
+// user roles, the same values as the user_role type of the users table
+const (
+	CustomerRole = "customer"
+	SupportRole  = "support"
+	AdminRole    = "admin"
+)
@@ -0,0 +1,73 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const httpProviderTimeout = 5 * time.Second
+
+// HTTPProvider asks a remote service for rates with GET {baseURL}/rates?from=USD&to=EUR
+// and expects a json body like {"rate": "0.92"}. Point it at an httptest server to fake it.
+type HTTPProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+type httpRateResponse struct {
+	Rate string `json:"rate"`
+}
+
+func (p *HTTPProvider) Rate(ctx context.Context, from, to string) (Rate, error) {
+	if from == to {
+		return identityRate(from), nil
+	}
+
+	query := url.Values{}
+	query.Set("from", from)
+	query.Set("to", to)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/rates?"+query.Encode(), nil)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	rsp, err := p.client.Do(req)
+	if err != nil {
+		return Rate{}, fmt.Errorf("cannot fetch exchange rate: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return Rate{}, fmt.Errorf("%w: %s/%s", ErrRateNotFound, from, to)
+	default:
+		return Rate{}, fmt.Errorf("cannot fetch exchange rate: unexpected status %d", rsp.StatusCode)
+	}
+
+	var body httpRateResponse
+	if err := json.NewDecoder(rsp.Body).Decode(&body); err != nil {
+		return Rate{}, fmt.Errorf("cannot decode exchange rate: %w", err)
+	}
+
+	if _, err := parseRate(body.Rate); err != nil {
+		return Rate{}, err
+	}
+	return Rate{From: from, To: to, Value: body.Rate}, nil
+}
+
+func NewHTTPProvider(baseURL string, client *http.Client) (RateProvider, error) {
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid rate provider url: %w", err)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: httpProviderTimeout}
+	}
+	return &HTTPProvider{baseURL: strings.TrimRight(baseURL, "/"), client: client}, nil
+}
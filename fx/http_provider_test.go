@@ -0,0 +1,45 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/rates", r.URL.Path)
+		switch r.URL.Query().Get("to") {
+		case "EUR":
+			w.Write([]byte(`{"rate": "0.92"}`))
+		case "CAD":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	provider, err := NewHTTPProvider(srv.URL+"/", srv.Client())
+	require.NoError(t, err)
+
+	rate, err := provider.Rate(context.Background(), "USD", "EUR")
+	require.NoError(t, err)
+	require.Equal(t, Rate{From: "USD", To: "EUR", Value: "0.92"}, rate)
+
+	_, err = provider.Rate(context.Background(), "USD", "CAD")
+	require.True(t, errors.Is(err, ErrRateNotFound))
+
+	_, err = provider.Rate(context.Background(), "USD", "RS")
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrRateNotFound))
+}
+
+func TestHTTPProviderInvalidURL(t *testing.T) {
+	_, err := NewHTTPProvider("not a url", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,27 @@
+package fx
+
+import (
+	"fmt"
+
+	"github.com/RahilRehan/banco/db/util"
+)
+
+const (
+	ProviderStatic = "static"
+	ProviderHTTP   = "http"
+)
+
+// NewRateProvider builds the RateProvider selected by FX_PROVIDER. Without a rates file
+// the static provider only knows that a currency converts 1:1 into itself.
+func NewRateProvider(cfg util.Config) (RateProvider, error) {
+	switch cfg.FX_PROVIDER {
+	case "", ProviderStatic:
+		if cfg.FX_RATES_FILE == "" {
+			return NewStaticProvider(nil)
+		}
+		return NewFileProvider(cfg.FX_RATES_FILE)
+	case ProviderHTTP:
+		return NewHTTPProvider(cfg.FX_HTTP_URL, nil)
+	}
+	return nil, fmt.Errorf("unsupported fx provider %q", cfg.FX_PROVIDER)
+}
@@ -0,0 +1,105 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const rateDecimals = 10
+
+var ErrRateNotFound = errors.New("exchange rate not found")
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+// RateProvider looks up the exchange rate between two currencies.
+type RateProvider interface {
+	Rate(ctx context.Context, from, to string) (Rate, error)
+}
+
+// Rate is the price of one unit of From in To. Value is a decimal string
+// so it can be stored in a numeric column without losing precision.
+type Rate struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value string `json:"value"`
+}
+
+// Convert converts an amount of From into To, rounding half away from zero.
+func (r Rate) Convert(amount int64) (int64, error) {
+	return r.ConvertMinor(amount, 0, 0)
+}
+
+// ConvertMinor converts an amount in minor units of From into minor units of To.
+// The exponents are the minor-unit digits of each currency, 2 for cents and 0 for yen.
+func (r Rate) ConvertMinor(amount int64, fromExponent, toExponent int32) (int64, error) {
+	value, err := parseRate(r.Value)
+	if err != nil {
+		return 0, err
+	}
+
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), value)
+	if shift := toExponent - fromExponent; shift != 0 {
+		scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+		if shift > 0 {
+			converted.Mul(converted, scale)
+		} else {
+			converted.Quo(converted, scale)
+		}
+	}
+	num, denom := converted.Num(), converted.Denom()
+
+	// round half away from zero: (2*num + sign*denom) / (2*denom), truncated
+	twice := new(big.Int).Mul(num, big.NewInt(2))
+	if num.Sign() < 0 {
+		twice.Sub(twice, denom)
+	} else {
+		twice.Add(twice, denom)
+	}
+	result := new(big.Int).Quo(twice, new(big.Int).Mul(denom, big.NewInt(2)))
+
+	if !result.IsInt64() {
+		return 0, fmt.Errorf("converted amount overflows: %s", result)
+	}
+	return result.Int64(), nil
+}
+
+// Inverse returns the rate for the opposite direction.
+func (r Rate) Inverse() (Rate, error) {
+	value, err := parseRate(r.Value)
+	if err != nil {
+		return Rate{}, err
+	}
+	return Rate{
+		From:  r.To,
+		To:    r.From,
+		Value: trimDecimal(new(big.Rat).Inv(value).FloatString(rateDecimals)),
+	}, nil
+}
+
+func identityRate(currency string) Rate {
+	return Rate{From: currency, To: currency, Value: "1"}
+}
+
+func parseRate(value string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(value)
+	if !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRate, value)
+	}
+	return rate, nil
+}
+
+func trimDecimal(value string) string {
+	if !strings.Contains(value, ".") {
+		return value
+	}
+	return strings.TrimRight(strings.TrimRight(value, "0"), ".")
+}
+
+func abs(n int32) int32 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,81 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateConvert(t *testing.T) {
+	testCases := map[string]struct {
+		value    string
+		amount   int64
+		expected int64
+	}{
+		"Identity":        {value: "1", amount: 150, expected: 150},
+		"Round down":      {value: "0.92", amount: 101, expected: 93},
+		"Round half up":   {value: "0.5", amount: 11, expected: 6},
+		"Negative amount": {value: "0.5", amount: -11, expected: -6},
+		"Greater than 1":  {value: "1.36", amount: 250, expected: 340},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			converted, err := Rate{From: "USD", To: "EUR", Value: test.value}.Convert(test.amount)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, converted)
+		})
+	}
+}
+
+func TestRateConvertMinor(t *testing.T) {
+	// 1 USD = 150 JPY, 12.34 USD is 1851 yen
+	converted, err := Rate{From: "USD", To: "JPY", Value: "150"}.ConvertMinor(1234, 2, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1851), converted)
+
+	// 1 JPY = 0.0067 USD, 1851 yen is 12.40 USD
+	converted, err = Rate{From: "JPY", To: "USD", Value: "0.0067"}.ConvertMinor(1851, 0, 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(1240), converted)
+}
+
+func TestRateConvertInvalid(t *testing.T) {
+	for _, value := range []string{"", "abc", "0", "-1"} {
+		_, err := Rate{Value: value}.Convert(100)
+		require.True(t, errors.Is(err, ErrInvalidRate), value)
+	}
+}
+
+func TestRateInverse(t *testing.T) {
+	rate, err := Rate{From: "USD", To: "EUR", Value: "0.5"}.Inverse()
+	require.NoError(t, err)
+	require.Equal(t, Rate{From: "EUR", To: "USD", Value: "2"}, rate)
+}
+
+func TestStaticProvider(t *testing.T) {
+	provider, err := NewStaticProvider(map[string]string{"usd/eur": "0.8"})
+	require.NoError(t, err)
+
+	rate, err := provider.Rate(context.Background(), "USD", "EUR")
+	require.NoError(t, err)
+	require.Equal(t, "0.8", rate.Value)
+
+	rate, err = provider.Rate(context.Background(), "EUR", "USD")
+	require.NoError(t, err)
+	require.Equal(t, "1.25", rate.Value)
+
+	rate, err = provider.Rate(context.Background(), "CAD", "CAD")
+	require.NoError(t, err)
+	require.Equal(t, "1", rate.Value)
+
+	_, err = provider.Rate(context.Background(), "USD", "CAD")
+	require.True(t, errors.Is(err, ErrRateNotFound))
+}
+
+func TestStaticProviderInvalidRate(t *testing.T) {
+	_, err := NewStaticProvider(map[string]string{"USD/EUR": "zero"})
+	require.True(t, errors.Is(err, ErrInvalidRate))
+}
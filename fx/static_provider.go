@@ -0,0 +1,64 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// StaticProvider serves rates from a fixed table keyed by "FROM/TO".
+// A missing direction is derived from the inverse rate when that one is known.
+type StaticProvider struct {
+	rates map[string]string
+}
+
+func (p *StaticProvider) Rate(ctx context.Context, from, to string) (Rate, error) {
+	if from == to {
+		return identityRate(from), nil
+	}
+
+	if value, ok := p.rates[pairKey(from, to)]; ok {
+		rate := Rate{From: from, To: to, Value: value}
+		if _, err := parseRate(value); err != nil {
+			return Rate{}, err
+		}
+		return rate, nil
+	}
+
+	if value, ok := p.rates[pairKey(to, from)]; ok {
+		return Rate{From: to, To: from, Value: value}.Inverse()
+	}
+
+	return Rate{}, fmt.Errorf("%w: %s/%s", ErrRateNotFound, from, to)
+}
+
+func NewStaticProvider(rates map[string]string) (RateProvider, error) {
+	normalized := make(map[string]string, len(rates))
+	for pair, value := range rates {
+		if _, err := parseRate(value); err != nil {
+			return nil, fmt.Errorf("rate %s: %w", pair, err)
+		}
+		normalized[strings.ToUpper(pair)] = value
+	}
+	return &StaticProvider{rates: normalized}, nil
+}
+
+// NewFileProvider loads a StaticProvider from a json file such as {"USD/EUR": "0.92"}.
+func NewFileProvider(path string) (RateProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read rates file: %w", err)
+	}
+
+	var rates map[string]string
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("cannot parse rates file: %w", err)
+	}
+	return NewStaticProvider(rates)
+}
+
+func pairKey(from, to string) string {
+	return from + "/" + to
+}
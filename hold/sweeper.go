@@ -0,0 +1,77 @@
+package hold
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+)
+
+const (
+	defaultInterval  = time.Minute
+	defaultBatchSize = 100
+	// Actor is who the audit events of expired holds are recorded for
+	Actor = "hold-sweeper"
+)
+
+// Store is the part of db.Store the sweeper needs.
+type Store interface {
+	ExpireHolds(ctx context.Context, limit int32) (int, error)
+}
+
+// Sweeper expires the holds that were neither captured nor released before their
+// deadline, giving the held money back to the available balance.
+type Sweeper struct {
+	store     Store
+	interval  time.Duration
+	batchSize int
+}
+
+func NewSweeper(store Store, interval time.Duration) *Sweeper {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Sweeper{
+		store:     store,
+		interval:  interval,
+		batchSize: defaultBatchSize,
+	}
+}
+
+// Run sweeps expired holds until the context is cancelled.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Sweep(ctx); err != nil {
+			log.Println("Cannot expire holds ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep expires every hold past its deadline, one batch per transaction, and returns
+// how many it expired.
+func (s *Sweeper) Sweep(ctx context.Context) (int, error) {
+	ctx = db.WithAuditContext(ctx, &db.AuditContext{Actor: Actor})
+
+	total := 0
+	for {
+		n, err := s.store.ExpireHolds(ctx, int32(s.batchSize))
+		total += n
+		if err != nil {
+			return total, err
+		}
+		// a full batch means more holds are probably expired
+		if n < s.batchSize {
+			return total, nil
+		}
+	}
+}
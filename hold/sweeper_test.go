@@ -0,0 +1,70 @@
+package hold
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHolds expires up to limit of its expired holds per call.
+type fakeHolds struct {
+	expired int
+	calls   int
+	err     error
+}
+
+func (h *fakeHolds) ExpireHolds(ctx context.Context, limit int32) (int, error) {
+	audit, ok := ctx.Value(db.AuditContextKey).(*db.AuditContext)
+	if !ok || audit.Actor != Actor {
+		return 0, errors.New("missing audit actor")
+	}
+
+	h.calls++
+	if h.err != nil {
+		return 0, h.err
+	}
+	n := h.expired
+	if n > int(limit) {
+		n = int(limit)
+	}
+	h.expired -= n
+	return n, nil
+}
+
+func TestSweep(t *testing.T) {
+	store := &fakeHolds{expired: 250}
+	sweeper := NewSweeper(store, 0)
+
+	n, err := sweeper.Sweep(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 250, n)
+	require.Equal(t, 3, store.calls)
+	require.Zero(t, store.expired)
+
+	n, err = sweeper.Sweep(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestSweepFullBatches(t *testing.T) {
+	store := &fakeHolds{expired: 2 * defaultBatchSize}
+	sweeper := NewSweeper(store, 0)
+
+	n, err := sweeper.Sweep(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2*defaultBatchSize, n)
+	// the last call finds nothing left
+	require.Equal(t, 3, store.calls)
+}
+
+func TestSweepError(t *testing.T) {
+	store := &fakeHolds{err: errors.New("connection refused")}
+	sweeper := NewSweeper(store, 0)
+
+	_, err := sweeper.Sweep(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 1, store.calls)
+}
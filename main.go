@@ -1,19 +1,34 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/RahilRehan/banco/api"
 	migration "github.com/RahilRehan/banco/db/migrations"
 	db "github.com/RahilRehan/banco/db/sqlc"
 	"github.com/RahilRehan/banco/db/util"
+	"github.com/RahilRehan/banco/hold"
+	"github.com/RahilRehan/banco/outbox"
+	"github.com/RahilRehan/banco/schedule"
+	"github.com/RahilRehan/banco/token"
+	"github.com/RahilRehan/banco/webhook"
 	_ "github.com/lib/pq"
 )
 
 func main() {
 
+	if len(os.Args) > 1 && os.Args[1] == "keygen" {
+		keygen()
+		return
+	}
+
 	cfg, err := util.LoadConfig(".")
 	if err != nil {
 		log.Fatalln("Cannot read config: ", err)
@@ -38,12 +53,93 @@ func main() {
 	}
 
 	store := db.NewStore(conn)
+
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		reconcile(store)
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "set-role" {
+		setRole(store, os.Args[2:])
+		return
+	}
+
 	server, err := api.NewServer(*cfg, store)
 	if err != nil {
 		log.Fatalln("Cannot start server ", err)
 	}
+
+	sinks, err := outbox.NewSinks(*cfg)
+	if err != nil {
+		log.Fatalln("Cannot create outbox sinks ", err)
+	}
+	// subscriptions always get their deliveries, whatever else is configured
+	sinks = append(sinks, webhook.NewFanoutSink(store))
+	go outbox.NewDispatcher(store, cfg.OUTBOX_POLL_INTERVAL, sinks...).Run(context.Background())
+	go webhook.NewDeliverer(store, nil, cfg.WEBHOOK_POLL_INTERVAL).Run(context.Background())
+	go schedule.NewRunner(store, cfg.SCHEDULER_POLL_INTERVAL).Run(context.Background())
+	go hold.NewSweeper(store, cfg.HOLD_SWEEP_INTERVAL).Run(context.Background())
+
+	// kill -HUP reloads the token signing keys
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			if err := server.ReloadTokenKeys(); err != nil {
+				log.Println("Cannot reload token keys ", err)
+				continue
+			}
+			log.Println("Token keys reloaded")
+		}
+	}()
+
 	err = server.Start(cfg.SERVER_ADDRESS)
 	if err != nil {
 		log.Fatalln("Cannot start server ", err)
 	}
 }
+
+// reconcile prints the ledger reconciliation report and exits non-zero when the ledger doesn't balance
+func reconcile(store db.Store) {
+	report, err := store.Reconcile(context.Background())
+	if err != nil {
+		log.Fatalln("Cannot reconcile ledger ", err)
+	}
+
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatalln("Cannot encode reconciliation report ", err)
+	}
+	fmt.Println(string(out))
+
+	if !report.Balanced {
+		os.Exit(1)
+	}
+}
+
+// setRole gives a user a role, it is how the first admin is made
+func setRole(store db.Store, args []string) {
+	if len(args) != 2 {
+		log.Fatalln("Usage: banco set-role <username> <customer|support|admin>")
+	}
+
+	user, err := store.UpdateUserRole(context.Background(), db.UpdateUserRoleParams{
+		Username: args[0],
+		Role:     db.UserRole(args[1]),
+	})
+	if err != nil {
+		log.Fatalln("Cannot set role ", err)
+	}
+	fmt.Printf("%s is now %s\n", user.Username, user.Role)
+}
+
+// keygen prints a new Ed25519 key pair for v2.public tokens, the private key goes into
+// TOKEN_PRIVATE_KEY and the public key to the services that only verify tokens
+func keygen() {
+	privateKey, publicKey, err := token.GenerateEd25519Keys()
+	if err != nil {
+		log.Fatalln("Cannot generate keys ", err)
+	}
+	fmt.Printf("TOKEN_PRIVATE_KEY=%s\n", privateKey)
+	fmt.Printf("TOKEN_PUBLIC_KEY=%s\n", publicKey)
+}
@@ -0,0 +1,121 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+)
+
+const (
+	defaultInterval    = time.Second
+	defaultBatchSize   = 100
+	retryBaseDelay     = time.Second
+	maxRetryDelay      = 10 * time.Minute
+	defaultSinkTimeout = 5 * time.Second
+	leaseMargin        = time.Minute
+)
+
+// Processor is the part of db.Store the dispatcher needs.
+type Processor interface {
+	ProcessOutbox(ctx context.Context, args db.ProcessOutboxParams) (db.ProcessOutboxResult, error)
+}
+
+// Dispatcher polls the outbox and delivers the pending events to every sink. An event is
+// published once all sinks took it, when one fails the event is retried on all of them
+// with an exponential backoff, so delivery is at least once.
+type Dispatcher struct {
+	store     Processor
+	sinks     []Sink
+	interval  time.Duration
+	batchSize int32
+	// sinkTimeout bounds every Publish of a sink
+	sinkTimeout time.Duration
+	now         func() time.Time
+}
+
+func NewDispatcher(store Processor, interval time.Duration, sinks ...Sink) *Dispatcher {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Dispatcher{
+		store:       store,
+		sinks:       sinks,
+		interval:    interval,
+		batchSize:   defaultBatchSize,
+		sinkTimeout: defaultSinkTimeout,
+		now:         time.Now,
+	}
+}
+
+// Run dispatches until the context is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		// a full batch means more events are probably waiting
+		for {
+			result, err := d.DispatchOnce(ctx)
+			if err != nil {
+				log.Println("Cannot dispatch outbox events ", err)
+				break
+			}
+			if result.Published+result.Failed < int(d.batchSize) {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce delivers one batch of due events.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (db.ProcessOutboxResult, error) {
+	return d.store.ProcessOutbox(ctx, db.ProcessOutboxParams{
+		Limit: d.batchSize,
+		// every sink may take the whole timeout for every event of the batch
+		Lease:   time.Duration(d.batchSize)*time.Duration(len(d.sinks))*d.sinkTimeout + leaseMargin,
+		Publish: d.publish,
+		RetryAt: d.retryAt,
+	})
+}
+
+func (d *Dispatcher) publish(ctx context.Context, event db.OutboxEvent) error {
+	var failures []string
+	for _, sink := range d.sinks {
+		if err := d.publishTo(ctx, sink, event); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sink.Name(), err))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("cannot publish event %d to %s", event.ID, strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// publishTo gives a sink d.sinkTimeout to take the event, so a stuck sink fails the event
+// instead of holding up the batch.
+func (d *Dispatcher) publishTo(ctx context.Context, sink Sink, event db.OutboxEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, d.sinkTimeout)
+	defer cancel()
+	return sink.Publish(ctx, event)
+}
+
+// retryAt doubles the delay with every failed attempt, up to maxRetryDelay.
+func (d *Dispatcher) retryAt(event db.OutboxEvent) time.Time {
+	delay := maxRetryDelay
+	if event.Attempts < 20 {
+		if backoff := retryBaseDelay << uint(event.Attempts); backoff < maxRetryDelay {
+			delay = backoff
+		}
+	}
+	return d.now().Add(delay)
+}
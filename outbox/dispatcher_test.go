@@ -0,0 +1,116 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOutbox processes its events the way db.Store does, without the locking.
+type fakeOutbox struct {
+	events []db.OutboxEvent
+}
+
+func (o *fakeOutbox) ProcessOutbox(ctx context.Context, args db.ProcessOutboxParams) (db.ProcessOutboxResult, error) {
+	var result db.ProcessOutboxResult
+	for i := range o.events {
+		event := &o.events[i]
+		if event.PublishedAt.Valid || int(args.Limit) == result.Published+result.Failed {
+			continue
+		}
+		if err := args.Publish(ctx, *event); err != nil {
+			event.LastError = err.Error()
+			event.NextAttemptAt = args.RetryAt(*event)
+			result.Failed++
+		} else {
+			event.PublishedAt.Valid = true
+			result.Published++
+		}
+		event.Attempts++
+	}
+	return result, nil
+}
+
+type failingSink struct {
+	failures int
+	calls    int
+}
+
+func (s *failingSink) Name() string {
+	return "failing"
+}
+
+func (s *failingSink) Publish(ctx context.Context, event db.OutboxEvent) error {
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("sink is down")
+	}
+	return nil
+}
+
+func TestDispatcher(t *testing.T) {
+	store := &fakeOutbox{events: []db.OutboxEvent{
+		{ID: 1, EventType: db.EventAccountCreated},
+		{ID: 2, EventType: db.EventTransferCompleted},
+	}}
+	channel := NewChannelSink(10)
+	flaky := &failingSink{failures: 1}
+
+	dispatcher := NewDispatcher(store, time.Second, LogSink{}, channel, flaky)
+
+	result, err := dispatcher.DispatchOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, db.ProcessOutboxResult{Published: 1, Failed: 1}, result)
+	require.Contains(t, store.events[0].LastError, "failing: sink is down")
+	require.True(t, store.events[1].PublishedAt.Valid)
+
+	// the failed event goes to every sink again, the ones that took it see it twice
+	result, err = dispatcher.DispatchOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, db.ProcessOutboxResult{Published: 1}, result)
+
+	var delivered []int64
+	for len(channel.Events()) > 0 {
+		delivered = append(delivered, (<-channel.Events()).ID)
+	}
+	require.Equal(t, []int64{1, 2, 1}, delivered)
+}
+
+func TestDispatcherSinkTimeout(t *testing.T) {
+	store := &fakeOutbox{events: []db.OutboxEvent{{ID: 1, EventType: db.EventAccountCreated}}}
+	// nobody reads the channel, so Publish blocks until the timeout
+	stuck := NewChannelSink(0)
+
+	dispatcher := NewDispatcher(store, time.Second, stuck)
+	dispatcher.sinkTimeout = 10 * time.Millisecond
+
+	result, err := dispatcher.DispatchOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, db.ProcessOutboxResult{Failed: 1}, result)
+	require.Contains(t, store.events[0].LastError, context.DeadlineExceeded.Error())
+}
+
+func TestDispatcherRetryAt(t *testing.T) {
+	now := time.Now()
+	dispatcher := NewDispatcher(&fakeOutbox{}, 0)
+	dispatcher.now = func() time.Time { return now }
+
+	require.Equal(t, now.Add(2*time.Second), dispatcher.retryAt(db.OutboxEvent{Attempts: 1}))
+	require.Equal(t, now.Add(8*time.Second), dispatcher.retryAt(db.OutboxEvent{Attempts: 3}))
+	require.Equal(t, now.Add(maxRetryDelay), dispatcher.retryAt(db.OutboxEvent{Attempts: 15}))
+	require.Equal(t, now.Add(maxRetryDelay), dispatcher.retryAt(db.OutboxEvent{Attempts: 100}))
+}
+
+func TestChannelSinkCancelled(t *testing.T) {
+	sink := NewChannelSink(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := sink.Publish(ctx, db.OutboxEvent{ID: 1})
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,33 @@
+package outbox
+
+import (
+	"fmt"
+
+	"github.com/RahilRehan/banco/db/util"
+)
+
+const (
+	SinkLog     = "log"
+	SinkWebhook = "webhook"
+)
+
+// NewSinks builds the sinks listed in OUTBOX_SINKS, the channel sink has to be wired in
+// code because its consumers live in the same process.
+func NewSinks(cfg util.Config) ([]Sink, error) {
+	var sinks []Sink
+	for _, name := range cfg.OUTBOX_SINKS {
+		switch name {
+		case SinkLog:
+			sinks = append(sinks, LogSink{})
+		case SinkWebhook:
+			sink, err := NewWebhookSink(cfg.OUTBOX_WEBHOOK_URL, nil)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		default:
+			return nil, fmt.Errorf("unsupported outbox sink %q", name)
+		}
+	}
+	return sinks, nil
+}
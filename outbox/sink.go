@@ -0,0 +1,56 @@
+package outbox
+
+import (
+	"context"
+	"log"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+)
+
+// Sink is where the dispatcher delivers outbox events. Delivery is at least once, a sink
+// can see the same event again and should use its ID to drop duplicates.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event db.OutboxEvent) error
+}
+
+// LogSink writes every event to the standard logger.
+type LogSink struct{}
+
+func (LogSink) Name() string {
+	return SinkLog
+}
+
+func (LogSink) Publish(ctx context.Context, event db.OutboxEvent) error {
+	log.Printf("outbox event %d %s %s/%s: %s", event.ID, event.EventType, event.AggregateType, event.AggregateID, event.Payload)
+	return nil
+}
+
+// ChannelSink hands events to consumers in the same process.
+type ChannelSink struct {
+	events chan db.OutboxEvent
+}
+
+func NewChannelSink(buffer int) *ChannelSink {
+	return &ChannelSink{events: make(chan db.OutboxEvent, buffer)}
+}
+
+func (s *ChannelSink) Name() string {
+	return "channel"
+}
+
+// Publish waits until the event is taken, the buffer has room or the context is done,
+// the dispatcher gives every sink a timeout.
+func (s *ChannelSink) Publish(ctx context.Context, event db.OutboxEvent) error {
+	select {
+	case s.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Events is where the consumers read the events from.
+func (s *ChannelSink) Events() <-chan db.OutboxEvent {
+	return s.events
+}
@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+)
+
+const webhookSinkTimeout = 5 * time.Second
+
+// WebhookSink POSTs every event as json to a fixed url, any status other than 2xx
+// leaves the event pending. Point it at an httptest server to fake it.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(webhookURL string, client *http.Client) (*WebhookSink, error) {
+	if _, err := url.ParseRequestURI(webhookURL); err != nil {
+		return nil, fmt.Errorf("invalid outbox webhook url: %w", err)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: webhookSinkTimeout}
+	}
+	return &WebhookSink{url: webhookURL, client: client}, nil
+}
+
+func (s *WebhookSink) Name() string {
+	return SinkWebhook
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, event db.OutboxEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", rsp.StatusCode)
+	}
+	return nil
+}
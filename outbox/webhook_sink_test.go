@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSink(t *testing.T) {
+	var received db.OutboxEvent
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sink, err := NewWebhookSink(srv.URL+"/events", srv.Client())
+	require.NoError(t, err)
+
+	event := db.OutboxEvent{
+		ID:            7,
+		EventType:     db.EventTransferCompleted,
+		AggregateType: "transfers",
+		AggregateID:   "3",
+		Payload:       json.RawMessage(`{"amount":10}`),
+	}
+	require.NoError(t, sink.Publish(context.Background(), event))
+	require.Equal(t, event.ID, received.ID)
+	require.Equal(t, event.EventType, received.EventType)
+	require.JSONEq(t, string(event.Payload), string(received.Payload))
+
+	status = http.StatusServiceUnavailable
+	require.Error(t, sink.Publish(context.Background(), event))
+}
+
+func TestNewSinks(t *testing.T) {
+	sinks, err := NewSinks(util.Config{
+		OUTBOX_SINKS:       []string{SinkLog, SinkWebhook},
+		OUTBOX_WEBHOOK_URL: "http://localhost:9000/events",
+	})
+	require.NoError(t, err)
+	require.Len(t, sinks, 2)
+	require.Equal(t, SinkLog, sinks[0].Name())
+	require.Equal(t, SinkWebhook, sinks[1].Name())
+
+	_, err = NewSinks(util.Config{OUTBOX_SINKS: []string{SinkWebhook}})
+	require.Error(t, err)
+
+	_, err = NewSinks(util.Config{OUTBOX_SINKS: []string{"kafka"}})
+	require.Error(t, err)
+}
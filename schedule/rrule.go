@@ -0,0 +1,219 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// rrule is the part of an RFC 5545 recurrence rule that standing orders need: FREQ of
+// DAILY, WEEKLY or MONTHLY, INTERVAL, BYDAY without ordinals, BYMONTHDAY (negative
+// days count from the end of the month), a single BYHOUR and BYMINUTE, COUNT and
+// UNTIL. The rule starts at start, which also gives the defaults, e.g. the day of
+// month of a monthly rule without BYMONTHDAY. Months without the day are skipped.
+type rrule struct {
+	start      time.Time
+	freq       string
+	interval   int
+	byDay      map[time.Weekday]bool
+	byMonthDay []int
+	hour       int
+	minute     int
+	count      int
+	until      time.Time
+}
+
+func parseRRule(spec string, start time.Time) (*rrule, error) {
+	r := &rrule{
+		start:    start,
+		interval: 1,
+		hour:     start.Hour(),
+		minute:   start.Minute(),
+	}
+
+	rule := strings.TrimPrefix(strings.ToUpper(spec), "RRULE:")
+	for _, part := range strings.Split(rule, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("rrule %q: invalid part %q", spec, part)
+		}
+		key, value := kv[0], kv[1]
+
+		var err error
+		switch key {
+		case "FREQ":
+			if value != "DAILY" && value != "WEEKLY" && value != "MONTHLY" {
+				return nil, fmt.Errorf("rrule %q: unsupported FREQ %s", spec, value)
+			}
+			r.freq = value
+		case "INTERVAL":
+			r.interval, err = parseBounded(value, 1, 1000)
+		case "BYDAY":
+			r.byDay = make(map[time.Weekday]bool)
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("rrule %q: unsupported BYDAY %s", spec, day)
+				}
+				r.byDay[weekday] = true
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				n, err := parseBounded(day, -31, 31)
+				if err != nil || n == 0 {
+					return nil, fmt.Errorf("rrule %q: invalid BYMONTHDAY %s", spec, day)
+				}
+				r.byMonthDay = append(r.byMonthDay, n)
+			}
+		case "BYHOUR":
+			r.hour, err = parseBounded(value, 0, 23)
+		case "BYMINUTE":
+			r.minute, err = parseBounded(value, 0, 59)
+		case "COUNT":
+			r.count, err = parseBounded(value, 1, 10000)
+		case "UNTIL":
+			r.until, err = parseUntil(value)
+		default:
+			return nil, fmt.Errorf("rrule %q: unsupported part %s", spec, key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rrule %q: invalid %s: %w", spec, key, err)
+		}
+	}
+
+	if r.freq == "" {
+		return nil, fmt.Errorf("rrule %q: FREQ is required", spec)
+	}
+	if r.count > 0 && !r.until.IsZero() {
+		return nil, fmt.Errorf("rrule %q: COUNT and UNTIL can't be used together", spec)
+	}
+	return r, nil
+}
+
+func parseBounded(value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("%d is out of range %d-%d", n, min, max)
+	}
+	return n, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// a date includes its whole day
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date or utc date-time", value)
+}
+
+func (r *rrule) Next(after time.Time) time.Time {
+	first := startOfDay(r.start)
+	// without COUNT the days before after can be skipped, the occurrences before it
+	// don't need to be counted
+	day := first
+	if r.count == 0 && after.After(first) {
+		day = startOfDay(after)
+	}
+	end := day.Add(horizon)
+
+	n := 0
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if !r.matches(first, day) {
+			continue
+		}
+		t := day.Add(time.Duration(r.hour)*time.Hour + time.Duration(r.minute)*time.Minute)
+		if t.Before(r.start) {
+			continue
+		}
+		if !r.until.IsZero() && t.After(r.until) {
+			return time.Time{}
+		}
+		n++
+		if r.count > 0 && n > r.count {
+			return time.Time{}
+		}
+		if t.After(after) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matches tells if day is in the rule, first is the day the rule starts on.
+func (r *rrule) matches(first, day time.Time) bool {
+	switch r.freq {
+	case "DAILY":
+		if daysBetween(first, day)%r.interval != 0 {
+			return false
+		}
+		return r.weekdayMatches(day) && r.monthDayMatches(day)
+	case "WEEKLY":
+		weeks := daysBetween(startOfWeek(first), startOfWeek(day)) / 7
+		if weeks%r.interval != 0 {
+			return false
+		}
+		if r.byDay == nil {
+			return day.Weekday() == first.Weekday()
+		}
+		return r.weekdayMatches(day)
+	default:
+		months := (day.Year()-first.Year())*12 + int(day.Month()-first.Month())
+		if months%r.interval != 0 {
+			return false
+		}
+		if r.byMonthDay == nil && r.byDay == nil {
+			return day.Day() == first.Day()
+		}
+		return r.weekdayMatches(day) && r.monthDayMatches(day)
+	}
+}
+
+// weekdayMatches and monthDayMatches match every day when their part is not set.
+func (r *rrule) weekdayMatches(day time.Time) bool {
+	if r.byDay == nil {
+		return true
+	}
+	return r.byDay[day.Weekday()]
+}
+
+func (r *rrule) monthDayMatches(day time.Time) bool {
+	if r.byMonthDay == nil {
+		return true
+	}
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, n := range r.byMonthDay {
+		if n == day.Day() || (n < 0 && daysInMonth+n+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// startOfWeek returns the monday of the week of day, weeks start on monday in RRULEs.
+func startOfWeek(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
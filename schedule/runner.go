@@ -0,0 +1,109 @@
+package schedule
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+)
+
+const (
+	defaultInterval  = 10 * time.Second
+	defaultBatchSize = 100
+	// Actor is who the audit events of scheduled transfers are recorded for
+	Actor = "scheduler"
+)
+
+// Store is the part of db.Store the runner needs.
+type Store interface {
+	RunScheduledTransferTx(ctx context.Context, args db.RunScheduledTransferTxParams) (db.RunScheduledTransferTxResult, error)
+}
+
+// Runner runs the due scheduled transfers. Several runners can poll the same database,
+// each due transfer is run by exactly one of them.
+type Runner struct {
+	store     Store
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+}
+
+func NewRunner(store Store, interval time.Duration) *Runner {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Runner{
+		store:     store,
+		interval:  interval,
+		batchSize: defaultBatchSize,
+		now:       time.Now,
+	}
+}
+
+// Run runs due transfers until the context is cancelled.
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		// a full batch means more transfers are probably due
+		for {
+			n, err := r.RunDue(ctx)
+			if err != nil {
+				log.Println("Cannot run scheduled transfers ", err)
+				break
+			}
+			if n < r.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue runs up to one batch of due transfers, one transaction each, and returns how
+// many ran.
+func (r *Runner) RunDue(ctx context.Context) (int, error) {
+	ctx = db.WithAuditContext(ctx, &db.AuditContext{Actor: Actor})
+
+	for n := 0; n < r.batchSize; n++ {
+		result, err := r.store.RunScheduledTransferTx(ctx, db.RunScheduledTransferTxParams{
+			NextRunAt: r.nextRunAt,
+		})
+		if err == sql.ErrNoRows {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if result.Run.Status == db.ScheduledRunStatusFailed {
+			log.Printf("scheduled transfer %d failed: %s", result.ScheduledTransfer.ID, result.Run.Error)
+		}
+	}
+	return r.batchSize, nil
+}
+
+// nextRunAt returns the first run after the current one and after now. When no runner
+// was up for a while the overdue run is made once and the runs missed meanwhile are
+// skipped, rather than all made at once.
+func (r *Runner) nextRunAt(scheduled db.ScheduledTransfer) (time.Time, bool) {
+	s, err := Parse(scheduled.Schedule, scheduled.StartAt)
+	if err != nil {
+		log.Printf("scheduled transfer %d has an invalid schedule, stopping it: %v", scheduled.ID, err)
+		return time.Time{}, false
+	}
+
+	after := scheduled.NextRunAt.Time
+	if now := r.now(); now.After(after) {
+		after = now
+	}
+	next := s.Next(after)
+	return next, !next.IsZero()
+}
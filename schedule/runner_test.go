@@ -0,0 +1,155 @@
+package schedule
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeScheduled runs its due transfers the way db.Store does, without the locking.
+type fakeScheduled struct {
+	now       time.Time
+	scheduled []db.ScheduledTransfer
+	runs      []db.ScheduledTransferRun
+	fail      bool
+}
+
+func (s *fakeScheduled) RunScheduledTransferTx(ctx context.Context, args db.RunScheduledTransferTxParams) (db.RunScheduledTransferTxResult, error) {
+	audit, ok := ctx.Value(db.AuditContextKey).(*db.AuditContext)
+	if !ok || audit.Actor != Actor {
+		return db.RunScheduledTransferTxResult{}, errors.New("missing audit actor")
+	}
+
+	for i := range s.scheduled {
+		scheduled := &s.scheduled[i]
+		if scheduled.Status != db.ScheduledTransferStatusActive || scheduled.NextRunAt.Time.After(s.now) {
+			continue
+		}
+
+		run := db.ScheduledTransferRun{
+			ScheduledTransferID: scheduled.ID,
+			ScheduledFor:        scheduled.NextRunAt.Time,
+			Status:              db.ScheduledRunStatusSucceeded,
+		}
+		if s.fail {
+			run.Status = db.ScheduledRunStatusFailed
+			run.Error = "insufficient funds"
+		}
+		s.runs = append(s.runs, run)
+
+		next, ok := args.NextRunAt(*scheduled)
+		scheduled.LastRunAt = scheduled.NextRunAt
+		scheduled.NextRunAt = sql.NullTime{Time: next, Valid: ok}
+		scheduled.RunCount++
+		if !ok {
+			scheduled.Status = db.ScheduledTransferStatusCompleted
+		}
+		return db.RunScheduledTransferTxResult{ScheduledTransfer: *scheduled, Run: run}, nil
+	}
+	return db.RunScheduledTransferTxResult{}, sql.ErrNoRows
+}
+
+func TestRunner(t *testing.T) {
+	now := date("2026-03-10T12:00:00Z")
+	store := &fakeScheduled{
+		now: now,
+		scheduled: []db.ScheduledTransfer{
+			{
+				// rent, missed since january
+				ID:        1,
+				Schedule:  "0 9 1 * *",
+				StartAt:   date("2026-01-01T00:00:00Z"),
+				NextRunAt: sql.NullTime{Time: date("2026-01-01T09:00:00Z"), Valid: true},
+				Status:    db.ScheduledTransferStatusActive,
+			},
+			{
+				ID:        2,
+				StartAt:   date("2026-03-10T11:00:00Z"),
+				NextRunAt: sql.NullTime{Time: date("2026-03-10T11:00:00Z"), Valid: true},
+				Status:    db.ScheduledTransferStatusActive,
+			},
+			{
+				ID:        3,
+				StartAt:   date("2026-03-11T00:00:00Z"),
+				NextRunAt: sql.NullTime{Time: date("2026-03-11T00:00:00Z"), Valid: true},
+				Status:    db.ScheduledTransferStatusActive,
+			},
+		},
+	}
+
+	runner := NewRunner(store, time.Minute)
+	runner.now = func() time.Time { return now }
+
+	n, err := runner.RunDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Len(t, store.runs, 2)
+
+	// the missed runs are skipped, the next one is the coming 1st
+	require.Equal(t, date("2026-04-01T09:00:00Z"), store.scheduled[0].NextRunAt.Time)
+	require.Equal(t, db.ScheduledTransferStatusActive, store.scheduled[0].Status)
+
+	// a one-off is done after its run
+	require.False(t, store.scheduled[1].NextRunAt.Valid)
+	require.Equal(t, db.ScheduledTransferStatusCompleted, store.scheduled[1].Status)
+
+	// not due yet
+	require.Zero(t, store.scheduled[2].RunCount)
+
+	n, err = runner.RunDue(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestRunnerFailedRunsGoOn(t *testing.T) {
+	now := date("2026-03-01T09:00:00Z")
+	store := &fakeScheduled{
+		now:  now,
+		fail: true,
+		scheduled: []db.ScheduledTransfer{{
+			ID:        1,
+			Schedule:  "FREQ=MONTHLY;BYMONTHDAY=1;BYHOUR=9;BYMINUTE=0",
+			StartAt:   date("2026-01-01T00:00:00Z"),
+			NextRunAt: sql.NullTime{Time: now, Valid: true},
+			Status:    db.ScheduledTransferStatusActive,
+		}},
+	}
+
+	runner := NewRunner(store, 0)
+	runner.now = func() time.Time { return now }
+
+	n, err := runner.RunDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, db.ScheduledRunStatusFailed, store.runs[0].Status)
+	require.Equal(t, date("2026-04-01T09:00:00Z"), store.scheduled[0].NextRunAt.Time)
+}
+
+func TestRunnerBatchSize(t *testing.T) {
+	now := date("2026-03-01T09:00:00Z")
+	store := &fakeScheduled{now: now}
+	for i := 0; i < 5; i++ {
+		store.scheduled = append(store.scheduled, db.ScheduledTransfer{
+			ID:        int64(i + 1),
+			StartAt:   now,
+			NextRunAt: sql.NullTime{Time: now, Valid: true},
+			Status:    db.ScheduledTransferStatusActive,
+		})
+	}
+
+	runner := NewRunner(store, 0)
+	runner.batchSize = 3
+
+	n, err := runner.RunDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	n, err = runner.RunDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+}
@@ -0,0 +1,52 @@
+// Package schedule reads the recurrence of scheduled transfers and runs the due ones.
+package schedule
+
+import (
+	"strings"
+	"time"
+)
+
+// horizon is how far ahead Next looks for an occurrence before giving up.
+const horizon = 10 * 366 * 24 * time.Hour
+
+// Schedule tells when a scheduled transfer runs. Every time is in UTC.
+type Schedule interface {
+	// Next returns the first run strictly after the given time, or the zero time when
+	// there is none.
+	Next(after time.Time) time.Time
+}
+
+// Parse reads a schedule: an empty spec runs once at start, a spec with FREQ= is an
+// RRULE (the RRULE: prefix is optional) that starts at start, anything else is a cron
+// expression with five fields.
+func Parse(spec string, start time.Time) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "":
+		return once{at: start.UTC()}, nil
+	case strings.Contains(strings.ToUpper(spec), "FREQ="):
+		return parseRRule(spec, start.UTC())
+	default:
+		return parseCron(spec)
+	}
+}
+
+// First returns the first run at or after start.
+func First(s Schedule, start time.Time) time.Time {
+	return s.Next(start.Add(-time.Nanosecond))
+}
+
+type once struct {
+	at time.Time
+}
+
+func (o once) Next(after time.Time) time.Time {
+	if after.Before(o.at) {
+		return o.at
+	}
+	return time.Time{}
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,154 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestNext(t *testing.T) {
+	start := date("2026-01-15T09:30:00Z")
+
+	testCases := []struct {
+		name  string
+		spec  string
+		after string
+		runs  []string
+		// ends is set when the schedule has no run after the last one
+		ends bool
+	}{
+		{
+			name:  "Once",
+			spec:  "",
+			after: "2026-01-01T00:00:00Z",
+			runs:  []string{"2026-01-15T09:30:00Z"},
+			ends:  true,
+		},
+		{
+			name:  "Cron rent on the 1st",
+			spec:  "0 9 1 * *",
+			after: "2026-01-15T09:30:00Z",
+			runs:  []string{"2026-02-01T09:00:00Z", "2026-03-01T09:00:00Z", "2026-04-01T09:00:00Z"},
+		},
+		{
+			name:  "Cron steps and ranges",
+			spec:  "*/20 8-9 * * *",
+			after: "2026-01-15T09:30:00Z",
+			runs:  []string{"2026-01-15T09:40:00Z", "2026-01-16T08:00:00Z", "2026-01-16T08:20:00Z"},
+		},
+		{
+			name:  "Cron weekdays with sunday as 7",
+			spec:  "0 12 * * 5,7",
+			after: "2026-01-15T09:30:00Z",
+			runs:  []string{"2026-01-16T12:00:00Z", "2026-01-18T12:00:00Z", "2026-01-23T12:00:00Z"},
+		},
+		{
+			name:  "Cron day of month or day of week",
+			spec:  "0 0 1 * 1",
+			after: "2026-01-28T00:00:00Z",
+			runs:  []string{"2026-02-01T00:00:00Z", "2026-02-02T00:00:00Z", "2026-02-09T00:00:00Z"},
+		},
+		{
+			name:  "Cron day of month step and day of week",
+			spec:  "0 0 */2 * 1",
+			after: "2026-01-28T00:00:00Z",
+			runs:  []string{"2026-02-09T00:00:00Z", "2026-02-23T00:00:00Z", "2026-03-09T00:00:00Z"},
+		},
+		{
+			name:  "Cron 31st skips short months",
+			spec:  "0 0 31 * *",
+			after: "2026-01-31T00:00:00Z",
+			runs:  []string{"2026-03-31T00:00:00Z", "2026-05-31T00:00:00Z"},
+		},
+		{
+			name:  "Cron february 29th",
+			spec:  "0 0 29 2 *",
+			after: "2026-01-01T00:00:00Z",
+			runs:  []string{"2028-02-29T00:00:00Z"},
+		},
+		{
+			name:  "RRULE monthly defaults to the start day and time",
+			spec:  "RRULE:FREQ=MONTHLY",
+			after: "2026-01-01T00:00:00Z",
+			runs:  []string{"2026-01-15T09:30:00Z", "2026-02-15T09:30:00Z", "2026-03-15T09:30:00Z"},
+		},
+		{
+			name:  "RRULE last day of the month",
+			spec:  "FREQ=MONTHLY;BYMONTHDAY=-1;BYHOUR=18;BYMINUTE=0",
+			after: "2026-01-15T09:30:00Z",
+			runs:  []string{"2026-01-31T18:00:00Z", "2026-02-28T18:00:00Z", "2026-03-31T18:00:00Z"},
+		},
+		{
+			name:  "RRULE every other week",
+			spec:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+			after: "2026-01-15T09:30:00Z",
+			runs:  []string{"2026-01-16T09:30:00Z", "2026-01-26T09:30:00Z", "2026-01-30T09:30:00Z", "2026-02-09T09:30:00Z"},
+		},
+		{
+			name:  "RRULE daily count",
+			spec:  "FREQ=DAILY;INTERVAL=3;COUNT=2",
+			after: "2026-01-01T00:00:00Z",
+			runs:  []string{"2026-01-15T09:30:00Z", "2026-01-18T09:30:00Z"},
+			ends:  true,
+		},
+		{
+			name:  "RRULE until",
+			spec:  "FREQ=DAILY;UNTIL=20260116",
+			after: "2026-01-15T00:00:00Z",
+			runs:  []string{"2026-01-15T09:30:00Z", "2026-01-16T09:30:00Z"},
+			ends:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := Parse(tc.spec, start)
+			require.NoError(t, err)
+
+			after := date(tc.after)
+			for _, run := range tc.runs {
+				after = s.Next(after)
+				require.Equal(t, date(run), after)
+			}
+			if tc.ends {
+				require.True(t, s.Next(after).IsZero(), "no run expected after %s", after)
+			}
+		})
+	}
+}
+
+func TestFirst(t *testing.T) {
+	s, err := Parse("0 9 * * *", time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, date("2026-01-15T09:00:00Z"), First(s, date("2026-01-15T09:00:00Z")))
+	require.Equal(t, date("2026-01-16T09:00:00Z"), First(s, date("2026-01-15T09:00:01Z")))
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"0 9 1 *",
+		"60 * * * *",
+		"* * 0 * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"5-1 * * * *",
+		"FREQ=YEARLY",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260101",
+		"FREQ=DAILY;BYSETPOS=1",
+		"INTERVAL=2;FREQ",
+	} {
+		_, err := Parse(spec, time.Now())
+		require.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,43 @@
+package token
+
+import (
+	"fmt"
+
+	"github.com/RahilRehan/banco/db/util"
+)
+
+const (
+	TokenTypePaseto       = "paseto"
+	TokenTypePasetoPublic = "paseto-public"
+	TokenTypeJWT          = "jwt"
+)
+
+// NewMaker builds the Maker selected by TOKEN_TYPE with its key ring loaded, v2.local
+// PASETO when it is not set. The key ring is returned so the caller can reload it.
+func NewMaker(cfg util.Config) (Maker, *KeyRing, error) {
+	var newMaker func(keys *KeyRing) (Maker, error)
+	var singleKey string
+
+	switch cfg.TOKEN_TYPE {
+	case "", TokenTypePaseto:
+		newMaker, singleKey = NewPasetoMakerWithKeyRing, cfg.TOKEN_SYMMETRIC_KEY
+	case TokenTypePasetoPublic:
+		newMaker, singleKey = NewPasetoPublicMakerWithKeyRing, cfg.TOKEN_PRIVATE_KEY
+	case TokenTypeJWT:
+		newMaker, singleKey = NewJWTMakerWithKeyRing, cfg.TOKEN_SYMMETRIC_KEY
+	default:
+		return nil, nil, fmt.Errorf("unsupported token type %q", cfg.TOKEN_TYPE)
+	}
+
+	keySource, err := NewKeySource(cfg, singleKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := NewKeyRing(keySource)
+	maker, err := newMaker(keys)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s token keys: %w", cfg.TOKEN_TYPE, err)
+	}
+	return maker, keys, nil
+}
@@ -0,0 +1,75 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMaker(t *testing.T) {
+	privateKey, _, err := GenerateEd25519Keys()
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		config       util.Config
+		expectedType Maker
+	}{
+		"Default": {
+			config:       util.Config{TOKEN_SYMMETRIC_KEY: util.RandomString(32)},
+			expectedType: &PasetoMaker{},
+		},
+		"Paseto": {
+			config:       util.Config{TOKEN_TYPE: TokenTypePaseto, TOKEN_SYMMETRIC_KEY: util.RandomString(32)},
+			expectedType: &PasetoMaker{},
+		},
+		"Paseto public": {
+			config:       util.Config{TOKEN_TYPE: TokenTypePasetoPublic, TOKEN_PRIVATE_KEY: privateKey},
+			expectedType: &PasetoPublicMaker{},
+		},
+		"JWT": {
+			config:       util.Config{TOKEN_TYPE: TokenTypeJWT, TOKEN_SYMMETRIC_KEY: util.RandomString(32)},
+			expectedType: &JWTMaker{},
+		},
+		"Key list": {
+			config: util.Config{
+				TOKEN_TYPE:          TokenTypeJWT,
+				TOKEN_KEYS:          []string{"old:" + util.RandomString(32), "new:" + util.RandomString(32)},
+				TOKEN_ACTIVE_KEY_ID: "new",
+			},
+			expectedType: &JWTMaker{},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			maker, keys, err := NewMaker(test.config)
+			require.NoError(t, err)
+			require.IsType(t, test.expectedType, maker)
+			require.NotEmpty(t, keys.ActiveKeyID())
+
+			token, _, err := maker.CreateToken(util.RandomOwner(), util.CustomerRole, AccessToken, time.Minute)
+			require.NoError(t, err)
+			_, err = maker.VerifyToken(token)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNewMakerInvalidConfig(t *testing.T) {
+	testCases := map[string]util.Config{
+		"Unknown type":          {TOKEN_TYPE: "macaroon", TOKEN_SYMMETRIC_KEY: util.RandomString(32)},
+		"Missing symmetric key": {TOKEN_TYPE: TokenTypePaseto},
+		"Short JWT key":         {TOKEN_TYPE: TokenTypeJWT, TOKEN_SYMMETRIC_KEY: util.RandomString(12)},
+		"Missing private key":   {TOKEN_TYPE: TokenTypePasetoPublic, TOKEN_SYMMETRIC_KEY: util.RandomString(32)},
+		"Missing active key id": {TOKEN_KEYS: []string{"k1:" + util.RandomString(32)}},
+	}
+
+	for name, config := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := NewMaker(config)
+			require.Error(t, err)
+		})
+	}
+}
@@ -10,18 +10,23 @@ import (
 
 const minSecretKeySize = 32
 
+const jwtKeyIDHeader = "kid"
+
 type JWTMaker struct {
-	secretKey string
+	keys *KeyRing
 }
 
-func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (string, error) {
-	payload, err := NewPayload(username, duration)
+func (maker *JWTMaker) CreateToken(username string, role string, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tokenType, duration)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
+	keyID, secretKey := maker.keys.active()
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
-	return jwtToken.SignedString([]byte(maker.secretKey))
+	jwtToken.Header[jwtKeyIDHeader] = keyID
+	token, err := jwtToken.SignedString(secretKey)
+	return token, payload, err
 }
 
 func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
@@ -30,7 +35,12 @@ func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
 		if !ok {
 			return nil, ErrInvalidToken
 		}
-		return []byte(maker.secretKey), nil
+		keyID, _ := token.Header[jwtKeyIDHeader].(string)
+		secretKey, err := maker.keys.key(keyID)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+		return secretKey, nil
 	}
 	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, keyFunc)
 	if err != nil {
@@ -49,9 +59,26 @@ func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
 }
 
 func NewJWTMaker(secretKey string) (Maker, error) {
+	if _, err := decodeJWTKey(secretKey); err != nil {
+		return nil, err
+	}
+	return NewJWTMakerWithKeyRing(NewKeyRing(StaticKeySource{
+		ActiveKeyID: DefaultKeyID,
+		Keys:        map[string]string{DefaultKeyID: secretKey},
+	}))
+}
+
+// NewJWTMakerWithKeyRing signs HS256 tokens with the keys of the ring, the key id goes into the kid header.
+func NewJWTMakerWithKeyRing(keys *KeyRing) (Maker, error) {
+	if err := keys.use(decodeJWTKey); err != nil {
+		return nil, err
+	}
+	return &JWTMaker{keys: keys}, nil
+}
+
+func decodeJWTKey(secretKey string) ([]byte, error) {
 	if len(secretKey) < minSecretKeySize {
 		return nil, fmt.Errorf("invalid key size: must be at least %d but got %d", minSecretKeySize, len(secretKey))
 	}
-
-	return &JWTMaker{}, nil
+	return []byte(secretKey), nil
 }
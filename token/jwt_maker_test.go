@@ -20,14 +20,18 @@ func TestJWTMaker(t *testing.T) {
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token, err := maker.CreateToken(username, duration)
+	token, createdPayload, err := maker.CreateToken(username, util.AdminRole, AccessToken, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
+	require.NotEmpty(t, createdPayload)
 
 	payload, err := maker.VerifyToken(token)
 	require.NoError(t, err)
 	require.NotZero(t, payload.ID)
+	require.Equal(t, createdPayload.ID, payload.ID)
 	require.Equal(t, username, payload.Username)
+	require.Equal(t, util.AdminRole, payload.Role)
+	require.Equal(t, AccessToken, payload.Type)
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
 }
@@ -37,11 +41,25 @@ func TestJWTMakerInvalidLength(t *testing.T) {
 	require.Equal(t, err, fmt.Errorf("invalid key size: must be at least %d but got %d", minSecretKeySize, 12))
 }
 
+func TestJWTMakerWrongKey(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+	otherMaker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := otherMaker.CreateToken(util.RandomOwner(), util.CustomerRole, AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
 func TestInvalidJWTToken(t *testing.T) {
 	maker, err := NewJWTMaker(util.RandomString(32))
 	require.NoError(t, err)
 
-	token, err := maker.CreateToken(util.RandomOwner(), -time.Minute)
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.CustomerRole, AccessToken, -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
@@ -52,7 +70,7 @@ func TestInvalidJWTToken(t *testing.T) {
 }
 
 func TestInvalidJWTTokenAlgNone(t *testing.T) {
-	payload, err := NewPayload(util.RandomOwner(), time.Minute)
+	payload, err := NewPayload(util.RandomOwner(), util.CustomerRole, AccessToken, time.Minute)
 	require.NoError(t, err)
 
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
@@ -0,0 +1,189 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/RahilRehan/banco/db/util"
+)
+
+// DefaultKeyID names the key of a maker built from a single key.
+const DefaultKeyID = "default"
+
+const keyFileExtension = ".key"
+
+var ErrUnknownKeyID = errors.New("unknown key id")
+
+// KeySource provides the keys of a KeyRing as their encoded strings by key ID.
+type KeySource interface {
+	Load() (activeKeyID string, keys map[string]string, err error)
+}
+
+// StaticKeySource serves keys given in the config.
+type StaticKeySource struct {
+	ActiveKeyID string
+	Keys        map[string]string
+}
+
+func (s StaticKeySource) Load() (string, map[string]string, error) {
+	return s.ActiveKeyID, s.Keys, nil
+}
+
+// ParseKeyList builds a StaticKeySource from "id:key" entries, e.g. TOKEN_KEYS.
+func ParseKeyList(activeKeyID string, entries []string) (StaticKeySource, error) {
+	keys := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return StaticKeySource{}, fmt.Errorf("invalid key entry %q: must be id:key", entry)
+		}
+		keys[parts[0]] = parts[1]
+	}
+	return StaticKeySource{ActiveKeyID: activeKeyID, Keys: keys}, nil
+}
+
+// DirKeySource reads one <id>.key file per key from Dir, surrounding whitespace is ignored.
+// The active key is ActiveKeyID or, when that is empty, the id in the file named "active".
+type DirKeySource struct {
+	Dir         string
+	ActiveKeyID string
+}
+
+func (s DirKeySource) Load() (string, map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(s.Dir, "*"+keyFileExtension))
+	if err != nil {
+		return "", nil, err
+	}
+
+	keys := make(map[string]string, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", nil, fmt.Errorf("cannot read key file: %w", err)
+		}
+		id := strings.TrimSuffix(filepath.Base(file), keyFileExtension)
+		keys[id] = strings.TrimSpace(string(data))
+	}
+
+	activeKeyID := s.ActiveKeyID
+	if len(activeKeyID) == 0 {
+		data, err := os.ReadFile(filepath.Join(s.Dir, "active"))
+		if err != nil {
+			return "", nil, fmt.Errorf("cannot read active key id: %w", err)
+		}
+		activeKeyID = strings.TrimSpace(string(data))
+	}
+	return activeKeyID, keys, nil
+}
+
+// KeyRing holds every key a Maker accepts by key ID. New tokens are signed with the
+// active key, tokens signed with another key in the ring still verify, and a key is
+// retired by removing it from the source and calling Reload.
+type KeyRing struct {
+	source KeySource
+	decode func(key string) ([]byte, error)
+
+	mu          sync.RWMutex
+	activeKeyID string
+	keys        map[string][]byte
+}
+
+func NewKeyRing(source KeySource) *KeyRing {
+	return &KeyRing{source: source}
+}
+
+// Reload reads the source again. When a key is invalid the ring keeps its current keys.
+func (r *KeyRing) Reload() error {
+	if r.decode == nil {
+		return errors.New("key ring is not used by a token maker")
+	}
+
+	activeKeyID, encoded, err := r.source.Load()
+	if err != nil {
+		return err
+	}
+	if _, ok := encoded[activeKeyID]; !ok {
+		return fmt.Errorf("%w: active key %q is not in the key ring", ErrUnknownKeyID, activeKeyID)
+	}
+
+	keys := make(map[string][]byte, len(encoded))
+	for id, key := range encoded {
+		decoded, err := r.decode(key)
+		if err != nil {
+			return fmt.Errorf("key %s: %w", id, err)
+		}
+		keys[id] = decoded
+	}
+
+	r.mu.Lock()
+	r.activeKeyID = activeKeyID
+	r.keys = keys
+	r.mu.Unlock()
+	return nil
+}
+
+// ActiveKeyID returns the id of the key new tokens are signed with.
+func (r *KeyRing) ActiveKeyID() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.activeKeyID
+}
+
+// KeyIDs returns the ids of every accepted key, sorted.
+func (r *KeyRing) KeyIDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, 0, len(r.keys))
+	for id := range r.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// use binds the ring to the key format of a maker and loads it.
+func (r *KeyRing) use(decode func(key string) ([]byte, error)) error {
+	r.decode = decode
+	return r.Reload()
+}
+
+func (r *KeyRing) active() (string, []byte) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.activeKeyID, r.keys[r.activeKeyID]
+}
+
+// key returns the key with the given id, tokens without a key id were signed before
+// the key ring existed and are checked against the active key.
+func (r *KeyRing) key(id string) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(id) == 0 {
+		id = r.activeKeyID
+	}
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKeyID, id)
+	}
+	return key, nil
+}
+
+// NewKeySource picks where the key ring of the api loads its keys from: the
+// TOKEN_KEYS_DIR directory, the TOKEN_KEYS list, or else the single key given.
+func NewKeySource(cfg util.Config, singleKey string) (KeySource, error) {
+	if len(cfg.TOKEN_KEYS_DIR) > 0 {
+		return DirKeySource{Dir: cfg.TOKEN_KEYS_DIR, ActiveKeyID: cfg.TOKEN_ACTIVE_KEY_ID}, nil
+	}
+	if len(cfg.TOKEN_KEYS) > 0 {
+		return ParseKeyList(cfg.TOKEN_ACTIVE_KEY_ID, cfg.TOKEN_KEYS)
+	}
+	return StaticKeySource{
+		ActiveKeyID: DefaultKeyID,
+		Keys:        map[string]string{DefaultKeyID: singleKey},
+	}, nil
+}
@@ -0,0 +1,131 @@
+package token
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyRotation(t *testing.T) {
+	privateKey1, _, err := GenerateEd25519Keys()
+	require.NoError(t, err)
+	privateKey2, _, err := GenerateEd25519Keys()
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		key1, key2 string
+		newMaker   func(keys *KeyRing) (Maker, error)
+	}{
+		"v2.local": {
+			key1:     util.RandomString(32),
+			key2:     util.RandomString(32),
+			newMaker: NewPasetoMakerWithKeyRing,
+		},
+		"v2.public": {
+			key1:     privateKey1,
+			key2:     privateKey2,
+			newMaker: NewPasetoPublicMakerWithKeyRing,
+		},
+		"jwt": {
+			key1:     util.RandomString(32),
+			key2:     util.RandomString(40),
+			newMaker: NewJWTMakerWithKeyRing,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			source := &StaticKeySource{
+				ActiveKeyID: "k1",
+				Keys:        map[string]string{"k1": test.key1},
+			}
+			keys := NewKeyRing(source)
+			maker, err := test.newMaker(keys)
+			require.NoError(t, err)
+
+			oldToken, _, err := maker.CreateToken(util.RandomOwner(), util.CustomerRole, AccessToken, time.Minute)
+			require.NoError(t, err)
+
+			// rotate: k2 signs, k1 is still accepted
+			source.ActiveKeyID = "k2"
+			source.Keys = map[string]string{"k1": test.key1, "k2": test.key2}
+			require.NoError(t, keys.Reload())
+			require.Equal(t, "k2", keys.ActiveKeyID())
+			require.Equal(t, []string{"k1", "k2"}, keys.KeyIDs())
+
+			_, err = maker.VerifyToken(oldToken)
+			require.NoError(t, err)
+
+			newToken, _, err := maker.CreateToken(util.RandomOwner(), util.CustomerRole, AccessToken, time.Minute)
+			require.NoError(t, err)
+			_, err = maker.VerifyToken(newToken)
+			require.NoError(t, err)
+
+			// retire k1
+			source.Keys = map[string]string{"k2": test.key2}
+			require.NoError(t, keys.Reload())
+
+			_, err = maker.VerifyToken(oldToken)
+			require.EqualError(t, err, ErrInvalidToken.Error())
+			_, err = maker.VerifyToken(newToken)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestKeyRingReloadKeepsKeysOnError(t *testing.T) {
+	source := &StaticKeySource{
+		ActiveKeyID: "k1",
+		Keys:        map[string]string{"k1": util.RandomString(32)},
+	}
+	keys := NewKeyRing(source)
+	maker, err := NewPasetoMakerWithKeyRing(keys)
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), util.CustomerRole, AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	source.ActiveKeyID = "k2"
+	source.Keys = map[string]string{"k2": "too short"}
+	require.Error(t, keys.Reload())
+
+	source.Keys = map[string]string{"k3": util.RandomString(32)}
+	require.ErrorIs(t, keys.Reload(), ErrUnknownKeyID)
+
+	require.Equal(t, "k1", keys.ActiveKeyID())
+	_, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+}
+
+func TestDirKeySource(t *testing.T) {
+	dir := t.TempDir()
+	key1 := util.RandomString(32)
+	key2 := util.RandomString(32)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2021-10.key"), []byte(key1+"\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2021-11.key"), []byte(key2), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "active"), []byte("2021-11\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("not a key"), 0600))
+
+	activeKeyID, keys, err := DirKeySource{Dir: dir}.Load()
+	require.NoError(t, err)
+	require.Equal(t, "2021-11", activeKeyID)
+	require.Equal(t, map[string]string{"2021-10": key1, "2021-11": key2}, keys)
+
+	activeKeyID, _, err = DirKeySource{Dir: dir, ActiveKeyID: "2021-10"}.Load()
+	require.NoError(t, err)
+	require.Equal(t, "2021-10", activeKeyID)
+}
+
+func TestParseKeyList(t *testing.T) {
+	source, err := ParseKeyList("b", []string{"a:first", " b:sec:ond "})
+	require.NoError(t, err)
+	require.Equal(t, "b", source.ActiveKeyID)
+	require.Equal(t, map[string]string{"a": "first", "b": "sec:ond"}, source.Keys)
+
+	_, err = ParseKeyList("a", []string{"nokey"})
+	require.Error(t, err)
+}
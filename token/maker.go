@@ -3,6 +3,9 @@ package token
 import "time"
 
 type Maker interface {
-	CreateToken(username string, duration time.Duration) (string, error)
+	// CreateToken returns the signed token and the payload it carries, the payload ID
+	// identifies the token, e.g. as the session ID of a refresh token. The role is
+	// carried along so routes can be limited to support or admin users.
+	CreateToken(username string, role string, tokenType TokenType, duration time.Duration) (string, *Payload, error)
 	VerifyToken(token string) (*Payload, error)
 }
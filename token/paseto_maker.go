@@ -8,23 +8,40 @@ import (
 	"golang.org/x/crypto/chacha20poly1305"
 )
 
+// keyFooter is the unencrypted but authenticated footer of a PASETO token, it names
+// the key of the key ring the token was made with.
+type keyFooter struct {
+	KeyID string `json:"kid"`
+}
+
 type PasetoMaker struct {
-	paseto       *paseto.V2
-	symmetricKey []byte
+	paseto *paseto.V2
+	keys   *KeyRing
 }
 
-func (m *PasetoMaker) CreateToken(username string, duration time.Duration) (string, error) {
-	payload, err := NewPayload(username, duration)
+func (m *PasetoMaker) CreateToken(username string, role string, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tokenType, duration)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	return m.paseto.Encrypt(m.symmetricKey, payload, nil)
+	keyID, symmetricKey := m.keys.active()
+	token, err := m.paseto.Encrypt(symmetricKey, payload, keyFooter{KeyID: keyID})
+	return token, payload, err
 }
 
 func (m *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 	payload := &Payload{}
 
-	err := m.paseto.Decrypt(token, m.symmetricKey, payload, nil)
+	var footer keyFooter
+	if err := paseto.ParseFooter(token, &footer); err != nil {
+		return nil, ErrInvalidToken
+	}
+	symmetricKey, err := m.keys.key(footer.KeyID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	err = m.paseto.Decrypt(token, symmetricKey, payload, nil)
 	if err != nil {
 		return nil, ErrInvalidToken
 	}
@@ -36,13 +53,31 @@ func (m *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 }
 
 func NewPasetoMaker(symmetricKey string) (Maker, error) {
-	if len(symmetricKey) != chacha20poly1305.KeySize {
-		return nil, fmt.Errorf("invalid key size: must be %d but got %d", chacha20poly1305.KeySize, len(symmetricKey))
+	if _, err := decodeSymmetricKey(symmetricKey); err != nil {
+		return nil, err
+	}
+	return NewPasetoMakerWithKeyRing(NewKeyRing(StaticKeySource{
+		ActiveKeyID: DefaultKeyID,
+		Keys:        map[string]string{DefaultKeyID: symmetricKey},
+	}))
+}
+
+// NewPasetoMakerWithKeyRing makes v2.local tokens with the symmetric keys of the ring.
+func NewPasetoMakerWithKeyRing(keys *KeyRing) (Maker, error) {
+	if err := keys.use(decodeSymmetricKey); err != nil {
+		return nil, err
 	}
 
 	maker := &PasetoMaker{
-		paseto:       paseto.NewV2(),
-		symmetricKey: []byte(symmetricKey),
+		paseto: paseto.NewV2(),
+		keys:   keys,
 	}
 	return maker, nil
 }
+
+func decodeSymmetricKey(symmetricKey string) ([]byte, error) {
+	if len(symmetricKey) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("invalid key size: must be %d but got %d", chacha20poly1305.KeySize, len(symmetricKey))
+	}
+	return []byte(symmetricKey), nil
+}
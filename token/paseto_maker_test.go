@@ -10,26 +10,47 @@ import (
 	"golang.org/x/crypto/chacha20poly1305"
 )
 
-func TestPasetoMaker(t *testing.T) {
-	maker, err := NewPasetoMaker(util.RandomString(32))
+// pasetoMakers returns one maker per PASETO flavour so every test covers both.
+func pasetoMakers(t *testing.T) map[string]Maker {
+	symmetricMaker, err := NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 
-	username := util.RandomOwner()
+	privateKey, _, err := GenerateEd25519Keys()
+	require.NoError(t, err)
+	publicMaker, err := NewPasetoPublicMaker(privateKey)
+	require.NoError(t, err)
 
-	duration := time.Minute
-	issuedAt := time.Now()
-	expiredAt := issuedAt.Add(duration)
+	return map[string]Maker{
+		"v2.local":  symmetricMaker,
+		"v2.public": publicMaker,
+	}
+}
 
-	token, err := maker.CreateToken(username, duration)
-	require.NoError(t, err)
-	require.NotEmpty(t, token)
+func TestPasetoMaker(t *testing.T) {
+	for name, maker := range pasetoMakers(t) {
+		t.Run(name, func(t *testing.T) {
+			username := util.RandomOwner()
 
-	payload, err := maker.VerifyToken(token)
-	require.NoError(t, err)
-	require.NotZero(t, payload.ID)
-	require.Equal(t, username, payload.Username)
-	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
-	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+			duration := time.Minute
+			issuedAt := time.Now()
+			expiredAt := issuedAt.Add(duration)
+
+			token, createdPayload, err := maker.CreateToken(username, util.AdminRole, AccessToken, duration)
+			require.NoError(t, err)
+			require.NotEmpty(t, token)
+			require.NotEmpty(t, createdPayload)
+
+			payload, err := maker.VerifyToken(token)
+			require.NoError(t, err)
+			require.NotZero(t, payload.ID)
+			require.Equal(t, createdPayload.ID, payload.ID)
+			require.Equal(t, username, payload.Username)
+			require.Equal(t, util.AdminRole, payload.Role)
+			require.Equal(t, AccessToken, payload.Type)
+			require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+			require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+		})
+	}
 }
 
 func TestPasetoMakerInvalidLength(t *testing.T) {
@@ -38,15 +59,64 @@ func TestPasetoMakerInvalidLength(t *testing.T) {
 }
 
 func TestInvalidPasetoToken(t *testing.T) {
-	maker, err := NewPasetoMaker(util.RandomString(32))
+	for name, maker := range pasetoMakers(t) {
+		t.Run(name, func(t *testing.T) {
+			token, _, err := maker.CreateToken(util.RandomOwner(), util.CustomerRole, AccessToken, -time.Minute)
+			require.NoError(t, err)
+			require.NotEmpty(t, token)
+
+			payload, err := maker.VerifyToken(token)
+			require.Error(t, err)
+			require.EqualError(t, err, ErrExpiredToken.Error())
+			require.Nil(t, payload)
+		})
+	}
+}
+
+func TestPasetoTokenFromOtherMaker(t *testing.T) {
+	makers := pasetoMakers(t)
+	otherMakers := pasetoMakers(t)
+
+	for name, maker := range makers {
+		t.Run(name, func(t *testing.T) {
+			token, _, err := otherMakers[name].CreateToken(util.RandomOwner(), util.CustomerRole, AccessToken, time.Minute)
+			require.NoError(t, err)
+
+			payload, err := maker.VerifyToken(token)
+			require.EqualError(t, err, ErrInvalidToken.Error())
+			require.Nil(t, payload)
+		})
+	}
+}
+
+func TestPasetoVerifier(t *testing.T) {
+	privateKey, publicKey, err := GenerateEd25519Keys()
+	require.NoError(t, err)
+
+	maker, err := NewPasetoPublicMaker(privateKey)
+	require.NoError(t, err)
+	verifier, err := NewPasetoVerifier(publicKey)
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	token, _, err := maker.CreateToken(username, util.CustomerRole, AccessToken, time.Minute)
 	require.NoError(t, err)
 
-	token, err := maker.CreateToken(util.RandomOwner(), -time.Minute)
+	payload, err := verifier.VerifyToken(token)
 	require.NoError(t, err)
-	require.NotEmpty(t, token)
+	require.Equal(t, username, payload.Username)
 
-	payload, err := maker.VerifyToken(token)
+	_, _, err = verifier.CreateToken(username, util.CustomerRole, AccessToken, time.Minute)
+	require.ErrorIs(t, err, ErrSigningUnavailable)
+}
+
+func TestPasetoPublicMakerInvalidKey(t *testing.T) {
+	_, err := NewPasetoPublicMaker("not hex")
 	require.Error(t, err)
-	require.EqualError(t, err, ErrExpiredToken.Error())
-	require.Nil(t, payload)
+
+	_, err = NewPasetoPublicMaker("abcd")
+	require.EqualError(t, err, "invalid private key size: must be 32 but got 2")
+
+	_, err = NewPasetoVerifier("abcd")
+	require.EqualError(t, err, "invalid public key size: must be 32 but got 2")
 }
@@ -0,0 +1,135 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/o1egl/paseto"
+)
+
+var ErrSigningUnavailable = errors.New("token maker can only verify tokens")
+
+// PasetoPublicMaker signs v2.public tokens with Ed25519 private keys. Services that
+// only verify tokens build it from the public keys alone with NewPasetoVerifier.
+type PasetoPublicMaker struct {
+	paseto *paseto.V2
+	keys   *KeyRing
+	// verifyOnly is set when the key ring holds public keys instead of private keys
+	verifyOnly bool
+}
+
+func (m *PasetoPublicMaker) CreateToken(username string, role string, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	if m.verifyOnly {
+		return "", nil, ErrSigningUnavailable
+	}
+
+	payload, err := NewPayload(username, role, tokenType, duration)
+	if err != nil {
+		return "", nil, err
+	}
+	keyID, privateKey := m.keys.active()
+	token, err := m.paseto.Sign(ed25519.PrivateKey(privateKey), payload, keyFooter{KeyID: keyID})
+	return token, payload, err
+}
+
+func (m *PasetoPublicMaker) VerifyToken(token string) (*Payload, error) {
+	payload := &Payload{}
+
+	var footer keyFooter
+	if err := paseto.ParseFooter(token, &footer); err != nil {
+		return nil, ErrInvalidToken
+	}
+	key, err := m.keys.key(footer.KeyID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	publicKey := ed25519.PublicKey(key)
+	if !m.verifyOnly {
+		publicKey = ed25519.PrivateKey(key).Public().(ed25519.PublicKey)
+	}
+
+	err = m.paseto.Verify(token, publicKey, payload, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	err = payload.Valid()
+	if err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// NewPasetoPublicMaker takes the hex encoded 32 byte Ed25519 seed of the signing key.
+func NewPasetoPublicMaker(privateKeyHex string) (Maker, error) {
+	if _, err := decodeEd25519PrivateKey(privateKeyHex); err != nil {
+		return nil, err
+	}
+	return NewPasetoPublicMakerWithKeyRing(NewKeyRing(StaticKeySource{
+		ActiveKeyID: DefaultKeyID,
+		Keys:        map[string]string{DefaultKeyID: privateKeyHex},
+	}))
+}
+
+// NewPasetoPublicMakerWithKeyRing signs with the hex encoded Ed25519 seeds of the ring.
+func NewPasetoPublicMakerWithKeyRing(keys *KeyRing) (Maker, error) {
+	if err := keys.use(decodeEd25519PrivateKey); err != nil {
+		return nil, err
+	}
+	return &PasetoPublicMaker{paseto: paseto.NewV2(), keys: keys}, nil
+}
+
+// NewPasetoVerifier takes the hex encoded Ed25519 public key. The maker it returns
+// verifies tokens but CreateToken fails with ErrSigningUnavailable.
+func NewPasetoVerifier(publicKeyHex string) (Maker, error) {
+	if _, err := decodeEd25519PublicKey(publicKeyHex); err != nil {
+		return nil, err
+	}
+	return NewPasetoVerifierWithKeyRing(NewKeyRing(StaticKeySource{
+		ActiveKeyID: DefaultKeyID,
+		Keys:        map[string]string{DefaultKeyID: publicKeyHex},
+	}))
+}
+
+// NewPasetoVerifierWithKeyRing verifies with the hex encoded Ed25519 public keys of the ring,
+// they must use the same key ids as the private keys of the signing maker.
+func NewPasetoVerifierWithKeyRing(keys *KeyRing) (Maker, error) {
+	if err := keys.use(decodeEd25519PublicKey); err != nil {
+		return nil, err
+	}
+	return &PasetoPublicMaker{paseto: paseto.NewV2(), keys: keys, verifyOnly: true}, nil
+}
+
+// GenerateEd25519Keys returns a new hex encoded signing seed and its public key.
+func GenerateEd25519Keys() (privateKeyHex string, publicKeyHex string, err error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(privateKey.Seed()), hex.EncodeToString(publicKey), nil
+}
+
+func decodeEd25519PrivateKey(privateKeyHex string) ([]byte, error) {
+	seed, err := hex.DecodeString(privateKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid private key size: must be %d but got %d", ed25519.SeedSize, len(seed))
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+func decodeEd25519PublicKey(publicKeyHex string) ([]byte, error) {
+	publicKey, err := hex.DecodeString(publicKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key size: must be %d but got %d", ed25519.PublicKeySize, len(publicKey))
+	}
+	return publicKey, nil
+}
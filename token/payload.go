@@ -7,15 +7,27 @@ import (
 	"github.com/google/uuid"
 )
 
+// TokenType tells what a token may be used for, access tokens authenticate requests
+// and refresh tokens only buy new access tokens.
+type TokenType string
+
+const (
+	AccessToken  TokenType = "access"
+	RefreshToken TokenType = "refresh"
+)
+
 type Payload struct {
 	ID        uuid.UUID
 	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	Type      TokenType `json:"type"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
 var ErrExpiredToken = errors.New("token has expired")
 var ErrInvalidToken = errors.New("invalid token")
+var ErrWrongTokenType = errors.New("wrong token type")
 
 func (p *Payload) Valid() error {
 	if time.Now().After(p.ExpiredAt) {
@@ -24,7 +36,7 @@ func (p *Payload) Valid() error {
 	return nil
 }
 
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+func NewPayload(username string, role string, tokenType TokenType, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -32,6 +44,8 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	payload := &Payload{
 		ID:        tokenID,
 		Username:  username,
+		Role:      role,
+		Type:      tokenType,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
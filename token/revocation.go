@@ -0,0 +1,104 @@
+package token
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const revocationSweepInterval = time.Minute
+
+// RevocationStore remembers revoked tokens until they would have expired anyway.
+type RevocationStore interface {
+	// RevokeToken rejects the token carrying this payload.
+	RevokeToken(ctx context.Context, payload *Payload) error
+	// RevokeUser rejects every token issued to username up to now. ttl must be at
+	// least the longest token lifetime so no token outlives the revocation.
+	RevokeUser(ctx context.Context, username string, ttl time.Duration) error
+	IsRevoked(ctx context.Context, payload *Payload) (bool, error)
+}
+
+type userRevocation struct {
+	revokedBefore time.Time
+	expiresAt     time.Time
+}
+
+// MemoryRevocationStore keeps revocations in process memory, which is only enough
+// when a single node serves the api. Expired entries are evicted as new ones come in.
+type MemoryRevocationStore struct {
+	mu        sync.Mutex
+	tokens    map[uuid.UUID]time.Time
+	users     map[string]userRevocation
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		tokens: make(map[uuid.UUID]time.Time),
+		users:  make(map[string]userRevocation),
+		now:    time.Now,
+	}
+}
+
+func (s *MemoryRevocationStore) RevokeToken(ctx context.Context, payload *Payload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+	s.tokens[payload.ID] = payload.ExpiredAt
+	return nil
+}
+
+func (s *MemoryRevocationStore) RevokeUser(ctx context.Context, username string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+	now := s.now()
+	s.users[username] = userRevocation{revokedBefore: now, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (s *MemoryRevocationStore) IsRevoked(ctx context.Context, payload *Payload) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if expiresAt, ok := s.tokens[payload.ID]; ok && now.Before(expiresAt) {
+		return true, nil
+	}
+	if revocation, ok := s.users[payload.Username]; ok && now.Before(revocation.expiresAt) {
+		return !payload.IssuedAt.After(revocation.revokedBefore), nil
+	}
+	return false, nil
+}
+
+// Len returns how many revocations are held, expired ones not yet evicted included.
+func (s *MemoryRevocationStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.tokens) + len(s.users)
+}
+
+// sweep drops expired revocations at most once per revocationSweepInterval, s.mu must be held.
+func (s *MemoryRevocationStore) sweep() {
+	now := s.now()
+	if now.Sub(s.lastSweep) < revocationSweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for id, expiresAt := range s.tokens {
+		if !now.Before(expiresAt) {
+			delete(s.tokens, id)
+		}
+	}
+	for username, revocation := range s.users {
+		if !now.Before(revocation.expiresAt) {
+			delete(s.users, username)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package token
+
+import (
+	"fmt"
+
+	"github.com/RahilRehan/banco/db/util"
+)
+
+const (
+	RevocationStoreMemory   = "memory"
+	RevocationStorePostgres = "postgres"
+)
+
+// NewRevocationStore builds the RevocationStore selected by REVOCATION_STORE,
+// the in-memory one when it is not set.
+func NewRevocationStore(cfg util.Config, querier revocationQuerier) (RevocationStore, error) {
+	switch cfg.REVOCATION_STORE {
+	case "", RevocationStoreMemory:
+		return NewMemoryRevocationStore(), nil
+	case RevocationStorePostgres:
+		return NewSQLRevocationStore(querier), nil
+	}
+	return nil, fmt.Errorf("unsupported revocation store %q", cfg.REVOCATION_STORE)
+}
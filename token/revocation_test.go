@@ -0,0 +1,84 @@
+package token
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RahilRehan/banco/db/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRevocationStoreRevokeToken(t *testing.T) {
+	store := NewMemoryRevocationStore()
+
+	revoked, err := NewPayload(util.RandomOwner(), util.CustomerRole, AccessToken, time.Minute)
+	require.NoError(t, err)
+	other, err := NewPayload(revoked.Username, util.CustomerRole, AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	require.NoError(t, store.RevokeToken(context.Background(), revoked))
+
+	isRevoked, err := store.IsRevoked(context.Background(), revoked)
+	require.NoError(t, err)
+	require.True(t, isRevoked)
+
+	isRevoked, err = store.IsRevoked(context.Background(), other)
+	require.NoError(t, err)
+	require.False(t, isRevoked)
+}
+
+func TestMemoryRevocationStoreRevokeUser(t *testing.T) {
+	store := NewMemoryRevocationStore()
+	username := util.RandomOwner()
+
+	before, err := NewPayload(username, util.CustomerRole, AccessToken, time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, store.RevokeUser(context.Background(), username, time.Minute))
+	after, err := NewPayload(username, util.CustomerRole, AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	isRevoked, err := store.IsRevoked(context.Background(), before)
+	require.NoError(t, err)
+	require.True(t, isRevoked)
+
+	isRevoked, err = store.IsRevoked(context.Background(), after)
+	require.NoError(t, err)
+	require.False(t, isRevoked)
+}
+
+func TestMemoryRevocationStoreEviction(t *testing.T) {
+	store := NewMemoryRevocationStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	payload, err := NewPayload(util.RandomOwner(), util.CustomerRole, AccessToken, time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, store.RevokeToken(context.Background(), payload))
+	require.NoError(t, store.RevokeUser(context.Background(), payload.Username, time.Minute))
+	require.Equal(t, 2, store.Len())
+
+	now = now.Add(2 * time.Minute)
+
+	isRevoked, err := store.IsRevoked(context.Background(), payload)
+	require.NoError(t, err)
+	require.False(t, isRevoked)
+
+	fresh, err := NewPayload(util.RandomOwner(), util.CustomerRole, AccessToken, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, store.RevokeToken(context.Background(), fresh))
+	require.Equal(t, 1, store.Len())
+}
+
+func TestNewRevocationStore(t *testing.T) {
+	store, err := NewRevocationStore(util.Config{}, nil)
+	require.NoError(t, err)
+	require.IsType(t, &MemoryRevocationStore{}, store)
+
+	store, err = NewRevocationStore(util.Config{REVOCATION_STORE: RevocationStorePostgres}, nil)
+	require.NoError(t, err)
+	require.IsType(t, &SQLRevocationStore{}, store)
+
+	_, err = NewRevocationStore(util.Config{REVOCATION_STORE: "redis"}, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package token
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+)
+
+type revocationQuerier interface {
+	CreateRevokedToken(ctx context.Context, arg db.CreateRevokedTokenParams) error
+	UpsertUserRevocation(ctx context.Context, arg db.UpsertUserRevocationParams) error
+	IsTokenRevoked(ctx context.Context, arg db.IsTokenRevokedParams) (bool, error)
+	DeleteExpiredRevokedTokens(ctx context.Context) (int64, error)
+	DeleteExpiredUserRevocations(ctx context.Context) (int64, error)
+}
+
+// SQLRevocationStore keeps revocations in the revoked_tokens and user_revocations
+// tables so every node behind a load balancer sees them.
+type SQLRevocationStore struct {
+	querier revocationQuerier
+
+	mu        sync.Mutex
+	lastPurge time.Time
+}
+
+func NewSQLRevocationStore(querier revocationQuerier) *SQLRevocationStore {
+	return &SQLRevocationStore{querier: querier}
+}
+
+func (s *SQLRevocationStore) RevokeToken(ctx context.Context, payload *Payload) error {
+	s.purgeExpired(ctx)
+	return s.querier.CreateRevokedToken(ctx, db.CreateRevokedTokenParams{
+		ID:        payload.ID,
+		Username:  payload.Username,
+		ExpiresAt: payload.ExpiredAt,
+	})
+}
+
+func (s *SQLRevocationStore) RevokeUser(ctx context.Context, username string, ttl time.Duration) error {
+	s.purgeExpired(ctx)
+	now := time.Now()
+	return s.querier.UpsertUserRevocation(ctx, db.UpsertUserRevocationParams{
+		Username:      username,
+		RevokedBefore: now,
+		ExpiresAt:     now.Add(ttl),
+	})
+}
+
+func (s *SQLRevocationStore) IsRevoked(ctx context.Context, payload *Payload) (bool, error) {
+	return s.querier.IsTokenRevoked(ctx, db.IsTokenRevokedParams{
+		ID:       payload.ID,
+		Username: payload.Username,
+		IssuedAt: payload.IssuedAt,
+	})
+}
+
+// Purge deletes revocations of tokens that have expired anyway.
+func (s *SQLRevocationStore) Purge(ctx context.Context) (int64, error) {
+	tokens, err := s.querier.DeleteExpiredRevokedTokens(ctx)
+	if err != nil {
+		return 0, err
+	}
+	users, err := s.querier.DeleteExpiredUserRevocations(ctx)
+	return tokens + users, err
+}
+
+// purgeExpired runs Purge at most once per revocationSweepInterval. A failed purge
+// is retried next time and doesn't fail the revocation.
+func (s *SQLRevocationStore) purgeExpired(ctx context.Context) {
+	s.mu.Lock()
+	if time.Since(s.lastPurge) < revocationSweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPurge = time.Now()
+	s.mu.Unlock()
+
+	if _, err := s.Purge(ctx); err != nil {
+		s.mu.Lock()
+		s.lastPurge = time.Time{}
+		s.mu.Unlock()
+	}
+}
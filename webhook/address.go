@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var ErrForbiddenAddress = errors.New("webhook address is not allowed")
+
+// blockedNetworks are not covered by the net.IP helpers used in ForbiddenIP.
+var blockedNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// Resolver looks up the addresses of a host, net.DefaultResolver is one.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// ForbiddenIP tells whether ip points into the bank's own network: loopback, private,
+// link-local (which holds the cloud metadata endpoint 169.254.169.254), unspecified
+// or multicast addresses are never sent webhooks.
+func ForbiddenIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckURL refuses a webhook url that is not http or https or whose host resolves to a
+// forbidden address. The host may point elsewhere by the time a delivery is sent, the
+// client of NewClient checks the address again when it connects.
+func CheckURL(ctx context.Context, resolver Resolver, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("webhook url %q must be http or https", rawURL)
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if ForbiddenIP(ip) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+		}
+		return nil
+	}
+
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("cannot resolve webhook host %q: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if ForbiddenIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenAddress, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// NewClient returns the http client deliveries are sent with. It refuses to connect to
+// forbidden addresses, so a host re-pointed after CheckURL (DNS rebinding) or a redirect
+// can't reach the internal network. Proxies are not used, they would hide the address.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: dialControl,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// dialControl runs after the address is resolved and before the connection is made.
+func dialControl(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ForbiddenIP(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type staticResolver map[string][]net.IPAddr
+
+func (r staticResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func TestForbiddenIP(t *testing.T) {
+	testCases := map[string]bool{
+		"127.0.0.1":       true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.1.1":     true,
+		"169.254.169.254": true,
+		"0.0.0.0":         true,
+		"100.64.0.1":      true,
+		"224.0.0.1":       true,
+		"::1":             true,
+		"fe80::1":         true,
+		"fd00:ec2::254":   true,
+		"93.184.216.34":   false,
+		"2606:4700::1111": false,
+	}
+
+	for address, forbidden := range testCases {
+		t.Run(address, func(t *testing.T) {
+			require.Equal(t, forbidden, ForbiddenIP(net.ParseIP(address)))
+		})
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	resolver := staticResolver{
+		"example.com":          {{IP: net.ParseIP("93.184.216.34")}},
+		"internal.example.com": {{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("192.168.0.10")}},
+	}
+
+	testCases := map[string]struct {
+		url       string
+		forbidden bool
+		ok        bool
+	}{
+		"Public host":             {url: "https://example.com/hooks", ok: true},
+		"Public ip":               {url: "http://93.184.216.34/hooks", ok: true},
+		"Unsupported scheme":      {url: "ftp://example.com/hooks"},
+		"No host":                 {url: "https:///hooks"},
+		"Unknown host":            {url: "https://unknown.example.com/hooks"},
+		"Loopback ip":             {url: "http://127.0.0.1:8080/hooks", forbidden: true},
+		"Metadata ip":             {url: "http://169.254.169.254/latest/meta-data", forbidden: true},
+		"Ipv6 loopback":           {url: "http://[::1]/hooks", forbidden: true},
+		"One private address":     {url: "https://internal.example.com/hooks", forbidden: true},
+		"Localhost without a dns": {url: "http://localhost/hooks"},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := CheckURL(context.Background(), resolver, test.url)
+			if test.ok {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, test.forbidden, errors.Is(err, ErrForbiddenAddress))
+		})
+	}
+}
+
+func TestNewClientRefusesForbiddenAddresses(t *testing.T) {
+	var called bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	// the receiver listens on loopback, as a rebound host would point there
+	rsp, err := NewClient(time.Second).Post(receiver.URL, "application/json", nil)
+	if rsp != nil {
+		rsp.Body.Close()
+	}
+	require.ErrorIs(t, err, ErrForbiddenAddress)
+	require.False(t, called)
+}
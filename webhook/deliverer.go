@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+)
+
+const (
+	defaultInterval    = time.Second
+	defaultBatchSize   = 50
+	defaultMaxAttempts = 8
+	deliveryTimeout    = 5 * time.Second
+	retryBaseDelay     = 10 * time.Second
+	maxRetryDelay      = time.Hour
+	leaseMargin        = time.Minute
+)
+
+// event types a subscription can be registered for. account.created is left out, a
+// subscription hangs off an account that already exists when it is created.
+var EventTypes = []string{db.EventTransferCompleted}
+
+// Sender is the part of db.Store the deliverer needs.
+type Sender interface {
+	DeliverWebhooksTx(ctx context.Context, args db.DeliverWebhooksTxParams) (db.DeliverWebhooksTxResult, error)
+}
+
+// Payload is the body POSTed to the receivers.
+type Payload struct {
+	ID        int64           `json:"id"`
+	EventID   int64           `json:"eventId"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Deliverer sends the pending webhook deliveries. A delivery succeeds on any 2xx answer,
+// otherwise it is retried with an exponential backoff until MaxAttempts, after which it
+// is marked failed and can be replayed through the api.
+type Deliverer struct {
+	store       Sender
+	client      *http.Client
+	interval    time.Duration
+	batchSize   int32
+	MaxAttempts int32
+	now         func() time.Time
+}
+
+func NewDeliverer(store Sender, client *http.Client, interval time.Duration) *Deliverer {
+	if client == nil {
+		client = NewClient(deliveryTimeout)
+	}
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Deliverer{
+		store:       store,
+		client:      client,
+		interval:    interval,
+		batchSize:   defaultBatchSize,
+		MaxAttempts: defaultMaxAttempts,
+		now:         time.Now,
+	}
+}
+
+// Run delivers until the context is cancelled.
+func (d *Deliverer) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			result, err := d.DeliverOnce(ctx)
+			if err != nil {
+				log.Println("Cannot deliver webhooks ", err)
+				break
+			}
+			if result.Succeeded+result.Retried+result.Failed < int(d.batchSize) {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverOnce sends one batch of due deliveries.
+func (d *Deliverer) DeliverOnce(ctx context.Context) (db.DeliverWebhooksTxResult, error) {
+	return d.store.DeliverWebhooksTx(ctx, db.DeliverWebhooksTxParams{
+		Limit: d.batchSize,
+		// every delivery of the batch may take the whole timeout
+		Lease:       time.Duration(d.batchSize)*deliveryTimeout + leaseMargin,
+		MaxAttempts: d.MaxAttempts,
+		Deliver:     d.deliver,
+		RetryAt:     d.retryAt,
+	})
+}
+
+func (d *Deliverer) deliver(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow) db.WebhookAttempt {
+	start := d.now()
+	statusCode, err := d.post(ctx, delivery)
+	return db.WebhookAttempt{
+		StatusCode: int32(statusCode),
+		Err:        err,
+		Duration:   d.now().Sub(start),
+	}
+}
+
+func (d *Deliverer) post(ctx context.Context, delivery db.ClaimWebhookDeliveriesRow) (int, error) {
+	body, err := json.Marshal(Payload{
+		ID:        delivery.ID,
+		EventID:   delivery.EventID,
+		Type:      delivery.EventType,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, d.now(), body))
+
+	rsp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return rsp.StatusCode, fmt.Errorf("unexpected status %d", rsp.StatusCode)
+	}
+	return rsp.StatusCode, nil
+}
+
+// retryAt doubles the delay with every failed attempt, up to maxRetryDelay.
+func (d *Deliverer) retryAt(delivery db.ClaimWebhookDeliveriesRow) time.Time {
+	delay := maxRetryDelay
+	if delivery.Attempts < 20 {
+		if backoff := retryBaseDelay << uint(delivery.Attempts); backoff < maxRetryDelay {
+			delay = backoff
+		}
+	}
+	return d.now().Add(delay)
+}
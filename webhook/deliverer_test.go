@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDeliveries sends its deliveries the way db.Store does, without the locking.
+type fakeDeliveries struct {
+	deliveries []db.ClaimWebhookDeliveriesRow
+	attempts   []db.WebhookAttempt
+}
+
+func (s *fakeDeliveries) DeliverWebhooksTx(ctx context.Context, args db.DeliverWebhooksTxParams) (db.DeliverWebhooksTxResult, error) {
+	var result db.DeliverWebhooksTxResult
+	for i := range s.deliveries {
+		delivery := &s.deliveries[i]
+		if delivery.Status != db.WebhookDeliveryStatusPending {
+			continue
+		}
+		attempt := args.Deliver(ctx, *delivery)
+		s.attempts = append(s.attempts, attempt)
+		switch {
+		case attempt.Err == nil:
+			delivery.Status = db.WebhookDeliveryStatusSucceeded
+			result.Succeeded++
+		case delivery.Attempts+1 >= args.MaxAttempts:
+			delivery.Status = db.WebhookDeliveryStatusFailed
+			result.Failed++
+		default:
+			delivery.NextAttemptAt = args.RetryAt(*delivery)
+			result.Retried++
+		}
+		delivery.Attempts++
+		delivery.LastStatusCode = attempt.StatusCode
+	}
+	return result, nil
+}
+
+func (s *fakeDeliveries) EnqueueWebhookDeliveries(ctx context.Context, arg db.EnqueueWebhookDeliveriesParams) (int64, error) {
+	for _, accountID := range arg.AccountIds {
+		s.deliveries = append(s.deliveries, db.ClaimWebhookDeliveriesRow{
+			ID:             int64(len(s.deliveries) + 1),
+			SubscriptionID: accountID,
+			EventID:        arg.EventID,
+			EventType:      arg.EventType,
+			Payload:        arg.Payload,
+			Status:         db.WebhookDeliveryStatusPending,
+		})
+	}
+	return int64(len(arg.AccountIds)), nil
+}
+
+func TestDeliverer(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+
+	var failures int
+	received := make(chan Payload, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		if err := Verify(secret, r.Header.Get(SignatureHeader), body, time.Minute, time.Now()); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var payload Payload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		require.Equal(t, payload.Type, r.Header.Get(EventHeader))
+		require.Equal(t, strconv.FormatInt(payload.ID, 10), r.Header.Get(DeliveryHeader))
+		received <- payload
+	}))
+	defer receiver.Close()
+
+	store := &fakeDeliveries{}
+	transfer, err := json.Marshal(db.Transfer{ID: 7, FromAccountID: 1, ToAccountID: 2, Amount: 10})
+	require.NoError(t, err)
+	err = NewFanoutSink(store).Publish(context.Background(), db.OutboxEvent{
+		ID:        3,
+		EventType: db.EventTransferCompleted,
+		Payload:   transfer,
+	})
+	require.NoError(t, err)
+	require.Len(t, store.deliveries, 2)
+	for i := range store.deliveries {
+		store.deliveries[i].Url = receiver.URL
+		store.deliveries[i].Secret = secret
+	}
+	// the second subscription signs with a secret the receiver does not know
+	store.deliveries[1].Secret = "whsec_other"
+
+	deliverer := NewDeliverer(store, receiver.Client(), time.Second)
+	deliverer.MaxAttempts = 2
+	failures = 1
+
+	result, err := deliverer.DeliverOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, db.DeliverWebhooksTxResult{Retried: 2}, result)
+	require.Equal(t, int32(http.StatusServiceUnavailable), store.attempts[0].StatusCode)
+	require.Equal(t, int32(http.StatusUnauthorized), store.attempts[1].StatusCode)
+
+	result, err = deliverer.DeliverOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, db.DeliverWebhooksTxResult{Succeeded: 1, Failed: 1}, result)
+	require.Equal(t, db.WebhookDeliveryStatusSucceeded, store.deliveries[0].Status)
+	require.Equal(t, db.WebhookDeliveryStatusFailed, store.deliveries[1].Status)
+
+	payload := <-received
+	require.Equal(t, int64(3), payload.EventID)
+	require.Equal(t, db.EventTransferCompleted, payload.Type)
+	require.JSONEq(t, string(transfer), string(payload.Data))
+
+	// nothing is pending any more
+	result, err = deliverer.DeliverOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, db.DeliverWebhooksTxResult{}, result)
+}
+
+func TestDelivererRetryAt(t *testing.T) {
+	now := time.Now()
+	deliverer := NewDeliverer(&fakeDeliveries{}, nil, 0)
+	deliverer.now = func() time.Time { return now }
+
+	testCases := []struct {
+		attempts int32
+		delay    time.Duration
+	}{
+		{0, 10 * time.Second},
+		{1, 20 * time.Second},
+		{3, 80 * time.Second},
+		{9, maxRetryDelay},
+		{60, maxRetryDelay},
+	}
+	for _, tc := range testCases {
+		retryAt := deliverer.retryAt(db.ClaimWebhookDeliveriesRow{Attempts: tc.attempts})
+		require.Equal(t, now.Add(tc.delay), retryAt)
+	}
+}
+
+func TestFanoutSinkSkipsUnrelatedEvents(t *testing.T) {
+	store := &fakeDeliveries{}
+	err := NewFanoutSink(store).Publish(context.Background(), db.OutboxEvent{
+		ID:        1,
+		EventType: db.EventUserCreated,
+		Payload:   json.RawMessage(`{"username":"someone"}`),
+	})
+	require.NoError(t, err)
+	require.Empty(t, store.deliveries)
+
+	// no subscription can see the creation of its own account
+	err = NewFanoutSink(store).Publish(context.Background(), db.OutboxEvent{
+		ID:        2,
+		EventType: db.EventAccountCreated,
+		Payload:   json.RawMessage(`{"id":1}`),
+	})
+	require.NoError(t, err)
+	require.Empty(t, store.deliveries)
+
+	err = NewFanoutSink(store).Publish(context.Background(), db.OutboxEvent{
+		ID:        3,
+		EventType: db.EventTransferCompleted,
+		Payload:   json.RawMessage(`not json`),
+	})
+	require.Error(t, err)
+}
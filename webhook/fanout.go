@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	db "github.com/RahilRehan/banco/db/sqlc"
+)
+
+// Enqueuer is the part of db.Store the fanout sink needs.
+type Enqueuer interface {
+	EnqueueWebhookDeliveries(ctx context.Context, arg db.EnqueueWebhookDeliveriesParams) (int64, error)
+}
+
+// FanoutSink is an outbox sink that turns every event into one pending delivery per
+// matching subscription. It does not call the receivers itself, a slow receiver would
+// hold up the outbox, the Deliverer sends the deliveries on its own schedule.
+type FanoutSink struct {
+	store Enqueuer
+}
+
+func NewFanoutSink(store Enqueuer) *FanoutSink {
+	return &FanoutSink{store: store}
+}
+
+func (s *FanoutSink) Name() string {
+	return "webhooks"
+}
+
+// Publish enqueues the deliveries of an event. It is safe to call again for the same
+// event, a subscription gets at most one delivery per event.
+func (s *FanoutSink) Publish(ctx context.Context, event db.OutboxEvent) error {
+	accountIDs, err := accountIDs(event)
+	if err != nil {
+		return err
+	}
+	if len(accountIDs) == 0 {
+		return nil
+	}
+
+	_, err = s.store.EnqueueWebhookDeliveries(ctx, db.EnqueueWebhookDeliveriesParams{
+		EventID:    event.ID,
+		EventType:  event.EventType,
+		Payload:    event.Payload,
+		AccountIds: accountIDs,
+	})
+	return err
+}
+
+// accountIDs returns the accounts whose subscriptions see an event, a transfer is seen
+// by both of its accounts.
+func accountIDs(event db.OutboxEvent) ([]int64, error) {
+	switch event.EventType {
+	case db.EventTransferCompleted:
+		var transfer db.Transfer
+		if err := json.Unmarshal(event.Payload, &transfer); err != nil {
+			return nil, err
+		}
+		return []int64{transfer.FromAccountID, transfer.ToAccountID}, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// headers set on every delivery
+const (
+	SignatureHeader = "Banco-Signature"
+	EventHeader     = "Banco-Event"
+	DeliveryHeader  = "Banco-Delivery"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrSignatureExpired = errors.New("webhook signature expired")
+)
+
+// NewSecret returns a random signing secret for a subscription.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign returns the signature header of a body sent at timestamp, in the form
+// t=<unix seconds>,v1=<hex hmac-sha256 of "<t>.<body>">. Signing the timestamp lets a
+// receiver refuse replayed requests.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", t, computeMAC(secret, t, body))
+}
+
+// Verify checks a signature header against the body, refusing signatures older than
+// tolerance. A tolerance of 0 skips the age check.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var t, mac string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			t = kv[1]
+		case "v1":
+			mac = kv[1]
+		}
+	}
+	if t == "" || mac == "" {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(mac), []byte(computeMAC(secret, t, body))) {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 {
+		unix, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		if now.Sub(time.Unix(unix, 0)) > tolerance {
+			return ErrSignatureExpired
+		}
+	}
+	return nil
+}
+
+func computeMAC(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
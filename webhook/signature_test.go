@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, len("whsec_")+64)
+
+	body := []byte(`{"id":1}`)
+	now := time.Now()
+	header := Sign(secret, now, body)
+
+	testCases := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		err    error
+	}{
+		{"OK", secret, header, body, now, nil},
+		{"WrongSecret", "whsec_other", header, body, now, ErrInvalidSignature},
+		{"TamperedBody", secret, header, []byte(`{"id":2}`), now, ErrInvalidSignature},
+		{"MissingHeader", secret, "", body, now, ErrInvalidSignature},
+		{"Expired", secret, header, body, now.Add(10 * time.Minute), ErrSignatureExpired},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Verify(tc.secret, tc.header, tc.body, 5*time.Minute, tc.now)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}